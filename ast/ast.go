@@ -103,10 +103,17 @@ func (ps *PrintStatement) String() string {
 	return sb.String()
 }
 
+// VarStatement is e.g. "var x int = 2", or a short variable declaration e.g.
+// "x := 2". A name without a type is given the type of its value.
 type VarStatement struct {
-	Token token.Token // The token.Var token.
-	Name  *Identifier
-	Value Expression
+	Token  token.Token // The token.Var or token.Define token.
+	Names  []*Identifier
+	Values []Expression
+
+	// Redeclared is set for each name of a short variable declaration, which
+	// is already declared in the scope, so the name is only assigned its
+	// value.
+	Redeclared []bool
 }
 
 func (vs *VarStatement) statementNode()       {}
@@ -114,22 +121,109 @@ func (vs *VarStatement) TokenLiteral() string { return vs.Token.Literal }
 func (vs *VarStatement) String() string {
 	var sb strings.Builder
 
+	if vs.Token.Type == token.Define {
+		writeExpressions(&sb, identifiers(vs.Names))
+		sb.WriteString(" := ")
+		writeExpressions(&sb, vs.Values)
+
+		return sb.String()
+	}
+
 	sb.WriteString(vs.TokenLiteral())
 	sb.WriteString(" ")
-	sb.WriteString(vs.Name.String())
+	for i, n := range vs.Names {
+		sb.WriteString(n.String())
+
+		if i == len(vs.Names)-1 {
+			break
+		}
+
+		sb.WriteString(", ")
+	}
 
-	if vs.Value != nil {
+	if len(vs.Values) != 0 {
 		sb.WriteString(" = ")
-		sb.WriteString(vs.Value.String())
+		writeExpressions(&sb, vs.Values)
+	}
+
+	return sb.String()
+}
+
+// IsRedeclared reports whether the i'th name is redeclared by a short variable
+// declaration.
+func (vs *VarStatement) IsRedeclared(i int) bool {
+	return vs.Redeclared != nil && vs.Redeclared[i]
+}
+
+// ConstStatement declares constants, where a grouped declaration holds a spec
+// for each line of the group.
+type ConstStatement struct {
+	Token token.Token // The token.Const token.
+	Specs []*ConstSpec
+}
+
+func (cs *ConstStatement) statementNode()       {}
+func (cs *ConstStatement) TokenLiteral() string { return cs.Token.Literal }
+func (cs *ConstStatement) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(cs.TokenLiteral())
+	sb.WriteString(" ")
+
+	if len(cs.Specs) == 1 {
+		sb.WriteString(cs.Specs[0].String())
+		return sb.String()
+	}
+
+	sb.WriteString("(")
+	for i, s := range cs.Specs {
+		sb.WriteString(s.String())
+
+		if i == len(cs.Specs)-1 {
+			break
+		}
+
+		sb.WriteString("; ")
+	}
+	sb.WriteString(")")
+
+	return sb.String()
+}
+
+// ConstSpec is a line of a constant declaration. A spec without values repeats
+// the type and the values of the previous spec in the group.
+type ConstSpec struct {
+	Names  []*Identifier
+	Tnode  TypeNode // The type of the constants or nil if they are untyped.
+	Values []Expression
+	Iota   int // The value of iota, which is the index of the spec.
+}
+
+func (cs *ConstSpec) String() string {
+	var sb strings.Builder
+
+	writeExpressions(&sb, identifiers(cs.Names))
+
+	if cs.Tnode != nil {
+		sb.WriteString(" ")
+		sb.WriteString(cs.Tnode.String())
+	}
+
+	if len(cs.Values) != 0 {
+		sb.WriteString(" = ")
+		writeExpressions(&sb, cs.Values)
 	}
 
 	return sb.String()
 }
 
 type AssignStatement struct {
-	Token token.Token // The token.Assign token.
-	Name  Expression
-	Value Expression
+	Token  token.Token // The token.Assign token or an assignment operator.
+	Names  []Expression
+	Values []Expression
+	// Operator is the operator of a compound assignment e.g. "+" of
+	// "x += 2", which is empty for a plain assignment.
+	Operator string
 }
 
 func (as *AssignStatement) statementNode()       {}
@@ -137,15 +231,27 @@ func (as *AssignStatement) TokenLiteral() string { return as.Token.Literal }
 func (as *AssignStatement) String() string {
 	var sb strings.Builder
 
-	sb.WriteString(as.Name.String())
+	writeExpressions(&sb, as.Names)
 	sb.WriteString(" ")
 	sb.WriteString(as.TokenLiteral())
 	sb.WriteString(" ")
-	sb.WriteString(as.Value.String())
+	writeExpressions(&sb, as.Values)
 
 	return sb.String()
 }
 
+// IncDecStatement is e.g. "i++" or "i--".
+type IncDecStatement struct {
+	Token token.Token // The token.Increment or token.Decrement token.
+	Name  Expression
+}
+
+func (ids *IncDecStatement) statementNode()       {}
+func (ids *IncDecStatement) TokenLiteral() string { return ids.Token.Literal }
+func (ids *IncDecStatement) String() string {
+	return ids.Name.String() + ids.TokenLiteral()
+}
+
 type TypeStatement struct {
 	Token token.Token // The token.Type token.
 	Name  *Identifier
@@ -192,7 +298,9 @@ type IfStatement struct {
 	Token       token.Token // The token.If token.
 	Condition   Expression
 	Consequence *BlockStatement
-	Alternative *BlockStatement
+	// Alternative is either a *BlockStatement or an *IfStatement for an
+	// "else if", and nil if there is no else.
+	Alternative Statement
 }
 
 func (ifs *IfStatement) statementNode()       {}
@@ -213,8 +321,12 @@ func (ifs *IfStatement) String() string {
 	return sb.String()
 }
 
+// ForStatement is e.g. "for var i int = 0; i < 10; i = i + 1 { ... }". Each of
+// the init, the condition and the next is nil if it is omitted, and a loop
+// without a condition loops forever.
 type ForStatement struct {
 	Token     token.Token // The token.For token.
+	Label     *Identifier // The label of the loop, which is nil if there is none.
 	Init      Statement
 	Condition Expression
 	Next      Statement
@@ -228,20 +340,133 @@ func (fs *ForStatement) TokenLiteral() string { return fs.Token.Literal }
 func (fs *ForStatement) String() string {
 	var sb strings.Builder
 
+	if fs.Label != nil {
+		sb.WriteString(fs.Label.String())
+		sb.WriteString(": ")
+	}
+
 	sb.WriteString("for")
 	sb.WriteString(" ")
-	sb.WriteString(fs.Init.String())
-	sb.WriteString("; ")
-	sb.WriteString(fs.Condition.String())
-	sb.WriteString("; ")
-	sb.WriteString(fs.Next.String())
+
+	switch {
+	case fs.Init == nil && fs.Next == nil && fs.Condition == nil:
+	case fs.Init == nil && fs.Next == nil:
+		sb.WriteString(fs.Condition.String())
+		sb.WriteString(" ")
+	default:
+		if fs.Init != nil {
+			sb.WriteString(fs.Init.String())
+		}
+		sb.WriteString("; ")
+		if fs.Condition != nil {
+			sb.WriteString(fs.Condition.String())
+		}
+		sb.WriteString("; ")
+		if fs.Next != nil {
+			sb.WriteString(fs.Next.String())
+		}
+	}
+
 	sb.WriteString(fs.Body.String())
 
 	return sb.String()
 }
 
+// SwitchStatement is e.g. "switch x { case 1, 2: ...; default: ... }". A
+// switch without a tag switches on the first case which is true.
+type SwitchStatement struct {
+	Token token.Token // The token.Switch token.
+	Label *Identifier // The label of the switch, which is nil if there is none.
+	Tag   Expression  // The tag of the switch, which is nil if there is none.
+	Cases []*CaseClause
+}
+
+func (ss *SwitchStatement) statementNode()       {}
+func (ss *SwitchStatement) TokenLiteral() string { return ss.Token.Literal }
+func (ss *SwitchStatement) String() string {
+	var sb strings.Builder
+
+	if ss.Label != nil {
+		sb.WriteString(ss.Label.String())
+		sb.WriteString(": ")
+	}
+
+	sb.WriteString("switch ")
+	if ss.Tag != nil {
+		sb.WriteString(ss.Tag.String())
+		sb.WriteString(" ")
+	}
+
+	sb.WriteString("{")
+	for i, c := range ss.Cases {
+		sb.WriteString(c.String())
+
+		if i == len(ss.Cases)-1 {
+			break
+		}
+
+		sb.WriteString("; ")
+	}
+	sb.WriteString("}")
+
+	return sb.String()
+}
+
+// CaseClause is a case of a switch statement, or the default case if it has no
+// values. The body of a case is a block of its own.
+type CaseClause struct {
+	Token  token.Token // The token.Case or token.Default token.
+	Values []Expression
+	Body   *BlockStatement
+}
+
+func (cc *CaseClause) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(cc.Token.Literal)
+	if len(cc.Values) != 0 {
+		sb.WriteString(" ")
+		writeExpressions(&sb, cc.Values)
+	}
+	sb.WriteString(":")
+
+	for i, s := range cc.Body.Statements {
+		sb.WriteString(" ")
+		sb.WriteString(s.String())
+
+		if i == len(cc.Body.Statements)-1 {
+			break
+		}
+
+		sb.WriteString(";")
+	}
+
+	return sb.String()
+}
+
+// BranchStatement is a break or a continue statement, which may name the
+// label of the statement it leaves or continues.
+type BranchStatement struct {
+	Token token.Token // The token.Break or token.Continue token.
+	Label *Identifier // The label of the statement, which is nil if there is none.
+	// Target is the loop or the switch the statement leaves, or the loop it
+	// continues, which is given by the checker.
+	Target Statement
+}
+
+func (bs *BranchStatement) statementNode()       {}
+func (bs *BranchStatement) TokenLiteral() string { return bs.Token.Literal }
+func (bs *BranchStatement) String() string {
+	if bs.Label == nil {
+		return bs.Token.Literal
+	}
+
+	return bs.Token.Literal + " " + bs.Label.String()
+}
+
 type FuncStatement struct {
 	Token     token.Token // The token.Func token.
+	Receiver  *Identifier // The receiver of a method, which is nil for a function.
 	Name      *Identifier
 	Signature *FuncType
 	Body      *BlockStatement
@@ -256,6 +481,13 @@ func (fs *FuncStatement) String() string {
 
 	sb.WriteString("func")
 	sb.WriteString(" ")
+	if fs.Receiver != nil {
+		sb.WriteString("(")
+		sb.WriteString(fs.Receiver.String())
+		sb.WriteString(" ")
+		sb.WriteString(fs.Receiver.Tnode.String())
+		sb.WriteString(") ")
+	}
 	sb.WriteString(fs.Name.String())
 	sb.WriteString(fs.Signature.String())
 	if fs.Body != nil {
@@ -265,10 +497,30 @@ func (fs *FuncStatement) String() string {
 	return sb.String()
 }
 
+// Label returns the label of the function. A method is labelled by the name of
+// its receiver type and its own name e.g. "human.greet".
+func (fs *FuncStatement) Label() string {
+	if fs.Receiver == nil {
+		return fs.Name.Value
+	}
+
+	tnode := fs.Receiver.Tnode
+	if pt, ok := tnode.(*PointerType); ok {
+		tnode = pt.Elem
+	}
+
+	return MethodLabel(tnode.TokenLiteral(), fs.Name.Value)
+}
+
+// MethodLabel returns the label of the method of the named type.
+func MethodLabel(typeName, method string) string {
+	return typeName + "." + method
+}
+
 type ReturnStatement struct {
-	Token    token.Token // The token.Return token.
-	Value    Expression  // The value to return.
-	Function *Identifier // The function the return resides in.
+	Token    token.Token  // The token.Return token.
+	Values   []Expression // The values to return.
+	Function *Identifier  // The function the return resides in.
 }
 
 func (rs *ReturnStatement) statementNode()       {}
@@ -278,10 +530,7 @@ func (rs *ReturnStatement) String() string {
 
 	sb.WriteString(rs.Token.Literal)
 	sb.WriteString(" ")
-
-	if rs.Value != nil {
-		sb.WriteString(rs.Value.String())
-	}
+	writeExpressions(&sb, rs.Values)
 
 	return sb.String()
 }
@@ -366,6 +615,58 @@ func (bl *BoolLiteral) Type() types.Type     { return bl.T }
 func (bl *BoolLiteral) TokenLiteral() string { return bl.Token.Literal }
 func (bl *BoolLiteral) String() string       { return bl.Token.Literal }
 
+type NilLiteral struct {
+	Token token.Token // The token.Nil token.
+
+	Reg string
+	T   types.Type
+}
+
+func (nl *NilLiteral) expressionNode()      {}
+func (nl *NilLiteral) Register() string     { return nl.Reg }
+func (nl *NilLiteral) Type() types.Type     { return nl.T }
+func (nl *NilLiteral) TokenLiteral() string { return nl.Token.Literal }
+func (nl *NilLiteral) String() string       { return nl.Token.Literal }
+
+// Iota is the untyped integer constant iota, which is only used in a constant
+// declaration.
+type Iota struct {
+	Token token.Token // The token.Iota token.
+
+	Reg string
+	T   types.Type
+}
+
+func (i *Iota) expressionNode()      {}
+func (i *Iota) Register() string     { return i.Reg }
+func (i *Iota) Type() types.Type     { return i.T }
+func (i *Iota) TokenLiteral() string { return i.Token.Literal }
+func (i *Iota) String() string       { return i.Token.Literal }
+
+type PrefixExpression struct {
+	Token    token.Token // The operator token (&, *)
+	Operator string
+	Right    Expression
+
+	Reg string
+	T   types.Type
+}
+
+func (pe *PrefixExpression) expressionNode()      {}
+func (pe *PrefixExpression) Register() string     { return pe.Reg }
+func (pe *PrefixExpression) Type() types.Type     { return pe.T }
+func (pe *PrefixExpression) TokenLiteral() string { return pe.Token.Literal }
+func (pe *PrefixExpression) String() string {
+	var sb strings.Builder
+
+	sb.WriteString("(")
+	sb.WriteString(pe.Operator)
+	sb.WriteString(pe.Right.String())
+	sb.WriteString(")")
+
+	return sb.String()
+}
+
 type InfixExpression struct {
 	Token    token.Token // The operator token (+, -, /, *)
 	Left     Expression
@@ -374,6 +675,9 @@ type InfixExpression struct {
 
 	Reg string
 	T   types.Type
+	// Value is the value of the expression, if it is an untyped constant
+	// which is given another type by its use.
+	Value interface{}
 }
 
 func (ie *InfixExpression) expressionNode()      {}
@@ -401,9 +705,9 @@ func (bt *BasicType) TokenLiteral() string { return bt.Token.Literal }
 func (bt *BasicType) String() string       { return bt.Token.Literal }
 
 type FuncType struct {
-	Token     token.Token // The token.Lparen token.
-	Parameter *Identifier
-	Result    TypeNode
+	Token      token.Token // The token.Lparen token.
+	Parameters []*Identifier
+	Result     TypeNode
 }
 
 func (ft *FuncType) typeNode()            {}
@@ -412,20 +716,93 @@ func (ft *FuncType) String() string {
 	var sb strings.Builder
 
 	sb.WriteString("(")
-	if ft.Parameter != nil {
-		sb.WriteString(ft.Parameter.String())
+	for i, p := range ft.Parameters {
+		sb.WriteString(p.String())
+
+		if i == len(ft.Parameters)-1 {
+			break
+		}
+
+		sb.WriteString(", ")
 	}
 
 	sb.WriteString(")")
 	sb.WriteString(" ")
 	if ft.Result != nil {
-		sb.WriteString(ft.Result.TokenLiteral())
+		sb.WriteString(ft.Result.String())
 		sb.WriteString(" ")
 	}
 
 	return sb.String()
 }
 
+// TupleType is the list of results of a function with multiple results.
+type TupleType struct {
+	Token token.Token // The token.Lparen token.
+	Types []TypeNode
+}
+
+func (tt *TupleType) typeNode()            {}
+func (tt *TupleType) TokenLiteral() string { return tt.Token.Literal }
+func (tt *TupleType) String() string {
+	var sb strings.Builder
+
+	sb.WriteString("(")
+	for i, t := range tt.Types {
+		sb.WriteString(t.String())
+
+		if i == len(tt.Types)-1 {
+			break
+		}
+
+		sb.WriteString(", ")
+	}
+	sb.WriteString(")")
+
+	return sb.String()
+}
+
+type ArrayType struct {
+	Token token.Token // The token.Lbracket token.
+	Len   Expression  // The number of elements.
+	Elem  TypeNode    // The type of the elements.
+}
+
+func (at *ArrayType) typeNode()            {}
+func (at *ArrayType) TokenLiteral() string { return at.Token.Literal }
+func (at *ArrayType) String() string {
+	var sb strings.Builder
+
+	sb.WriteString("[")
+	sb.WriteString(at.Len.String())
+	sb.WriteString("]")
+	sb.WriteString(at.Elem.String())
+
+	return sb.String()
+}
+
+type PointerType struct {
+	Token token.Token // The token.Asterisk token.
+	Elem  TypeNode    // The type pointed to.
+}
+
+func (pt *PointerType) typeNode()            {}
+func (pt *PointerType) TokenLiteral() string { return pt.Token.Literal }
+func (pt *PointerType) String() string {
+	return "*" + pt.Elem.String()
+}
+
+type SliceType struct {
+	Token token.Token // The token.Lbracket token.
+	Elem  TypeNode    // The type of the elements.
+}
+
+func (st *SliceType) typeNode()            {}
+func (st *SliceType) TokenLiteral() string { return st.Token.Literal }
+func (st *SliceType) String() string {
+	return "[]" + st.Elem.String()
+}
+
 type StructType struct {
 	Token  token.Token // The token.Struct token.
 	Fields []*Identifier
@@ -452,6 +829,217 @@ func (st *StructType) String() string {
 	return sb.String()
 }
 
+// InterfaceType is a set of methods, where each method is an identifier with
+// the function type of the method.
+type InterfaceType struct {
+	Token   token.Token // The token.Interface token.
+	Methods []*Identifier
+}
+
+func (it *InterfaceType) typeNode()            {}
+func (it *InterfaceType) TokenLiteral() string { return it.Token.Literal }
+func (it *InterfaceType) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(it.Token.Literal)
+	sb.WriteString("{")
+	for i, m := range it.Methods {
+		sb.WriteString(m.Value)
+		sb.WriteString(strings.TrimSpace(m.Tnode.String()))
+
+		if i == len(it.Methods)-1 {
+			break
+		}
+
+		sb.WriteString(";")
+	}
+	sb.WriteString("}")
+
+	return sb.String()
+}
+
+type IndexExpression struct {
+	Token token.Token // The token.Lbracket token.
+	Left  Expression  // The expression being indexed.
+	Index Expression
+
+	Reg string
+	T   types.Type
+}
+
+func (ie *IndexExpression) expressionNode()      {}
+func (ie *IndexExpression) Register() string     { return ie.Reg }
+func (ie *IndexExpression) Type() types.Type     { return ie.T }
+func (ie *IndexExpression) TokenLiteral() string { return ie.Token.Literal }
+func (ie *IndexExpression) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(ie.Left.String())
+	sb.WriteString("[")
+	sb.WriteString(ie.Index.String())
+	sb.WriteString("]")
+
+	return sb.String()
+}
+
+type SliceExpression struct {
+	Token token.Token // The token.Lbracket token.
+	Left  Expression  // The expression being sliced.
+	Low   Expression  // The low bound, which may be omitted.
+	High  Expression  // The high bound, which may be omitted.
+
+	Reg string
+	T   types.Type
+}
+
+func (se *SliceExpression) expressionNode()      {}
+func (se *SliceExpression) Register() string     { return se.Reg }
+func (se *SliceExpression) Type() types.Type     { return se.T }
+func (se *SliceExpression) TokenLiteral() string { return se.Token.Literal }
+func (se *SliceExpression) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(se.Left.String())
+	sb.WriteString("[")
+	if se.Low != nil {
+		sb.WriteString(se.Low.String())
+	}
+	sb.WriteString(":")
+	if se.High != nil {
+		sb.WriteString(se.High.String())
+	}
+	sb.WriteString("]")
+
+	return sb.String()
+}
+
+// BuiltinExpression is a call to one of the builtin functions e.g. len(s).
+type BuiltinExpression struct {
+	Token     token.Token // The builtin token e.g. token.Len.
+	Tnode     TypeNode    // The type argument of make.
+	Arguments []Expression
+
+	Reg string
+	T   types.Type
+}
+
+func (be *BuiltinExpression) expressionNode()      {}
+func (be *BuiltinExpression) Register() string     { return be.Reg }
+func (be *BuiltinExpression) Type() types.Type     { return be.T }
+func (be *BuiltinExpression) TokenLiteral() string { return be.Token.Literal }
+func (be *BuiltinExpression) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(be.Token.Literal)
+	sb.WriteString("(")
+	if be.Tnode != nil {
+		sb.WriteString(be.Tnode.String())
+		if len(be.Arguments) > 0 {
+			sb.WriteString(", ")
+		}
+	}
+	writeExpressions(&sb, be.Arguments)
+	sb.WriteString(")")
+
+	return sb.String()
+}
+
+// ConversionExpression is a conversion of a value to a basic type e.g.
+// float(x). A conversion to a named type is parsed as a CallExpression, as the
+// name could also be a function.
+type ConversionExpression struct {
+	Token token.Token // The token of the type e.g. token.FloatType.
+	Tnode TypeNode
+	Value Expression
+
+	Reg string
+	T   types.Type
+}
+
+func (ce *ConversionExpression) expressionNode()      {}
+func (ce *ConversionExpression) Register() string     { return ce.Reg }
+func (ce *ConversionExpression) Type() types.Type     { return ce.T }
+func (ce *ConversionExpression) TokenLiteral() string { return ce.Token.Literal }
+func (ce *ConversionExpression) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(ce.Tnode.String())
+	sb.WriteString("(")
+	sb.WriteString(ce.Value.String())
+	sb.WriteString(")")
+
+	return sb.String()
+}
+
+// CompositeLiteral is a struct literal with either keyed values e.g.
+// human{age: 3, name: "Bob"} or positional values e.g. human{3, "Bob"}.
+type CompositeLiteral struct {
+	Token  token.Token // The token.Lbrace token.
+	Tnode  TypeNode
+	Keys   []*Identifier // The field of each value, which is nil for a positional value.
+	Values []Expression
+
+	Reg string
+	T   types.Type
+}
+
+func (cl *CompositeLiteral) expressionNode()      {}
+func (cl *CompositeLiteral) Register() string     { return cl.Reg }
+func (cl *CompositeLiteral) Type() types.Type     { return cl.T }
+func (cl *CompositeLiteral) TokenLiteral() string { return cl.Token.Literal }
+func (cl *CompositeLiteral) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(cl.Tnode.String())
+	sb.WriteString("{")
+	for i, v := range cl.Values {
+		if cl.Keys[i] != nil {
+			sb.WriteString(cl.Keys[i].Value)
+			sb.WriteString(": ")
+		}
+		sb.WriteString(v.String())
+
+		if i == len(cl.Values)-1 {
+			break
+		}
+
+		sb.WriteString(", ")
+	}
+	sb.WriteString("}")
+
+	return sb.String()
+}
+
+// FuncLiteral is an anonymous function e.g. "func(x int) int { return x + n }",
+// which may use the variables of the enclosing functions.
+type FuncLiteral struct {
+	Token     token.Token // The token.Func token.
+	Signature *FuncType
+	Body      *BlockStatement
+	// Label is the label of the code of the function, which is given by the
+	// checker.
+	Label string
+
+	SymbolTable *symbol.Table
+
+	Reg string
+	T   types.Type
+}
+
+func (fl *FuncLiteral) expressionNode()      {}
+func (fl *FuncLiteral) Register() string     { return fl.Reg }
+func (fl *FuncLiteral) Type() types.Type     { return fl.T }
+func (fl *FuncLiteral) TokenLiteral() string { return fl.Token.Literal }
+func (fl *FuncLiteral) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(fl.Token.Literal)
+	sb.WriteString(fl.Signature.String())
+	sb.WriteString(fl.Body.String())
+
+	return sb.String()
+}
+
 type SelectorExpression struct {
 	Token token.Token // The token.Period token.
 	X     Expression  // The lhs of the token.Period.
@@ -460,7 +1048,8 @@ type SelectorExpression struct {
 	Reg string
 	T   types.Type
 
-	Offset int // Offset to the field in X.
+	Offset int           // Offset to the field in X.
+	Method *types.Method // The method selected by a method call.
 }
 
 func (s *SelectorExpression) expressionNode()      {}
@@ -478,9 +1067,9 @@ func (s *SelectorExpression) String() string {
 }
 
 type CallExpression struct {
-	Token    token.Token // The token.Rparen token.
-	Function Expression
-	Argument Expression
+	Token     token.Token // The token.Rparen token.
+	Function  Expression
+	Arguments []Expression
 
 	Reg string
 	T   types.Type
@@ -495,10 +1084,31 @@ func (c *CallExpression) String() string {
 
 	sb.WriteString(c.Function.String())
 	sb.WriteString("(")
-	if c.Argument != nil {
-		sb.WriteString(c.Argument.String())
-	}
+	writeExpressions(&sb, c.Arguments)
 	sb.WriteString(")")
 
 	return sb.String()
 }
+
+// writeExpressions writes the expressions separated by commas.
+// identifiers returns the identifiers as expressions.
+func identifiers(ids []*Identifier) []Expression {
+	exprs := make([]Expression, len(ids))
+	for i, id := range ids {
+		exprs[i] = id
+	}
+
+	return exprs
+}
+
+func writeExpressions(sb *strings.Builder, exprs []Expression) {
+	for i, e := range exprs {
+		sb.WriteString(e.String())
+
+		if i == len(exprs)-1 {
+			break
+		}
+
+		sb.WriteString(", ")
+	}
+}
@@ -13,11 +13,27 @@ import (
 )
 
 func Check(program *ast.Program) error {
+	funcLiterals = 0
+	targets = nil
+	labels = nil
 	return check(program, program.SymbolTable)
 }
 
 var currentFunc *ast.Identifier
 
+// funcLiterals counts the function literals of the program, so each of them is
+// given its own label.
+var funcLiterals int
+
+// targets holds the loops and the switches enclosing the statement being
+// checked, where the innermost is the last. They are the statements a break or
+// a continue can target.
+var targets []ast.Statement
+
+// labels holds the labels of the function being checked, since a label is only
+// declared once in a function.
+var labels map[string]bool
+
 func check(node ast.Node, symbolTable *symbol.Table) error {
 	switch node := node.(type) {
 	case *ast.Program:
@@ -37,120 +53,316 @@ func check(node ast.Node, symbolTable *symbol.Table) error {
 			return err
 		}
 	case *ast.PrintStatement:
-		if err := check(node.Value, symbolTable); err != nil {
+		if err := checkValue(node.Value, symbolTable); err != nil {
 			return err
 		}
 	case *ast.VarStatement:
-		if err := check(node.Name, symbolTable); err != nil {
-			return err
+		for i, n := range node.Names {
+			// A name without a type is given the type of its value, unless
+			// it is redeclared and keeps its type.
+			if n.Tnode == nil && !node.IsRedeclared(i) {
+				continue
+			}
+
+			if err := check(n, symbolTable); err != nil {
+				return err
+			}
 		}
 
-		if node.Value == nil {
+		if len(node.Values) == 0 {
 			break
 		}
 
-		if err := check(node.Value, symbolTable); err != nil {
+		ts, err := checkValues(node.Values, symbolTable)
+		if err != nil {
 			return err
 		}
 
-		if !reflect.DeepEqual(node.Name.T, node.Value.Type()) {
+		if len(node.Names) != len(ts) {
 			return fmt.Errorf(
-				"type error: identifier: %q of type: %s is assigned the wrong type: %s",
-				node.Name.Value,
-				node.Name.T,
-				node.Value.Type(),
+				"type error: assignment mismatch: %d variables but %d values",
+				len(node.Names),
+				len(ts),
 			)
 		}
+
+		for i, n := range node.Names {
+			if n.Tnode == nil && !node.IsRedeclared(i) {
+				t, err := inferType(n, ts[i])
+				if err != nil {
+					return err
+				}
+
+				sym, _ := symbolTable.Resolve(n.Value)
+				sym.Type, n.T = t, t
+			}
+
+			if len(node.Values) == len(ts) {
+				ts[i] = convertUntyped(node.Values[i], n.T, symbolTable)
+			}
+
+			if !assignable(ts[i], n.T) {
+				return fmt.Errorf(
+					"type error: identifier: %q of type: %s is assigned the wrong type: %s",
+					n.Value,
+					n.T,
+					ts[i],
+				)
+			}
+		}
+	case *ast.ConstStatement:
+		if err := checkConst(node, symbolTable); err != nil {
+			return err
+		}
 	case *ast.TypeStatement:
 		if err := check(node.Name, symbolTable); err != nil {
 			return err
 		}
 
+		// A named basic type is checked by its name.
+		if _, ok := node.Type.(*ast.BasicType); ok {
+			break
+		}
+
 		if err := check(node.Type, symbolTable); err != nil {
 			return err
 		}
 	case *ast.StructType:
 		for _, f := range node.Fields {
-			switch t := f.Tnode.(type) {
-			case *ast.BasicType:
-				// In struct only allow for basic types
-				if t.Token.Type == token.Ident {
-					return fmt.Errorf("type error: struct fields can only be a basic type [int, float, bool, string]")
-				}
-				f.T = typeNodetoType(t)
-			default:
-				// TODO: maybe later allow for struct inside structs.
-				panic("StructType containing fields of other types than BasicType is not implemented")
+			typ, err := typeNodetoType(f.Tnode, symbolTable)
+			if err != nil {
+				return err
+			}
+			f.T = typ
+		}
+	case *ast.InterfaceType:
+		for _, m := range node.Methods {
+			signature, err := funcTypeToSignature(m.Tnode.(*ast.FuncType), symbolTable)
+			if err != nil {
+				return err
 			}
+			m.T = signature
 		}
 	case *ast.SelectorExpression:
-		if err := check(node.X, symbolTable); err != nil {
+		if err := checkValue(node.X, symbolTable); err != nil {
 			return err
 		}
 
-		switch v := node.X.(type) {
-		case *ast.Identifier:
-			x, ok := v.T.(*types.Struct)
-			if !ok {
+		if err := selectField(node); err != nil {
+			return err
+		}
+	case *ast.IndexExpression:
+		if err := checkValue(node.Left, symbolTable); err != nil {
+			return err
+		}
+
+		if err := checkValue(node.Index, symbolTable); err != nil {
+			return err
+		}
+
+		if !integer(node.Index.Type()) {
+			return fmt.Errorf(
+				"type error: invalid index %s (type %s), must be integer",
+				node.Index,
+				node.Index.Type(),
+			)
+		}
+
+		// A constant index can be checked already at compile time.
+		var index int64
+		var constIndex bool
+		if v, _, err := constant(node.Index, 0, symbolTable); err == nil {
+			index, constIndex = v.(int64)
+		}
+
+		if constIndex && index < 0 {
+			return fmt.Errorf("type error: invalid index %d (index must be non-negative)", index)
+		}
+
+		switch t := node.Left.Type().(type) {
+		case *types.Array:
+			if constIndex && index >= int64(t.Len) {
 				return fmt.Errorf(
-					"type error: selecting field on identifier: %s, which is not a struct",
-					v,
+					"type error: invalid array index %d (out of bounds for %d-element array)",
+					index,
+					t.Len,
 				)
 			}
-			offset, ok := identifierInStruct(node.Field, x)
-			if !ok {
+
+			node.T = t.Elem
+		case *types.Slice:
+			node.T = t.Elem
+		default:
+			// Indexing a string gives the byte at the index.
+			if t.Kind() == types.String {
+				node.T = types.Typ[types.Byte]
+				break
+			}
+
+			return fmt.Errorf(
+				"type error: cannot index %s (type %s)",
+				node.Left,
+				node.Left.Type(),
+			)
+		}
+	case *ast.SliceExpression:
+		if err := checkValue(node.Left, symbolTable); err != nil {
+			return err
+		}
+
+		if k := node.Left.Type().Kind(); k != types.SliceKind && k != types.String {
+			return fmt.Errorf(
+				"type error: cannot slice %s (type %s)",
+				node.Left,
+				node.Left.Type(),
+			)
+		}
+
+		for _, e := range []ast.Expression{node.Low, node.High} {
+			if e == nil {
+				continue
+			}
+
+			if err := checkValue(e, symbolTable); err != nil {
+				return err
+			}
+
+			if !integer(e.Type()) {
 				return fmt.Errorf(
-					"type error: identifier: %s is not a field in struct: %s",
-					node.Field.Value,
-					v.Value,
+					"type error: invalid slice index %s (type %s), must be integer",
+					e,
+					e.Type(),
 				)
 			}
-			node.Offset = offset
-		case *ast.CallExpression:
-			x, ok := v.T.(*types.Struct)
+		}
+
+		node.T = node.Left.Type()
+	case *ast.BuiltinExpression:
+		if err := checkBuiltin(node, symbolTable); err != nil {
+			return err
+		}
+	case *ast.ConversionExpression:
+		t, err := typeNodetoType(node.Tnode, symbolTable)
+		if err != nil {
+			return err
+		}
+
+		if err := checkConversion(node.Value, t, symbolTable); err != nil {
+			return err
+		}
+
+		node.T = t
+	case *ast.PrefixExpression:
+		if err := checkValue(node.Right, symbolTable); err != nil {
+			return err
+		}
+
+		switch node.Operator {
+		case "&":
+			if !addressable(node.Right, symbolTable) {
+				return fmt.Errorf("type error: cannot take the address of %s", node.Right)
+			}
+			markAddressTaken(node.Right, symbolTable)
+
+			node.T = &types.Pointer{Elem: node.Right.Type()}
+		case "*":
+			p, ok := node.Right.Type().(*types.Pointer)
 			if !ok {
 				return fmt.Errorf(
-					"type error: selecting field on identifier: %s, which is not a struct",
-					v,
+					"type error: invalid indirect of %s (type %s)",
+					node.Right,
+					node.Right.Type(),
 				)
 			}
-			offset, ok := identifierInStruct(node.Field, x)
-			if !ok {
+
+			node.T = p.Elem
+		case "!":
+			if node.Right.Type().Kind() != types.Bool {
 				return fmt.Errorf(
-					"type error: identifier: %s is not a field in struct: %s",
-					node.Field.Value,
-					v,
+					"type error: operator: %v does not support type: %v",
+					node.Operator,
+					node.Right.Type(),
 				)
 			}
-			node.Offset = offset
+
+			node.T = types.Typ[types.Bool]
+		case "-", "+":
+			t := node.Right.Type()
+			if !integer(t) && t.Kind() != types.Float {
+				return fmt.Errorf("type error: operator: %v does not support type: %v", node.Operator, t)
+			}
+
+			node.T = t
 		default:
-			return fmt.Errorf("type error: can not handle the expression for X in selection. got=%T", v)
+			return fmt.Errorf("type error: unknown prefix operator: %s", node.Operator)
 		}
-
-		node.T = node.Field.T
 	case *ast.AssignStatement:
-		if err := check(node.Name, symbolTable); err != nil {
+		if node.Operator != "" {
+			if err := checkCompoundAssign(node, symbolTable); err != nil {
+				return err
+			}
+			break
+		}
+
+		for _, n := range node.Names {
+			if err := checkValue(n, symbolTable); err != nil {
+				return err
+			}
+		}
+
+		ts, err := checkValues(node.Values, symbolTable)
+		if err != nil {
+			return err
+		}
+
+		if len(node.Names) != len(ts) {
+			return fmt.Errorf(
+				"type error: assignment mismatch: %d variables but %d values",
+				len(node.Names),
+				len(ts),
+			)
+		}
+
+		for i, n := range node.Names {
+			if err := checkTarget(n, symbolTable); err != nil {
+				return err
+			}
+
+			if len(node.Values) == len(ts) {
+				ts[i] = convertUntyped(node.Values[i], n.Type(), symbolTable)
+			}
+
+			if !assignable(ts[i], n.Type()) {
+				return fmt.Errorf(
+					"type error: identifier: %q of type: %s is assigned the wrong type: %s",
+					n,
+					n.Type(),
+					ts[i],
+				)
+			}
+		}
+	case *ast.IncDecStatement:
+		if err := checkValue(node.Name, symbolTable); err != nil {
 			return err
 		}
 
-		if err := check(node.Value, symbolTable); err != nil {
+		if err := checkTarget(node.Name, symbolTable); err != nil {
 			return err
 		}
 
-		if !reflect.DeepEqual(node.Name.Type(), node.Value.Type()) {
+		if !numeric(node.Name.Type()) {
 			return fmt.Errorf(
-				"type error: identifier: %q of type: %s is assigned the wrong type: %s",
-				node.Name,
+				"type error: invalid operation: %s (non-numeric type %s)",
+				node,
 				node.Name.Type(),
-				node.Value.Type(),
 			)
 		}
 	case *ast.IfStatement:
-		if err := check(node.Condition, symbolTable); err != nil {
+		if err := checkValue(node.Condition, symbolTable); err != nil {
 			return err
 		}
 
-		if node.Condition.Type() != types.Typ[types.Bool] {
+		if node.Condition.Type().Kind() != types.Bool {
 			return fmt.Errorf(
 				"type error: non-bool %s (type %s) used as if condition",
 				node.Condition.String(),
@@ -168,29 +380,53 @@ func check(node ast.Node, symbolTable *symbol.Table) error {
 			}
 		}
 	case *ast.ForStatement:
-		if err := check(node.Init, node.SymbolTable); err != nil {
-			return err
+		if node.Init != nil {
+			if err := check(node.Init, node.SymbolTable); err != nil {
+				return err
+			}
 		}
 
-		if err := check(node.Condition, node.SymbolTable); err != nil {
-			return err
+		if node.Condition != nil {
+			if err := checkValue(node.Condition, node.SymbolTable); err != nil {
+				return err
+			}
+
+			if node.Condition.Type().Kind() != types.Bool {
+				return fmt.Errorf(
+					"type error: non-bool %s (type %s) used as for condition",
+					node.Condition.String(),
+					node.Condition.Type(),
+				)
+			}
 		}
 
-		if node.Condition.Type() != types.Typ[types.Bool] {
-			return fmt.Errorf(
-				"type error: non-bool %s (type %s) used as for condition",
-				node.Condition.String(),
-				node.Condition.Type(),
-			)
+		if node.Next != nil {
+			if err := check(node.Next, node.SymbolTable); err != nil {
+				return err
+			}
 		}
 
-		if err := check(node.Next, node.SymbolTable); err != nil {
-			return err
+		if !defineLabel(node.Label) {
+			return fmt.Errorf("checker error: label: %q already defined", node.Label.Value)
 		}
 
-		if err := check(node.Body, node.SymbolTable); err != nil {
+		targets = append(targets, node)
+		err := check(node.Body, node.SymbolTable)
+		targets = targets[:len(targets)-1]
+		if err != nil {
+			return err
+		}
+	case *ast.SwitchStatement:
+		if err := checkSwitch(node, symbolTable); err != nil {
 			return err
 		}
+	case *ast.BranchStatement:
+		target, err := branchTarget(node)
+		if err != nil {
+			return err
+		}
+
+		node.Target = target
 	case *ast.FuncStatement:
 		currentFunc = node.Name
 		// TODO: Could maybe have a stack of functions if the function is
@@ -203,33 +439,82 @@ func check(node ast.Node, symbolTable *symbol.Table) error {
 			return err
 		}
 
+		if node.Receiver != nil {
+			signature, err = checkReceiver(node, signature)
+			if err != nil {
+				return err
+			}
+
+			// The method is known by its label.
+			currentFunc = &ast.Identifier{Token: node.Name.Token, Value: node.Label()}
+			currentFunc.T = signature
+		}
+
 		node.Name.T = signature
 
+		// Give the parameters their proper types.
+		for i, sym := range node.SymbolTable.Parameters() {
+			sym.Type = signature.Parameters[i]
+		}
+
 		if node.Body != nil {
 			// special case that a prototype have been defined
-			sym, _ := symbolTable.Resolve(node.Name.Value)
+			sym, _ := symbolTable.Resolve(node.Label())
 			if v, ok := sym.Type.(*types.Signature); ok {
 				if !reflect.DeepEqual(*signature, *v) {
 					return fmt.Errorf("type error: function: %q's prototype and definition differ in signature", node.Name.Value)
 				}
 			}
 
-			if err := check(node.Body, node.SymbolTable); err != nil {
+			outerLabels := labels
+			labels = nil
+			err := check(node.Body, node.SymbolTable)
+			labels = outerLabels
+			if err != nil {
 				return err
 			}
 
-			if signature.Result.Kind() != types.Nil {
-				lastIndex := len(node.Body.Statements) - 1
-				_, ok := node.Body.Statements[lastIndex].(*ast.ReturnStatement)
-				if !ok {
-					return fmt.Errorf("type error: function: %s, is missing return statement at the end", node.Name)
-				}
+			if signature.Result.Kind() != types.Nil && !endsWithReturn(node.Body) {
+				return fmt.Errorf("type error: function: %s, is missing return statement at the end", node.Name)
 			}
 		}
 
 		// Update the symbol of function to its proper type.
-		sym, _ := symbolTable.Resolve(node.Name.Value)
+		sym, _ := symbolTable.Resolve(node.Label())
 		sym.Type = node.Name.T
+	case *ast.FuncLiteral:
+		signature, err := funcTypeToSignature(node.Signature, node.SymbolTable)
+		if err != nil {
+			return err
+		}
+
+		for i, sym := range node.SymbolTable.Parameters() {
+			sym.Type = signature.Parameters[i]
+		}
+
+		// The keyword func can not be the name of a type, so the label can
+		// not be the label of a method.
+		funcLiterals++
+		node.Label = fmt.Sprintf("func.lit%d", funcLiterals)
+
+		// A function literal may be inside another function, which is
+		// continued afterwards.
+		// Neither can a break or a continue leave the function literal.
+		outer, outerTargets, outerLabels := currentFunc, targets, labels
+		currentFunc = &ast.Identifier{Token: node.Token, Value: node.Label, T: signature}
+		targets, labels = nil, nil
+
+		err = check(node.Body, node.SymbolTable)
+		currentFunc, targets, labels = outer, outerTargets, outerLabels
+		if err != nil {
+			return err
+		}
+
+		if signature.Result.Kind() != types.Nil && !endsWithReturn(node.Body) {
+			return fmt.Errorf("type error: function literal is missing return statement at the end")
+		}
+
+		node.T = signature
 	case *ast.ReturnStatement:
 		if currentFunc == nil {
 			return fmt.Errorf("checker error: Return statement can not be declared outside of function")
@@ -240,7 +525,7 @@ func check(node ast.Node, symbolTable *symbol.Table) error {
 
 		result := currentFunc.T.(*types.Signature).Result
 
-		if node.Value == nil {
+		if len(node.Values) == 0 {
 			if result.Kind() != types.Nil {
 				return fmt.Errorf("type error: function: %q was expected to return %q", currentFunc.Value, result)
 			}
@@ -248,20 +533,55 @@ func check(node ast.Node, symbolTable *symbol.Table) error {
 			return nil
 		}
 
-		if err := check(node.Value, symbolTable); err != nil {
+		ts, err := checkValues(node.Values, symbolTable)
+		if err != nil {
 			return err
 		}
 
-		if !reflect.DeepEqual(node.Value.Type(), result) {
-			return fmt.Errorf("type error: function: %q, returns type: %s, but expected to return: %s", currentFunc.Value, node.Value.Type(), result)
+		results := types.Values(result)
+		if len(ts) != len(results) {
+			return fmt.Errorf(
+				"type error: function: %q, returns %d values, but expected to return: %d",
+				currentFunc.Value,
+				len(ts),
+				len(results),
+			)
+		}
+
+		for i, t := range ts {
+			if len(node.Values) == len(ts) {
+				t = convertUntyped(node.Values[i], results[i], symbolTable)
+			}
+
+			if !assignable(t, results[i]) {
+				return fmt.Errorf("type error: function: %q, returns type: %s, but expected to return: %s", currentFunc.Value, t, results[i])
+			}
 		}
 	case *ast.CallExpression:
-		if err := check(node.Function, symbolTable); err != nil {
+		// A call of a named type is a conversion to the type.
+		if t, ok := conversionType(node, symbolTable); ok {
+			if len(node.Arguments) != 1 {
+				return fmt.Errorf(
+					"type error: wrong number of arguments in conversion to %s, expected: 1, got: %d",
+					t,
+					len(node.Arguments),
+				)
+			}
+
+			if err := checkConversion(node.Arguments[0], t, symbolTable); err != nil {
+				return err
+			}
+
+			node.T = t
+			break
+		}
+
+		if err := checkFunction(node.Function, symbolTable); err != nil {
 			return err
 		}
 
-		if node.Argument != nil {
-			if err := check(node.Argument, symbolTable); err != nil {
+		for _, a := range node.Arguments {
+			if err := checkValue(a, symbolTable); err != nil {
 				return err
 			}
 		}
@@ -274,30 +594,58 @@ func check(node ast.Node, symbolTable *symbol.Table) error {
 			)
 		}
 
-		if node.Argument == nil {
-			if sig.Parameter.Kind() != types.Nil {
-				// TODO: Better error message here will be great.
-				return fmt.Errorf("type error: function: %q takes arguments, non was provided", node.Function.(*ast.Identifier).Value)
-			}
-		} else if !reflect.DeepEqual(sig.Parameter, node.Argument.Type()) {
+		if len(node.Arguments) != len(sig.Parameters) {
 			return fmt.Errorf(
-				"type error: wrong argument type for %q, expected: %s, got: %s",
+				"type error: wrong number of arguments in call to %q, expected: %d, got: %d",
 				node.Function.TokenLiteral(),
-				sig.Parameter,
-				node.Argument.Type(),
+				len(sig.Parameters),
+				len(node.Arguments),
 			)
 		}
 
+		for i, a := range node.Arguments {
+			convertUntyped(a, sig.Parameters[i], symbolTable)
+
+			if !assignable(a.Type(), sig.Parameters[i]) {
+				return fmt.Errorf(
+					"type error: wrong type for argument %d in call to %q, expected: %s, got: %s",
+					i+1,
+					node.Function.TokenLiteral(),
+					sig.Parameters[i],
+					a.Type(),
+				)
+			}
+		}
+
 		node.T = sig.Result
 	case *ast.Identifier:
 		sym, ok := symbolTable.Resolve(node.Value)
+		if ok && sym.Scope == symbol.ConstScope {
+			// An untyped constant has its default type, until it is given
+			// another type by its use.
+			node.T = defaultType(sym.Type.(types.Type))
+			return nil
+		}
+
 		if !ok {
 			if node.Token.Type == token.Blank {
-				node.T = typeNodetoType(node.Tnode)
+				t, err := typeNodetoType(node.Tnode, symbolTable)
+				if err != nil {
+					return err
+				}
+
+				node.T = t
 				return nil
 			}
 		}
 
+		// A free variable without a type is captured before it is given
+		// the type of its value.
+		for sym.Type == nil && sym.Variable != nil {
+			sym.Type = sym.Variable.Type
+			sym = sym.Variable
+		}
+
 		switch v := sym.Type.(type) {
 		case *ast.FuncType:
 			signature, err := funcTypeToSignature(v, symbolTable)
@@ -308,89 +656,231 @@ func check(node ast.Node, symbolTable *symbol.Table) error {
 			node.T = signature
 			sym.Type = node.T
 		case *ast.BasicType:
-			if v.Token.Type == token.Ident {
-				s, ok := symbolTable.Resolve(v.Token.Literal)
+			t, err := typeNodetoType(v, symbolTable)
+			if err != nil {
+				return err
+			}
+
+			// A type statement of a basic type declares a new type of
+			// the same kind.
+			if sym.Scope == symbol.TypeScope {
+				b, ok := t.(*types.Basic)
 				if !ok {
-					return fmt.Errorf(
-						"checker error: identifier %q is not defined",
-						v.Token.Literal,
-					)
+					return fmt.Errorf("type error: invalid type: %s (underlying type %s)", node.Value, t)
 				}
-				if s.Scope != symbol.TypeScope {
-					return fmt.Errorf(
-						"checker error: identifier %q is not a type",
-						v.Token.Literal,
-					)
-				}
-				node.T = s.Type.(*types.Struct)
-				sym.Type = node.T
-			} else {
-				node.T = tokenToType(v.Token)
-				sym.Type = node.T
+				t = b.Named(node.Value)
 			}
+
+			node.T = t
+			sym.Type = node.T
 		case *types.Basic:
 			node.T = v
 		case *types.Signature:
 			node.T = v
 		case *types.Struct:
 			node.T = v
+		case *types.Array, *types.Slice, *types.Pointer, *types.Interface:
+			node.T = v.(types.Type)
+		case *ast.ArrayType, *ast.SliceType, *ast.PointerType:
+			t, err := typeNodetoType(v.(ast.TypeNode), symbolTable)
+			if err != nil {
+				return err
+			}
+
+			node.T = t
+			sym.Type = node.T
 		case *ast.StructType:
+			// The struct is given to the symbol before its fields are
+			// checked, so a field can point to the struct itself.
+			str := &types.Struct{Name: node.Value}
+			sym.Type = str
+
 			if err := check(v, symbolTable); err != nil {
 				return err
 			}
-			var fields []*types.Field
 			for _, f := range v.Fields {
-				fields = append(fields, &types.Field{
+				// A struct field is stored in place, so the struct can not
+				// contain itself.
+				if containsStruct(f.T, str) {
+					return fmt.Errorf("type error: invalid recursive type: %s", node.Value)
+				}
+
+				str.Fields = append(str.Fields, &types.Field{
 					Name: f.Value,
 					Type: f.T,
 				})
 			}
 
-			node.T = &types.Struct{Fields: fields}
-			sym.Type = node.T
+			node.T = str
+		case *ast.InterfaceType:
+			// Like a struct, the interface is given to the symbol first, so
+			// a method can take the interface itself.
+			iface := &types.Interface{Name: node.Value}
+			sym.Type = iface
+
+			if err := check(v, symbolTable); err != nil {
+				return err
+			}
+			for _, m := range v.Methods {
+				if _, ok := iface.Method(m.Value); ok {
+					return fmt.Errorf("type error: duplicate method: %s in interface: %s", m.Value, node.Value)
+				}
+
+				iface.Methods = append(iface.Methods, &types.Method{
+					Name:      m.Value,
+					Signature: m.T.(*types.Signature),
+				})
+			}
+
+			node.T = iface
 		default:
 			return fmt.Errorf("type error: identifier: %q has the unknown type: %q", node.Value, node.Tnode)
 		}
 	case *ast.InfixExpression:
-		if err := check(node.Left, symbolTable); err != nil {
+		if err := checkValue(node.Left, symbolTable); err != nil {
 			return err
 		}
 
-		if err := check(node.Right, symbolTable); err != nil {
+		if err := checkValue(node.Right, symbolTable); err != nil {
 			return err
 		}
 
 		lt := node.Left.Type()
 		rt := node.Right.Type()
 
-		if lt != rt {
+		// An untyped constant is given the type of the other operand, and an
+		// untyped int is given the type of an untyped float.
+		leftUntyped := untypedConstant(node.Left, symbolTable)
+		rightUntyped := untypedConstant(node.Right, symbolTable)
+		switch {
+		case leftUntyped && !rightUntyped:
+			lt = convertUntyped(node.Left, rt, symbolTable)
+		case rightUntyped && !leftUntyped:
+			rt = convertUntyped(node.Right, lt, symbolTable)
+		case leftUntyped && rightUntyped && lt != rt:
+			lt = convertUntyped(node.Left, types.Typ[types.Float], symbolTable)
+			rt = convertUntyped(node.Right, types.Typ[types.Float], symbolTable)
+		}
+
+		// nil can only be compared to a pointer or an interface.
+		if lt.Kind() == types.UntypedNil && nilable(rt) {
+			node.Left.(*ast.NilLiteral).T = rt
+			lt = rt
+		}
+		if rt.Kind() == types.UntypedNil && nilable(lt) {
+			node.Right.(*ast.NilLiteral).T = lt
+			rt = lt
+		}
+
+		if !reflect.DeepEqual(lt, rt) {
+			if comparison(node.Operator) {
+				return fmt.Errorf(
+					"type error: invalid comparison: %s (mismatched types %s and %s)",
+					node,
+					lt,
+					rt,
+				)
+			}
+
 			return fmt.Errorf("type error: mismatch of types %s and %s", lt, rt)
 		}
 
-		if lt == types.Typ[types.String] {
+		switch lt.Kind() {
+		case types.UntypedNil:
+			return fmt.Errorf("type error: operator: %v is not defined on nil", node.Operator)
+		case types.PointerKind:
+			if node.Operator != "==" && node.Operator != "!=" {
+				return fmt.Errorf("type error: operator: %v does not support type: %v", node.Operator, lt)
+			}
+		case types.StructKind:
+			// A struct is compared field by field.
+			if (node.Operator != "==" && node.Operator != "!=") || !comparable(lt) {
+				return fmt.Errorf("type error: operator: %v does not support type: %v", node.Operator, lt)
+			}
+		case types.InterfaceKind:
+			// An interface value can only be compared to nil.
+			_, leftNil := node.Left.(*ast.NilLiteral)
+			_, rightNil := node.Right.(*ast.NilLiteral)
+			if (node.Operator != "==" && node.Operator != "!=") || (!leftNil && !rightNil) {
+				return fmt.Errorf("type error: operator: %v does not support type: %v", node.Operator, lt)
+			}
+		}
+
+		if lt.Kind() == types.ArrayKind || lt.Kind() == types.SliceKind {
+			return fmt.Errorf("type error: operator: %v does not support type: %v", node.Operator, lt)
+		}
+
+		// A string is only compared and concatenated.
+		if lt.Kind() == types.String && !comparison(node.Operator) && node.Operator != "+" {
 			return fmt.Errorf("type error: operator: %v does not support type: %v", node.Operator, lt)
 		}
 
 		switch node.Operator {
-		case "<":
-			if lt == types.Typ[types.Bool] {
+		case "<", "<=", ">", ">=":
+			if !ordered(lt) {
 				return fmt.Errorf("type error: operator: %v does not support type: %v", node.Operator, lt)
 			}
 
 			node.T = types.Typ[types.Bool]
 		case "==", "!=":
 			node.T = types.Typ[types.Bool]
-		default:
-			node.T = lt
-		}
-	case *ast.IntegerLiteral:
-		node.T = types.Typ[types.Int]
+		case "&&", "||":
+			if lt.Kind() != types.Bool {
+				return fmt.Errorf("type error: operator: %v does not support type: %v", node.Operator, lt)
+			}
+
+			node.T = types.Typ[types.Bool]
+		case "%", "&", "|", "^", "&^", "<<", ">>":
+			if !integer(lt) {
+				return fmt.Errorf("type error: operator: %v does not support type: %v", node.Operator, lt)
+			}
+
+			node.T = lt
+		default:
+			node.T = lt
+		}
+
+		// A constant expression is folded, so it must neither overflow nor
+		// divide by zero.
+		if constantOperands(node, symbolTable) {
+			if _, _, err := constant(node, 0, symbolTable); err != nil {
+				return err
+			}
+		}
+	case *ast.IntegerLiteral:
+		node.T = types.Typ[types.Int]
 	case *ast.FloatLiteral:
 		node.T = types.Typ[types.Float]
 	case *ast.StringLiteral:
 		node.T = types.Typ[types.String]
 	case *ast.BoolLiteral:
 		node.T = types.Typ[types.Bool]
+	case *ast.NilLiteral:
+		node.T = types.Typ[types.UntypedNil]
+	case *ast.Iota:
+		return fmt.Errorf("type error: cannot use iota outside constant declaration")
+	case *ast.CompositeLiteral:
+		t, err := typeNodetoType(node.Tnode, symbolTable)
+		if err != nil {
+			return err
+		}
+
+		s, ok := t.(*types.Struct)
+		if !ok {
+			return fmt.Errorf("type error: invalid composite literal type: %s", node.Tnode)
+		}
+
+		for _, v := range node.Values {
+			if err := checkValue(v, symbolTable); err != nil {
+				return err
+			}
+		}
+
+		if err := checkCompositeLiteral(node, s, symbolTable); err != nil {
+			return err
+		}
+
+		node.T = s
 	default:
 		return fmt.Errorf("type error: ast node not handled: %T", node)
 	}
@@ -398,30 +888,1045 @@ func check(node ast.Node, symbolTable *symbol.Table) error {
 	return nil
 }
 
+// checkTarget checks that the value of the expression can be assigned to.
+func checkTarget(n ast.Expression, symbolTable *symbol.Table) error {
+	if stringIndex(n) {
+		return fmt.Errorf("type error: cannot assign to %s (strings are immutable)", n)
+	}
+
+	if id, ok := n.(*ast.Identifier); ok {
+		if s, ok := symbolTable.Resolve(id.Value); ok && s.Scope == symbol.ConstScope {
+			return fmt.Errorf("type error: cannot assign to %s (constant)", id.Value)
+		}
+	}
+
+	return nil
+}
+
+// checkCompoundAssign checks the compound assignment e.g. "x += 2" like the
+// assignment "x = x + 2", though x is only evaluated once.
+func checkCompoundAssign(node *ast.AssignStatement, symbolTable *symbol.Table) error {
+	if len(node.Names) != 1 || len(node.Values) != 1 {
+		return fmt.Errorf(
+			"type error: assignment operation %s requires single-valued expressions",
+			node.TokenLiteral(),
+		)
+	}
+
+	name := node.Names[0]
+
+	if err := checkValue(name, symbolTable); err != nil {
+		return err
+	}
+
+	if err := checkTarget(name, symbolTable); err != nil {
+		return err
+	}
+
+	value := &ast.InfixExpression{
+		Token:    node.Token,
+		Left:     name,
+		Operator: node.Operator,
+		Right:    node.Values[0],
+	}
+	if err := check(value, symbolTable); err != nil {
+		return err
+	}
+
+	if !assignable(value.Type(), name.Type()) {
+		return fmt.Errorf(
+			"type error: identifier: %q of type: %s is assigned the wrong type: %s",
+			name,
+			name.Type(),
+			value.Type(),
+		)
+	}
+
+	return nil
+}
+
+// inferType returns the type a variable without a type is given by its value
+// of type t. An untyped constant gives its default type.
+func inferType(n *ast.Identifier, t types.Type) (types.Type, error) {
+	if t.Kind() == types.UntypedNil {
+		return nil, fmt.Errorf("type error: use of untyped nil in assignment to %s", n.Value)
+	}
+
+	return defaultType(t), nil
+}
+
+// checkSwitch checks that the values of the cases have the type of the tag,
+// which is a bool for a switch without a tag.
+func checkSwitch(node *ast.SwitchStatement, symbolTable *symbol.Table) error {
+	var tag types.Type = types.Typ[types.Bool]
+	if node.Tag != nil {
+		if err := checkValue(node.Tag, symbolTable); err != nil {
+			return err
+		}
+
+		tag = node.Tag.Type()
+		if !switchable(tag) {
+			return fmt.Errorf("type error: cannot switch on %s (type %s)", node.Tag, tag)
+		}
+	}
+
+	if !defineLabel(node.Label) {
+		return fmt.Errorf("checker error: label: %q already defined", node.Label.Value)
+	}
+
+	var hasDefault bool
+	seen := make(map[interface{}]bool)
+
+	for _, c := range node.Cases {
+		if len(c.Values) == 0 {
+			if hasDefault {
+				return fmt.Errorf("checker error: multiple defaults in switch")
+			}
+			hasDefault = true
+		}
+
+		for _, v := range c.Values {
+			if err := checkValue(v, symbolTable); err != nil {
+				return err
+			}
+
+			t := convertUntyped(v, tag, symbolTable)
+			if t.Kind() == types.UntypedNil && nilable(tag) {
+				v.(*ast.NilLiteral).T = tag
+				t = tag
+			}
+
+			if !reflect.DeepEqual(t, tag) {
+				return fmt.Errorf(
+					"type error: invalid case %s in switch (mismatched types %s and %s)",
+					v,
+					t,
+					tag,
+				)
+			}
+
+			// The same constant can not be the value of two cases.
+			if value, _, err := constant(v, 0, symbolTable); err == nil {
+				if seen[value] {
+					return fmt.Errorf("type error: duplicate case %s in switch", v)
+				}
+				seen[value] = true
+			}
+		}
+	}
+
+	targets = append(targets, node)
+	defer func() { targets = targets[:len(targets)-1] }()
+
+	for _, c := range node.Cases {
+		if err := check(c.Body, symbolTable); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// switchable reports whether a switch can have a tag of type t, which must be
+// comparable to the values of the cases.
+func switchable(t types.Type) bool {
+	return ordered(t) || t.Kind() == types.Bool || t.Kind() == types.PointerKind
+}
+
+// branchTarget returns the statement the break or the continue statement
+// targets. Without a label a break targets the innermost loop or switch, and a
+// continue targets the innermost loop.
+func branchTarget(node *ast.BranchStatement) (ast.Statement, error) {
+	for i := len(targets) - 1; i >= 0; i-- {
+		_, loop := targets[i].(*ast.ForStatement)
+
+		if node.Label == nil {
+			if loop || node.Token.Type == token.Break {
+				return targets[i], nil
+			}
+
+			continue
+		}
+
+		if l := label(targets[i]); l != nil && l.Value == node.Label.Value {
+			if !loop && node.Token.Type == token.Continue {
+				break
+			}
+
+			return targets[i], nil
+		}
+	}
+
+	if node.Label != nil {
+		return nil, fmt.Errorf("checker error: invalid %s label: %q", node.Token.Literal, node.Label.Value)
+	}
+
+	if node.Token.Type == token.Break {
+		return nil, fmt.Errorf("checker error: break is not in a loop or switch")
+	}
+
+	return nil, fmt.Errorf("checker error: continue is not in a loop")
+}
+
+// defineLabel adds the label to the labels of the function. It reports false
+// if the function already has the label, either on an enclosing loop or switch
+// or on an earlier one.
+func defineLabel(id *ast.Identifier) bool {
+	if id == nil {
+		return true
+	}
+
+	if labels[id.Value] {
+		return false
+	}
+
+	if labels == nil {
+		labels = make(map[string]bool)
+	}
+	labels[id.Value] = true
+
+	return true
+}
+
+// label returns the label of the loop or the switch.
+func label(stmt ast.Statement) *ast.Identifier {
+	switch stmt := stmt.(type) {
+	case *ast.ForStatement:
+		return stmt.Label
+	case *ast.SwitchStatement:
+		return stmt.Label
+	default:
+		return nil
+	}
+}
+
+// selectField gives the selector the type and the offset of the selected field
+// of the struct. X must already have been checked.
+func selectField(node *ast.SelectorExpression) error {
+	switch v := node.X.(type) {
+	case *ast.Identifier:
+		x, ok := structOf(v.T)
+		if !ok {
+			return fmt.Errorf(
+				"type error: selecting field on identifier: %s, which is not a struct",
+				v,
+			)
+		}
+		offset, ok := identifierInStruct(node.Field, x)
+		if !ok {
+			return fmt.Errorf(
+				"type error: identifier: %s is not a field in struct: %s",
+				node.Field.Value,
+				v.Value,
+			)
+		}
+		node.Offset = offset
+	case *ast.CallExpression, *ast.IndexExpression, *ast.PrefixExpression, *ast.SelectorExpression, *ast.CompositeLiteral:
+		x, ok := structOf(v.Type())
+		if !ok {
+			return fmt.Errorf(
+				"type error: selecting field on identifier: %s, which is not a struct",
+				v,
+			)
+		}
+		offset, ok := identifierInStruct(node.Field, x)
+		if !ok {
+			return fmt.Errorf(
+				"type error: identifier: %s is not a field in struct: %s",
+				node.Field.Value,
+				v,
+			)
+		}
+		node.Offset = offset
+	default:
+		return fmt.Errorf("type error: can not handle the expression for X in selection. got=%T", v)
+	}
+
+	node.T = node.Field.T
+
+	return nil
+}
+
+// checkFunction checks the function of a call. Selecting a method of a named
+// type or an interface gives the signature of the method, anything else must
+// be a function value.
+func checkFunction(fn ast.Expression, symbolTable *symbol.Table) error {
+	sel, ok := fn.(*ast.SelectorExpression)
+	if !ok {
+		return check(fn, symbolTable)
+	}
+
+	if err := checkValue(sel.X, symbolTable); err != nil {
+		return err
+	}
+
+	if m, ok := methodOf(sel.X.Type(), sel.Field.Value); ok {
+		pointer := sel.X.Type().Kind() == types.PointerKind
+
+		// A pointer method is called with the address of the value.
+		if m.PointerReceiver && !pointer && !addressable(sel.X, symbolTable) {
+			return fmt.Errorf("type error: cannot call pointer method: %s on %s", m.Name, sel.X)
+		}
+
+		// Both a struct and a pointer to it are held as the address of the
+		// struct, but any other receiver is passed as the value or the
+		// pointer which the method takes.
+		if _, ok := structOf(sel.X.Type()); !ok && m.PointerReceiver != pointer {
+			operator := "*"
+			if m.PointerReceiver {
+				operator = "&"
+			}
+
+			sel.X = &ast.PrefixExpression{
+				Token:    token.Token{Type: token.TokenType(operator), Literal: operator},
+				Operator: operator,
+				Right:    sel.X,
+			}
+			if err := checkValue(sel.X, symbolTable); err != nil {
+				return err
+			}
+		}
+
+		sel.Method = m
+		sel.T = m.Signature
+
+		return nil
+	}
+
+	if i, ok := sel.X.Type().(*types.Interface); ok {
+		m, ok := i.Method(sel.Field.Value)
+		if !ok {
+			return fmt.Errorf(
+				"type error: identifier: %s is not a method in interface: %s",
+				sel.Field.Value,
+				i,
+			)
+		}
+
+		sel.Method = m
+		sel.T = m.Signature
+
+		return nil
+	}
+
+	return selectField(sel)
+}
+
+// checkReceiver checks the receiver of the method and adds the method to the
+// method set of the receiver type. It returns the signature of the method as
+// a function, which takes the receiver as its first parameter.
+func checkReceiver(node *ast.FuncStatement, signature *types.Signature) (*types.Signature, error) {
+	recv := node.Receiver
+
+	t, err := typeNodetoType(recv.Tnode, node.SymbolTable)
+	if err != nil {
+		return nil, err
+	}
+	recv.T = t
+
+	named := t
+	p, pointer := t.(*types.Pointer)
+	if pointer {
+		named = p.Elem
+	}
+
+	// The receiver is a named struct or a basic type named by a type
+	// statement, which is not one of the predeclared types.
+	var methods *[]*types.Method
+	switch n := named.(type) {
+	case *types.Struct:
+		if n.Name == "" {
+			return nil, fmt.Errorf("type error: invalid receiver type: %s", t)
+		}
+
+		for _, f := range n.Fields {
+			if f.Name == node.Name.Value {
+				return nil, fmt.Errorf("type error: field and method with the same name: %s", f.Name)
+			}
+		}
+
+		methods = &n.Methods
+	case *types.Basic:
+		if n == types.Typ[n.Kind()] {
+			return nil, fmt.Errorf("type error: invalid receiver type: %s", t)
+		}
+
+		methods = &n.Methods
+	default:
+		return nil, fmt.Errorf("type error: invalid receiver type: %s", t)
+	}
+
+	// A prototyped method is already in the method set.
+	if _, ok := methodOf(named, node.Name.Value); !ok {
+		*methods = append(*methods, &types.Method{
+			Name:            node.Name.Value,
+			Signature:       signature,
+			PointerReceiver: pointer,
+		})
+	}
+
+	return &types.Signature{
+		Parameters: append([]types.Type{t}, signature.Parameters...),
+		Result:     signature.Result,
+	}, nil
+}
+
+// conversionType returns the type of the call, if the call is a conversion to
+// a named type.
+func conversionType(node *ast.CallExpression, symbolTable *symbol.Table) (types.Type, bool) {
+	id, ok := node.Function.(*ast.Identifier)
+	if !ok {
+		return nil, false
+	}
+
+	s, ok := symbolTable.Resolve(id.Value)
+	if !ok || s.Scope != symbol.TypeScope {
+		return nil, false
+	}
+
+	t, ok := s.Type.(types.Type)
+	return t, ok
+}
+
+// checkConversion checks that the value can be converted to type t.
+func checkConversion(value ast.Expression, t types.Type, symbolTable *symbol.Table) error {
+	if err := checkValue(value, symbolTable); err != nil {
+		return err
+	}
+
+	// A constant converted to a number must be a value of the number, so it
+	// is neither truncated nor overflowed.
+	if numeric(t) {
+		if v, vt, err := constant(value, 0, symbolTable); err == nil && numeric(defaultType(vt)) {
+			if _, err := representable(v, vt, t); err != nil {
+				return err
+			}
+		}
+	}
+
+	convertUntyped(value, t, symbolTable)
+
+	if !convertible(value.Type(), t) {
+		return fmt.Errorf("type error: cannot convert %s (type %s) to type %s", value, value.Type(), t)
+	}
+
+	return nil
+}
+
+// convertible reports whether a value of type v can be converted to type t.
+// Besides an assignable value, a number can be converted to another number, a
+// named basic type to and from its basic type, a byte to a string, and a
+// struct to another struct with identical fields.
+func convertible(v, t types.Type) bool {
+	if assignable(v, t) {
+		return true
+	}
+
+	if numeric(v) && numeric(t) {
+		return true
+	}
+
+	_, vBasic := v.(*types.Basic)
+	_, tBasic := t.(*types.Basic)
+	if vBasic && tBasic && v.Kind() == t.Kind() {
+		return true
+	}
+
+	if v.Kind() == types.Byte && t.Kind() == types.String {
+		return true
+	}
+
+	vs, ok := v.(*types.Struct)
+	if !ok {
+		return false
+	}
+
+	ts, ok := t.(*types.Struct)
+	if !ok || len(vs.Fields) != len(ts.Fields) {
+		return false
+	}
+
+	for i, f := range vs.Fields {
+		if f.Name != ts.Fields[i].Name || !reflect.DeepEqual(f.Type, ts.Fields[i].Type) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// numeric reports whether t is a numeric type.
+func numeric(t types.Type) bool {
+	return integer(t) || t.Kind() == types.Float
+}
+
+// comparison reports whether the operator is a comparison operator.
+func comparison(operator string) bool {
+	switch operator {
+	case "==", "!=", "<", "<=", ">", ">=":
+		return true
+	default:
+		return false
+	}
+}
+
+// ordered reports whether the values of type t can be ordered by the operators
+// <, <=, > and >=.
+func ordered(t types.Type) bool {
+	switch t.Kind() {
+	case types.Int, types.Byte, types.Float, types.String:
+		return true
+	default:
+		return false
+	}
+}
+
+// comparable reports whether the values of type t can be compared by the
+// operators == and != as a field of a struct. A struct is comparable if every
+// field is.
+func comparable(t types.Type) bool {
+	switch t.Kind() {
+	case types.Int, types.Byte, types.Float, types.String, types.Bool, types.PointerKind:
+		return true
+	case types.StructKind:
+		for _, f := range t.(*types.Struct).Fields {
+			if !comparable(f.Type) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return false
+	}
+}
+
+// integer reports whether t is an integer type.
+func integer(t types.Type) bool {
+	return t.Kind() == types.Int || t.Kind() == types.Byte
+}
+
+// stringIndex reports whether the expression indexes a string, whose bytes can
+// not be changed.
+func stringIndex(expr ast.Expression) bool {
+	ie, ok := expr.(*ast.IndexExpression)
+	return ok && ie.Left.Type().Kind() == types.String
+}
+
+// endsWithReturn reports whether the last statement of the body is a
+// terminating statement, after which the body can not continue.
+func endsWithReturn(body *ast.BlockStatement) bool {
+	if len(body.Statements) == 0 {
+		return false
+	}
+
+	return terminating(body.Statements[len(body.Statements)-1])
+}
+
+// terminating reports whether the statement is a return statement, or a
+// statement which only ends by one: a block ending in one, an if with an else
+// where both branches end in one, a switch with a default where every case
+// ends in one and a for loop without a condition. Neither the switch nor the
+// loop may be left by a break.
+func terminating(stmt ast.Statement) bool {
+	switch stmt := stmt.(type) {
+	case *ast.ReturnStatement:
+		return true
+	case *ast.BlockStatement:
+		return endsWithReturn(stmt)
+	case *ast.IfStatement:
+		return stmt.Alternative != nil && endsWithReturn(stmt.Consequence) && terminating(stmt.Alternative)
+	case *ast.SwitchStatement:
+		var hasDefault bool
+		for _, cc := range stmt.Cases {
+			if cc.Token.Type == token.Default {
+				hasDefault = true
+			}
+
+			if !endsWithReturn(cc.Body) || breaks(cc.Body, stmt) {
+				return false
+			}
+		}
+
+		return hasDefault
+	case *ast.ForStatement:
+		return stmt.Condition == nil && !breaks(stmt.Body, stmt)
+	default:
+		return false
+	}
+}
+
+// breaks reports whether a break statement in the statement leaves the target.
+func breaks(stmt ast.Statement, target ast.Statement) bool {
+	switch stmt := stmt.(type) {
+	case *ast.BranchStatement:
+		return stmt.Token.Type == token.Break && stmt.Target == target
+	case *ast.BlockStatement:
+		for _, s := range stmt.Statements {
+			if breaks(s, target) {
+				return true
+			}
+		}
+	case *ast.IfStatement:
+		return breaks(stmt.Consequence, target) || (stmt.Alternative != nil && breaks(stmt.Alternative, target))
+	case *ast.SwitchStatement:
+		for _, cc := range stmt.Cases {
+			if breaks(cc.Body, target) {
+				return true
+			}
+		}
+	case *ast.ForStatement:
+		return breaks(stmt.Body, target)
+	}
+
+	return false
+}
+
+// checkValue checks an expression which must produce a single value.
+func checkValue(expr ast.Expression, symbolTable *symbol.Table) error {
+	if err := check(expr, symbolTable); err != nil {
+		return err
+	}
+
+	if expr.Type().Kind() == types.TupleKind {
+		return fmt.Errorf("type error: multiple-value %s in single-value context", expr)
+	}
+
+	return nil
+}
+
+// checkValues checks the expressions on the right hand side of an assignment
+// or a return and returns the types of the values. A single call to a
+// function with multiple results gives all of its results.
+func checkValues(exprs []ast.Expression, symbolTable *symbol.Table) ([]types.Type, error) {
+	if len(exprs) == 1 {
+		if err := check(exprs[0], symbolTable); err != nil {
+			return nil, err
+		}
+
+		return types.Values(exprs[0].Type()), nil
+	}
+
+	var ts []types.Type
+	for _, e := range exprs {
+		if err := checkValue(e, symbolTable); err != nil {
+			return nil, err
+		}
+
+		ts = append(ts, e.Type())
+	}
+
+	return ts, nil
+}
+
+// checkBuiltin checks the arguments of a call to a builtin function.
+func checkBuiltin(node *ast.BuiltinExpression, symbolTable *symbol.Table) error {
+	for _, a := range node.Arguments {
+		if err := checkValue(a, symbolTable); err != nil {
+			return err
+		}
+	}
+
+	name := node.Token.Literal
+	args := node.Arguments
+
+	switch node.Token.Type {
+	case token.Make:
+		t, err := typeNodetoType(node.Tnode, symbolTable)
+		if err != nil {
+			return err
+		}
+
+		if t.Kind() != types.SliceKind {
+			return fmt.Errorf("type error: cannot make type: %s", t)
+		}
+
+		if len(args) < 1 || len(args) > 2 {
+			return fmt.Errorf(
+				"type error: wrong number of arguments in call to %q, expected: 2 or 3, got: %d",
+				name,
+				len(args)+1,
+			)
+		}
+
+		for _, a := range args {
+			if a.Type().Kind() != types.Int {
+				return fmt.Errorf(
+					"type error: wrong type for size argument %s in call to %q, expected: %s, got: %s",
+					a,
+					name,
+					types.Typ[types.Int],
+					a.Type(),
+				)
+			}
+		}
+
+		node.T = t
+	case token.New:
+		t, err := typeNodetoType(node.Tnode, symbolTable)
+		if err != nil {
+			return err
+		}
+
+		if len(args) != 0 {
+			return fmt.Errorf(
+				"type error: wrong number of arguments in call to %q, expected: 1, got: %d",
+				name,
+				len(args)+1,
+			)
+		}
+
+		node.T = &types.Pointer{Elem: t}
+	case token.Append:
+		if len(args) == 0 {
+			return fmt.Errorf("type error: missing arguments in call to %q", name)
+		}
+
+		s, ok := args[0].Type().(*types.Slice)
+		if !ok {
+			return fmt.Errorf(
+				"type error: first argument in call to %q must be a slice, got: %s",
+				name,
+				args[0].Type(),
+			)
+		}
+
+		for i, a := range args[1:] {
+			convertUntyped(a, s.Elem, symbolTable)
+
+			if !assignable(a.Type(), s.Elem) {
+				return fmt.Errorf(
+					"type error: wrong type for argument %d in call to %q, expected: %s, got: %s",
+					i+2,
+					name,
+					s.Elem,
+					a.Type(),
+				)
+			}
+		}
+
+		node.T = s
+	case token.Len, token.Cap:
+		if len(args) != 1 {
+			return fmt.Errorf(
+				"type error: wrong number of arguments in call to %q, expected: 1, got: %d",
+				name,
+				len(args),
+			)
+		}
+
+		t := args[0].Type()
+		switch {
+		case t.Kind() == types.ArrayKind, t.Kind() == types.SliceKind:
+		case t.Kind() == types.String && node.Token.Type == token.Len:
+			// The length of a string is its number of bytes.
+		default:
+			return fmt.Errorf(
+				"type error: invalid argument %s (type %s) for %q",
+				args[0],
+				args[0].Type(),
+				name,
+			)
+		}
+
+		node.T = types.Typ[types.Int]
+	default:
+		return fmt.Errorf("type error: unknown builtin function: %q", name)
+	}
+
+	return nil
+}
+
+// assignable reports whether a value of type v can be assigned to a variable
+// of type t.
+func assignable(v, t types.Type) bool {
+	if v.Kind() == types.UntypedNil {
+		return nilable(t)
+	}
+
+	if i, ok := t.(*types.Interface); ok {
+		return implements(v, i)
+	}
+
+	return reflect.DeepEqual(v, t)
+}
+
+// nilable reports whether nil is a value of type t.
+func nilable(t types.Type) bool {
+	return t.Kind() == types.PointerKind || t.Kind() == types.InterfaceKind
+}
+
+// implements reports whether type t has every method of the interface. Only a
+// named struct and a pointer to it are held by an interface value, where the
+// pointer methods are only in the method set of the pointer. The methods of a
+// named basic type are only called directly. An interface has the methods of
+// the interface.
+func implements(t types.Type, iface *types.Interface) bool {
+	if i, ok := t.(*types.Interface); ok {
+		for _, im := range iface.Methods {
+			m, ok := i.Method(im.Name)
+			if !ok || !reflect.DeepEqual(m.Signature, im.Signature) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	s, ok := structOf(t)
+	if !ok {
+		return false
+	}
+
+	for _, im := range iface.Methods {
+		m, ok := s.Method(im.Name)
+		if !ok || !reflect.DeepEqual(m.Signature, im.Signature) {
+			return false
+		}
+
+		if m.PointerReceiver && t.Kind() != types.PointerKind {
+			return false
+		}
+	}
+
+	return true
+}
+
+// addressable reports whether the address of the expression can be taken,
+// which is the case for variables, struct fields, elements, pointer
+// indirections and composite literals.
+func addressable(expr ast.Expression, symbolTable *symbol.Table) bool {
+	switch expr := expr.(type) {
+	case *ast.Identifier:
+		s, ok := symbolTable.Resolve(expr.Value)
+		return ok && (s.Scope == symbol.GlobalScope || s.Scope == symbol.LocalScope || s.Scope == symbol.FreeScope)
+	case *ast.SelectorExpression, *ast.CompositeLiteral:
+		return true
+	case *ast.IndexExpression:
+		return !stringIndex(expr)
+	case *ast.PrefixExpression:
+		return expr.Operator == "*"
+	default:
+		return false
+	}
+}
+
+// markAddressTaken marks the local variable, which holds the value whose address is
+// taken, as captured. The address may outlive the function, so the variable
+// lives on the heap like a variable captured by a function literal. An element
+// of an array is held by the array variable itself.
+func markAddressTaken(expr ast.Expression, symbolTable *symbol.Table) {
+	for {
+		index, ok := expr.(*ast.IndexExpression)
+		if !ok || index.Left.Type().Kind() != types.ArrayKind {
+			break
+		}
+		expr = index.Left
+	}
+
+	if ident, ok := expr.(*ast.Identifier); ok {
+		if s, _ := symbolTable.Resolve(ident.Value); s.Scope == symbol.LocalScope {
+			s.Captured = true
+		}
+	}
+}
+
+// methodOf returns the method with the name in the method set of t, where t is
+// either a named type or a pointer to one.
+func methodOf(t types.Type, name string) (*types.Method, bool) {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem
+	}
+
+	switch t := t.(type) {
+	case *types.Struct:
+		return t.Method(name)
+	case *types.Basic:
+		return t.Method(name)
+	default:
+		return nil, false
+	}
+}
+
+// structOf returns the struct of t, where t is either a struct or a pointer to
+// a struct, as fields can be selected directly through the pointer.
+func structOf(t types.Type) (*types.Struct, bool) {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem
+	}
+
+	s, ok := t.(*types.Struct)
+	return s, ok
+}
+
 // identifierInStruct checks if the identifier is in the struct. If it is, then
 // updates that identifier with the same type as the one in the struct and
 // returns true. Otherwise returns false.
 func identifierInStruct(id *ast.Identifier, s *types.Struct) (int, bool) {
-	const wordAllignment = 8
 	for i, f := range s.Fields {
 		if f.Name == id.Value {
 			id.T = f.Type
-			return i * wordAllignment, true
+			return s.Offset(i), true
 		}
 	}
 
 	return 0, false
 }
 
-func typeNodetoType(t ast.TypeNode) types.Type {
+// checkCompositeLiteral checks that the values of the composite literal match
+// the fields of the struct. Keyed values may leave out fields, while
+// positional values must be given for every field.
+func checkCompositeLiteral(lit *ast.CompositeLiteral, s *types.Struct, symbolTable *symbol.Table) error {
+	if len(lit.Values) == 0 {
+		return nil
+	}
+
+	keyed := lit.Keys[0] != nil
+
+	seen := make(map[string]bool)
+	for i, v := range lit.Values {
+		if (lit.Keys[i] != nil) != keyed {
+			return fmt.Errorf("type error: mixture of field:value and value elements in struct literal")
+		}
+
+		var field *types.Field
+		if keyed {
+			key := lit.Keys[i]
+			if _, ok := identifierInStruct(key, s); !ok {
+				return fmt.Errorf("type error: unknown field: %s in struct literal of type: %s", key.Value, s.Name)
+			}
+
+			if seen[key.Value] {
+				return fmt.Errorf("type error: duplicate field: %s in struct literal", key.Value)
+			}
+			seen[key.Value] = true
+
+			field = &types.Field{Name: key.Value, Type: key.T}
+		} else {
+			if i >= len(s.Fields) {
+				return fmt.Errorf("type error: too many values in struct literal of type: %s", s.Name)
+			}
+
+			field = s.Fields[i]
+		}
+
+		convertUntyped(v, field.Type, symbolTable)
+
+		if !assignable(v.Type(), field.Type) {
+			return fmt.Errorf(
+				"type error: cannot use %s (type %s) as type %s in field: %s",
+				v,
+				v.Type(),
+				field.Type,
+				field.Name,
+			)
+		}
+	}
+
+	if !keyed && len(lit.Values) < len(s.Fields) {
+		return fmt.Errorf("type error: too few values in struct literal of type: %s", s.Name)
+	}
+
+	return nil
+}
+
+// containsStruct checks if a value of type t holds the struct s in place.
+func containsStruct(t types.Type, s *types.Struct) bool {
+	switch t := t.(type) {
+	case *types.Struct:
+		if t == s {
+			return true
+		}
+
+		for _, f := range t.Fields {
+			if containsStruct(f.Type, s) {
+				return true
+			}
+		}
+
+		return false
+	default:
+		return false
+	}
+}
+
+// typeNodetoType converts the type node into its type.
+func typeNodetoType(t ast.TypeNode, symbolTable *symbol.Table) (types.Type, error) {
 	switch t := t.(type) {
 	case *ast.BasicType:
-		return tokenToType(t.Token)
+		if t.Token.Type == token.Ident {
+			return namedType(t.Token.Literal, symbolTable)
+		}
+
+		return tokenToType(t.Token), nil
+	case *ast.FuncType:
+		return funcTypeToSignature(t, symbolTable)
+	case *ast.TupleType:
+		var tuple types.Tuple
+		for _, tt := range t.Types {
+			typ, err := typeNodetoType(tt, symbolTable)
+			if err != nil {
+				return nil, err
+			}
+
+			tuple.Types = append(tuple.Types, typ)
+		}
+
+		return &tuple, nil
+	case *ast.ArrayType:
+		n, ok := t.Len.(*ast.IntegerLiteral)
+		if !ok {
+			return nil, fmt.Errorf("type error: array length %s must be an integer constant", t.Len)
+		}
+
+		if n.Value < 0 {
+			return nil, fmt.Errorf("type error: invalid array length %d", n.Value)
+		}
+
+		elem, err := typeNodetoType(t.Elem, symbolTable)
+		if err != nil {
+			return nil, err
+		}
+
+		return &types.Array{Len: int(n.Value), Elem: elem}, nil
+	case *ast.SliceType:
+		elem, err := typeNodetoType(t.Elem, symbolTable)
+		if err != nil {
+			return nil, err
+		}
+
+		return &types.Slice{Elem: elem}, nil
+	case *ast.PointerType:
+		elem, err := typeNodetoType(t.Elem, symbolTable)
+		if err != nil {
+			return nil, err
+		}
+
+		return &types.Pointer{Elem: elem}, nil
 	default:
-		panic("other type nodes are not implemented")
+		return nil, fmt.Errorf("checker error: type node: %T is not implemented", t)
 	}
 }
 
+// namedType returns the type declared by a type statement with the given name.
+func namedType(name string, symbolTable *symbol.Table) (types.Type, error) {
+	s, ok := symbolTable.Resolve(name)
+	if !ok {
+		return nil, fmt.Errorf("checker error: identifier %q is not defined", name)
+	}
+
+	if s.Scope != symbol.TypeScope {
+		return nil, fmt.Errorf("checker error: identifier %q is not a type", name)
+	}
+
+	t, ok := s.Type.(types.Type)
+	if !ok {
+		return nil, fmt.Errorf("checker error: type %q is used before it is declared", name)
+	}
+
+	return t, nil
+}
+
 func tokenToType(t token.Token) types.Type {
 	var typ types.Type
 	switch t.Type {
@@ -433,6 +1938,8 @@ func tokenToType(t token.Token) types.Type {
 		typ = types.Typ[types.String]
 	case token.BoolType:
 		typ = types.Typ[types.Bool]
+	case token.ByteType:
+		typ = types.Typ[types.Byte]
 	default:
 		panic(fmt.Sprintf("tokenToType can not handle this token type: %v", t))
 	}
@@ -443,43 +1950,34 @@ func tokenToType(t token.Token) types.Type {
 func funcTypeToSignature(ft *ast.FuncType, symbolTable *symbol.Table) (*types.Signature, error) {
 	var signature types.Signature
 
-	var parameter types.Type
-	if ft.Parameter != nil {
-		if err := check(ft.Parameter, symbolTable); err != nil {
+	for _, p := range ft.Parameters {
+		t, err := typeNodetoType(p.Tnode, symbolTable)
+		if err != nil {
 			return nil, err
 		}
 
-		parameter = ft.Parameter.T
-	} else {
-		parameter = types.Typ[types.Nil]
+		if t.Kind() == types.ArrayKind {
+			return nil, fmt.Errorf("type error: parameter: %q can not be an array", p.Value)
+		}
+
+		p.T = t
+		signature.Parameters = append(signature.Parameters, t)
 	}
 
-	signature.Parameter = parameter
+	if ft.Result == nil {
+		signature.Result = types.Typ[types.Nil]
+		return &signature, nil
+	}
 
-	var result types.Type
-	switch t := ft.Result.(type) {
-	case *ast.BasicType:
-		if t.Token.Type == token.Ident {
-			sym, _ := symbolTable.Resolve(t.Token.Literal)
-			if sym.Scope != symbol.TypeScope {
-				return nil, fmt.Errorf("checker error: identifier %q is not a type", t.Token.Literal)
-			}
-			result = sym.Type.(*types.Struct)
-			break
-		}
+	result, err := typeNodetoType(ft.Result, symbolTable)
+	if err != nil {
+		return nil, err
+	}
 
-		result = typeNodetoType(t)
-	case *ast.FuncType:
-		res, err := funcTypeToSignature(t, symbolTable)
-		if err != nil {
-			return nil, err
+	for _, t := range types.Values(result) {
+		if t.Kind() == types.ArrayKind {
+			return nil, fmt.Errorf("type error: function result can not be an array")
 		}
-
-		result = res
-	case *ast.StructType:
-		panic("not implemented")
-	default:
-		result = types.Typ[types.Nil]
 	}
 	signature.Result = result
 
@@ -32,8 +32,8 @@ func TestPrintStatement(t *testing.T) {
 		},
 		{
 			input:         "print 2.0 + 2",
-			expectedType:  nil,
-			expectedToErr: true,
+			expectedType:  types.Typ[types.Float],
+			expectedToErr: false,
 		},
 		{
 			input:         `print "Hello World" + 2`,
@@ -78,6 +78,7 @@ func TestVarStatement(t *testing.T) {
 			var x human
 			`,
 			expectedType: &types.Struct{
+				Name: "human",
 				Fields: []*types.Field{
 					{
 						Name: "name",
@@ -90,8 +91,8 @@ func TestVarStatement(t *testing.T) {
 		{
 			input: `var x func(int)`,
 			expectedType: &types.Signature{
-				Parameter: types.Typ[types.Int],
-				Result:    types.Typ[types.Nil],
+				Parameters: []types.Type{types.Typ[types.Int]},
+				Result:     types.Typ[types.Nil],
 			},
 			expectedToErr: false,
 		},
@@ -137,10 +138,9 @@ x = "True"`,
 			x = test
 			`,
 			expectedType: &types.Signature{
-				Parameter: types.Typ[types.Nil],
 				Result: &types.Signature{
-					Parameter: types.Typ[types.Int],
-					Result:    types.Typ[types.Nil],
+					Parameters: []types.Type{types.Typ[types.Int]},
+					Result:     types.Typ[types.Nil],
 				},
 			},
 			expectedToErr: false,
@@ -174,6 +174,7 @@ func TestTypeStatement(t *testing.T) {
 		{
 			input: `type human struct{name string}`,
 			expectedType: &types.Struct{
+				Name: "human",
 				Fields: []*types.Field{
 					{
 						Name: "name",
@@ -236,288 +237,2657 @@ func TestSelectorExpression(t *testing.T) {
 	}
 }
 
-func TestIdentifier(t *testing.T) {
-	tests := []checkerTest{
-		{
-			input: `var x int
-x`,
-			expectedType:  types.Typ[types.Int],
-			expectedToErr: false,
-		},
+func TestNestedStruct(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedOffset int
+		expectedToErr  bool
+	}{
 		{
-			input: `var x float
-x`,
-			expectedType:  types.Typ[types.Float],
-			expectedToErr: false,
+			input: `
+			type point struct { x int; y int }
+			type line struct { a point; b point }
+			var l line
+			l.b.y`,
+			expectedOffset: 8,
+			expectedToErr:  false,
 		},
 		{
-			input: `var x string
-x`,
-			expectedType:  types.Typ[types.String],
-			expectedToErr: false,
+			input: `
+			type point struct { x int; y int }
+			type line struct { name string; a [2]int; b point }
+			var l line
+			l.b`,
+			expectedOffset: 24,
+			expectedToErr:  false,
 		},
 		{
-			input: `var x bool
-x`,
-			expectedType:  types.Typ[types.Bool],
+			input: `
+			type shape struct { name string; area func(int) int }
+			var s shape
+			s.area(2)`,
 			expectedToErr: false,
 		},
-	}
-
-	runCheckerTests(t, tests)
-}
-
-func TestBlockStatement(t *testing.T) {
-	// TODO: Write better tests for Block statements so testing that a variable
-	// with the same name in different blocks can have different types.
-	tests := []checkerTest{
 		{
-			input: `{
-var x int
-}`,
-			expectedType:  types.Typ[types.Int],
-			expectedToErr: false,
+			input: `
+			type point struct { x int; y int }
+			type line struct { a point; b point }
+			var l line
+			l.a.z`,
+			expectedToErr: true,
 		},
 		{
-			input: `{
-	var x float
-}`,
-			expectedType:  types.Typ[types.Float],
-			expectedToErr: false,
+			input: `
+			type node struct { val int; next node }
+			var n node
+			n.val`,
+			expectedToErr: true,
 		},
 		{
-			input: `{
-	var x string
-}`,
-			expectedType:  types.Typ[types.String],
-			expectedToErr: false,
+			input: `
+			type line struct { a point }
+			type point struct { x int }
+			var l line
+			l.a`,
+			expectedToErr: true,
 		},
 	}
 
-	runCheckerTests(t, tests)
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			t.Fatalf("%v", p.Errors())
+		}
+		if err := resolver.Resolve(program, symbol.NewTable()); err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		t.Logf("Program: %v", program.String())
+		err := Check(program)
+
+		if err != nil && !tt.expectedToErr {
+			t.Fatalf("checker had errors which was not expected. got=%s", err)
+		}
+
+		if err == nil && tt.expectedToErr {
+			t.Fatalf("checker was assumed to fail, but it did not.")
+		}
+
+		if err != nil {
+			continue
+		}
+
+		last := program.Statements[len(program.Statements)-1]
+		sel, ok := last.(*ast.ExpressionStatement).Expression.(*ast.SelectorExpression)
+		if !ok {
+			continue
+		}
+
+		if sel.Offset != tt.expectedOffset {
+			t.Fatalf("sel.Offset was not %d. got=%d", tt.expectedOffset, sel.Offset)
+		}
+	}
 }
 
-func TestIfStatement(t *testing.T) {
-	tests := []checkerTest{
+func TestCompositeLiteral(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedToErr bool
+	}{
 		{
 			input: `
-			if 2 < 2 {
-				print 2
-			}`,
-			expectedType:  types.Typ[types.Bool],
+			type point struct { x int; y int }
+			type human struct { age int; name string; home point; next *human }
+
+			func older(h human) human {
+				return human{h.age + 1, h.name, h.home, nil}
+			}
+
+			var bob human = human{age: 3, name: "Bob"}
+			var alice human = human{30, "Alice", point{1, 2}, &bob}
+			var p *point = &point{y: 2}
+			bob = older(human{})
+			`,
 			expectedToErr: false,
 		},
 		{
 			input: `
-			if 2 < 3 {
-				print 20
-			} else {
-				print 30
-			}`,
-			expectedType:  types.Typ[types.Bool],
-			expectedToErr: false,
+			type point struct { x int; y int }
+			var p point = point{x: 1, z: 2}
+			`,
+			expectedToErr: true,
 		},
 		{
 			input: `
-			if true {
-				print 20
-			} else {
-				print 30
-			}`,
-			expectedType:  types.Typ[types.Bool],
-			expectedToErr: false,
+			type point struct { x int; y int }
+			var p point = point{x: 1, x: 2}
+			`,
+			expectedToErr: true,
 		},
 		{
 			input: `
-			if 1 {
-				print 20
-			}`,
-			expectedType:  types.Typ[types.Bool],
+			type point struct { x int; y int }
+			var p point = point{1}
+			`,
 			expectedToErr: true,
 		},
 		{
 			input: `
-			if 1 + 1 {
-				print 20
-			}`,
-			expectedType:  types.Typ[types.Bool],
+			type point struct { x int; y int }
+			var p point = point{1, 2, 3}
+			`,
 			expectedToErr: true,
 		},
-	}
-
-	runCheckerTests(t, tests)
-}
-
-func TestForStatement(t *testing.T) {
-	tests := []checkerTest{
 		{
 			input: `
-			for var i int = 0; i < 10; i = i + 1 {
-				print i
-			}`,
-			expectedToErr: false,
+			type point struct { x int; y int }
+			var p point = point{x: 1, 2}
+			`,
+			expectedToErr: true,
 		},
 		{
 			input: `
-			var i float
-			for i = 0; i < 10; i = i + 1 {
-				print i
-			}`,
+			type point struct { x int; y int }
+			var p point = point{1.5, 2}
+			`,
 			expectedToErr: true,
 		},
 		{
 			input: `
-			for var i int = 0; i + 10; i = i + 1 {
-				print i
-			}`,
+			var x int
+			var y int = x{1}
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			type point struct { x int; y int }
+			type line struct { a point; b point }
+			var p line = point{1, 2}
+			`,
 			expectedToErr: true,
 		},
 	}
 
-	runCheckerTests(t, tests)
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			t.Fatalf("%v", p.Errors())
+		}
+		if err := resolver.Resolve(program, symbol.NewTable()); err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		t.Logf("Program: %v", program.String())
+		err := Check(program)
+
+		if err != nil && !tt.expectedToErr {
+			t.Fatalf("checker had errors which was not expected. got=%s", err)
+		}
+
+		if err == nil && tt.expectedToErr {
+			t.Fatalf("checker was assumed to fail, but it did not.")
+		}
+	}
 }
 
-func TestComparsion(t *testing.T) {
-	tests := []checkerTest{
+func TestMethod(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedToErr bool
+	}{
 		{
-			input:         `2 < 2`,
-			expectedType:  types.Typ[types.Bool],
+			input: `
+			type human struct { age int; name string }
+
+			func (h human) greet() string {
+				return h.name
+			}
+
+			func (h *human) grow(years int) {
+				h.age = h.age + years
+			}
+
+			type dog struct { name string }
+
+			func (d dog) greet() string {
+				return "woof"
+			}
+
+			var bob human
+			var p *human = &bob
+			bob.grow(2)
+			p.grow(1)
+			print bob.greet()
+			print p.greet()
+			print dog{"rex"}.greet()
+			`,
 			expectedToErr: false,
 		},
 		{
-			input:         `2 == 2`,
-			expectedType:  types.Typ[types.Bool],
+			input: `
+			type human struct { age int }
+
+			func (h human) grow(years int)
+
+			var bob human
+			bob.grow(1)
+
+			func (h human) grow(years int) {
+				print years
+			}
+			`,
 			expectedToErr: false,
 		},
 		{
-			input:         `2 != 2`,
-			expectedType:  types.Typ[types.Bool],
-			expectedToErr: false,
+			input: `
+			type human struct { age int }
+			func (h human) greet() string {
+				return "hi"
+			}
+			var bob human
+			print bob.greet(1)
+			`,
+			expectedToErr: true,
 		},
 		{
-			input:         `2 < false`,
-			expectedType:  nil,
+			input: `
+			type human struct { age int }
+			func (h human) greet() string {
+				return "hi"
+			}
+			var bob human
+			print bob.greet
+			`,
 			expectedToErr: true,
 		},
 		{
-			input:         `2 < 3 == true`,
-			expectedType:  types.Typ[types.Bool],
-			expectedToErr: false,
+			input: `
+			type human struct { age int }
+			var bob human
+			bob.greet()
+			`,
+			expectedToErr: true,
 		},
 		{
-			input:         `2 < 3 == true`,
-			expectedType:  types.Typ[types.Bool],
-			expectedToErr: false,
+			input: `
+			type human struct { age int }
+			func (h human) age() int {
+				return 1
+			}
+			`,
+			expectedToErr: true,
 		},
 		{
-			input:         `true < true`,
-			expectedType:  types.Typ[types.Bool],
+			input: `
+			func (x int) double() int {
+				return x * 2
+			}
+			`,
 			expectedToErr: true,
 		},
-	}
-
-	runCheckerTests(t, tests)
-}
-
-func TestFuncStatement(t *testing.T) {
-	tests := []struct {
-		input             string
-		expectedFuncType  types.Type
-		expectedParamType types.Type
-		expectedToErr     bool
-	}{
 		{
-			input: `func none() {}`,
-			expectedFuncType: &types.Signature{
-				Parameter: types.Typ[types.Nil],
-				Result:    types.Typ[types.Nil],
-			},
+			input: `
+			type human struct { age int }
+			func (h *human) grow() {
+				h.age = h.age + 1
+			}
+			func newHuman() human {
+				var h human
+				return h
+			}
+			newHuman().grow()
+			`,
+			expectedToErr: true,
 		},
 		{
-			input: `func greeter(x string) {
-				print x
+			input: `
+			type celsius int
+
+			func (c celsius) double() celsius {
+				return c * 2
+			}
+
+			func (c *celsius) warm(by celsius) {
+				*c = *c + by
+			}
+
+			var t celsius = 20
+			var p *celsius = &t
+			t.warm(1)
+			p.warm(1)
+			print t.double()
+			print p.double()
+			`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			type celsius int
+			func (c *celsius) warm() {
+				*c = *c + 1
+			}
+			celsius(3).warm()
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			type celsius int
+			var t celsius
+			t.double()
+			`,
+			expectedToErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			t.Fatalf("%v", p.Errors())
+		}
+		if err := resolver.Resolve(program, symbol.NewTable()); err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		t.Logf("Program: %v", program.String())
+		err := Check(program)
+
+		if err != nil && !tt.expectedToErr {
+			t.Fatalf("checker had errors which was not expected. got=%s", err)
+		}
+
+		if err == nil && tt.expectedToErr {
+			t.Fatalf("checker was assumed to fail, but it did not.")
+		}
+	}
+}
+
+func TestInterface(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedToErr bool
+	}{
+		{
+			input: `
+			type shape interface { area() float; name() string }
+			type rect struct { w float; h float }
+
+			func (r rect) area() float {
+				return r.w * r.h
+			}
+
+			func (r *rect) name() string {
+				return "rect"
+			}
+
+			func describe(s shape) float {
+				print s.name()
+				return s.area()
+			}
+
+			var r rect
+			var s shape = &r
+			var ss []shape
+			ss = append(ss, &r, nil)
+			print s == nil
+			print describe(&r)
+			s = nil
+			`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			type empty interface {}
+			type point struct { x int }
+			var e empty = point{1}
+			`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			type node interface { next() node }
+			type list struct { n int }
+			func (l list) next() node {
+				return l
+			}
+			var n node = list{}
+			n = n.next()
+			`,
+			expectedToErr: false,
+		},
+		{
+			// The pointer method is not in the method set of the value.
+			input: `
+			type namer interface { name() string }
+			type rect struct { w float }
+			func (r *rect) name() string {
+				return "rect"
+			}
+			var s namer = rect{}
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			type namer interface { name() string }
+			type rect struct { w float }
+			func (r rect) name() int {
+				return 1
+			}
+			var s namer = rect{}
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			type namer interface { name() string }
+			var s namer = 2
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			type shape interface { area() float; name() string }
+			type namer interface { name() string }
+			type rect struct { w float }
+			func (r rect) area() float {
+				return r.w
+			}
+			func (r rect) name() string {
+				return "rect"
+			}
+			var s shape = rect{}
+			var n namer = s
+			n = s
+			`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			type shape interface { area() float; name() string }
+			type namer interface { name() string }
+			var n namer
+			var s shape = n
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			type namer interface { name() string }
+			type ager interface { name() int }
+			var a ager
+			var n namer = a
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			type namer interface { name() string }
+			var s namer
+			s.area()
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			type namer interface { name() string }
+			var s namer
+			var t namer
+			print s == t
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			type namer interface { name() string; name() int }
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			type namer interface { name() string }
+			var s namer
+			print s.name
+			`,
+			expectedToErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			t.Fatalf("%v", p.Errors())
+		}
+		if err := resolver.Resolve(program, symbol.NewTable()); err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		t.Logf("Program: %v", program.String())
+		err := Check(program)
+
+		if err != nil && !tt.expectedToErr {
+			t.Fatalf("checker had errors which was not expected. got=%s", err)
+		}
+
+		if err == nil && tt.expectedToErr {
+			t.Fatalf("checker was assumed to fail, but it did not.")
+		}
+	}
+}
+
+func TestFuncLiteral(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedToErr bool
+	}{
+		{
+			input: `
+			func counter() func() int {
+				var n int = 0
+				return func() int {
+					n = n + 1
+					return n
+				}
+			}
+			var next func() int = counter()
+			print next()
+			`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			func apply(f func(int) int, x int) int {
+				return f(x)
+			}
+			print apply(func(x int) int { return x * 2 }, 21)
+			`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			func adder(n int) func(int) func() int {
+				return func(x int) func() int {
+					return func() int {
+						return x + n
+					}
+				}
+			}
+			print adder(1)(2)()
+			`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			var f func(int) int = func(x int) int {
+				print x
+			}
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var f func(int) int = func(x int) int {
+				return "x"
+			}
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var f func(int) int = func(x float) int {
+				return 1
+			}
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			func counter() func() int {
+				var n string
+				return func() int {
+					return n
+				}
+			}
+			`,
+			expectedToErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			t.Fatalf("%v", p.Errors())
+		}
+		if err := resolver.Resolve(program, symbol.NewTable()); err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		t.Logf("Program: %v", program.String())
+		err := Check(program)
+
+		if err != nil && !tt.expectedToErr {
+			t.Fatalf("checker had errors which was not expected. got=%s", err)
+		}
+
+		if err == nil && tt.expectedToErr {
+			t.Fatalf("checker was assumed to fail, but it did not.")
+		}
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	tests := []checkerTest{
+		{
+			input: `var x int
+x`,
+			expectedType:  types.Typ[types.Int],
+			expectedToErr: false,
+		},
+		{
+			input: `var x float
+x`,
+			expectedType:  types.Typ[types.Float],
+			expectedToErr: false,
+		},
+		{
+			input: `var x string
+x`,
+			expectedType:  types.Typ[types.String],
+			expectedToErr: false,
+		},
+		{
+			input: `var x bool
+x`,
+			expectedType:  types.Typ[types.Bool],
+			expectedToErr: false,
+		},
+	}
+
+	runCheckerTests(t, tests)
+}
+
+func TestBlockStatement(t *testing.T) {
+	// TODO: Write better tests for Block statements so testing that a variable
+	// with the same name in different blocks can have different types.
+	tests := []checkerTest{
+		{
+			input: `{
+var x int
+}`,
+			expectedType:  types.Typ[types.Int],
+			expectedToErr: false,
+		},
+		{
+			input: `{
+	var x float
+}`,
+			expectedType:  types.Typ[types.Float],
+			expectedToErr: false,
+		},
+		{
+			input: `{
+	var x string
+}`,
+			expectedType:  types.Typ[types.String],
+			expectedToErr: false,
+		},
+	}
+
+	runCheckerTests(t, tests)
+}
+
+func TestVarInference(t *testing.T) {
+	tests := []checkerTest{
+		{
+			input:         `x := 2`,
+			expectedType:  types.Typ[types.Int],
+			expectedToErr: false,
+		},
+		{
+			input:         `var x = 2.5`,
+			expectedType:  types.Typ[types.Float],
+			expectedToErr: false,
+		},
+		{
+			input:         `x, y := "a", "b"`,
+			expectedType:  types.Typ[types.String],
+			expectedToErr: false,
+		},
+		{
+			input:         `x := 2 < 3`,
+			expectedType:  types.Typ[types.Bool],
+			expectedToErr: false,
+		},
+		{
+			input: `
+			var p *int
+			q := p`,
+			expectedType:  &types.Pointer{Elem: types.Typ[types.Int]},
+			expectedToErr: false,
+		},
+		{
+			input:         `x := nil`,
+			expectedToErr: true,
+		},
+		{
+			input:         `x, y := 1`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			x := 2
+			x = "a"`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			func test() {
+				x := 2
+				var f func() = func() {
+					x = "a"
+				}
+			}`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			func test() {}
+			x := test()`,
+			expectedToErr: true,
+		},
+		{
+			// The redeclared x keeps its type.
+			input: `
+			var x float = 1.5
+			x, y := 2, 3.5`,
+			expectedType:  types.Typ[types.Float],
+			expectedToErr: false,
+		},
+		{
+			input: `
+			x := 1
+			x, y := "a", 2`,
+			expectedToErr: true,
+		},
+	}
+
+	runCheckerTests(t, tests)
+}
+
+func TestCompoundAssign(t *testing.T) {
+	tests := []checkerTest{
+		{
+			input: `
+			var x int = 2
+			x += 3
+			x <<= 1
+			x &^= 2
+			x++`,
+			expectedType:  types.Typ[types.Int],
+			expectedToErr: false,
+		},
+		{
+			input: `
+			var s string = "a"
+			s += "b"`,
+			expectedType:  types.Typ[types.String],
+			expectedToErr: false,
+		},
+		{
+			input: `
+			var f float = 2.5
+			f /= 2.0
+			f--`,
+			expectedType:  types.Typ[types.Float],
+			expectedToErr: false,
+		},
+		{
+			input: `
+			var x int = 2
+			x += 2.5`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var s string = "a"
+			s -= "b"`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var s string = "a"
+			s++`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var b bool
+			b++`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			const c = 1
+			c += 1`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			const c = 1
+			c++`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var s string = "a"
+			s[0] += byte(1)`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var x int = 2
+			x += 1, 2`,
+			expectedToErr: true,
+		},
+	}
+
+	runCheckerTests(t, tests)
+}
+
+func TestIfStatement(t *testing.T) {
+	tests := []checkerTest{
+		{
+			input: `
+			if 2 < 2 {
+				print 2
+			}`,
+			expectedType:  types.Typ[types.Bool],
+			expectedToErr: false,
+		},
+		{
+			input: `
+			if 2 < 3 {
+				print 20
+			} else {
+				print 30
+			}`,
+			expectedType:  types.Typ[types.Bool],
+			expectedToErr: false,
+		},
+		{
+			input: `
+			if true {
+				print 20
+			} else {
+				print 30
+			}`,
+			expectedType:  types.Typ[types.Bool],
+			expectedToErr: false,
+		},
+		{
+			input: `
+			if 1 {
+				print 20
+			}`,
+			expectedType:  types.Typ[types.Bool],
+			expectedToErr: true,
+		},
+		{
+			input: `
+			if 1 + 1 {
+				print 20
+			}`,
+			expectedType:  types.Typ[types.Bool],
+			expectedToErr: true,
+		},
+		{
+			input: `
+			if 2 < 3 {
+				print 20
+			} else if 3 < 4 {
+				print 30
+			} else {
+				print 40
+			}`,
+			expectedType:  types.Typ[types.Bool],
+			expectedToErr: false,
+		},
+		{
+			input: `
+			if 2 < 3 {
+				print 20
+			} else if 3 {
+				print 30
+			}`,
+			expectedType:  types.Typ[types.Bool],
+			expectedToErr: true,
+		},
+	}
+
+	runCheckerTests(t, tests)
+}
+
+func TestForStatement(t *testing.T) {
+	tests := []checkerTest{
+		{
+			input: `
+			for var i int = 0; i < 10; i = i + 1 {
+				print i
+			}`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			var i string
+			for i = 0; i < 10; i = i + 1 {
+				print i
+			}`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			for var i int = 0; i + 10; i = i + 1 {
+				print i
+			}`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			for {
+				break
+			}`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			for var i int = 0; ; {
+				print i
+			}`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			for 2 < 3 {
+				print 2
+			}`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			for 2 + 3 {
+				print 2
+			}`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var j float
+			for ; ; j = "2" {
+			}`,
+			expectedToErr: true,
+		},
+	}
+
+	runCheckerTests(t, tests)
+}
+
+func TestBranchStatement(t *testing.T) {
+	tests := []checkerTest{
+		{
+			input: `
+			outer: for var i int = 0; i < 10; i = i + 1 {
+				for var j int = 0; j < 10; j = j + 1 {
+					if j == i {
+						continue outer
+					}
+					if j > 5 {
+						break
+					}
+				}
+				continue
+			}`,
+			expectedToErr: false,
+		},
+		{
+			input:         `break`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			func test() {
+				continue
+			}`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			for var i int = 0; i < 10; i = i + 1 {
+				break outer
+			}`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			outer: for var i int = 0; i < 10; i = i + 1 {
+			}
+			for var j int = 0; j < 10; j = j + 1 {
+				continue outer
+			}`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			outer: for var i int = 0; i < 10; i = i + 1 {
+				outer: for var j int = 0; j < 10; j = j + 1 {
+				}
+			}`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			for var i int = 0; i < 10; i = i + 1 {
+				var f func() = func() {
+					break
+				}
+			}`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			L: for var i int = 0; i < 10; i = i + 1 {
+			}
+			L: for var j int = 0; j < 10; j = j + 1 {
+			}`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			func f(x int) {
+				L: for x < 10 {
+					x = x + 1
+				}
+				L: switch x {
+				}
+			}`,
+			expectedToErr: true,
+		},
+		{
+			// Every function has labels of its own.
+			input: `
+			L: for var i int = 0; i < 10; i = i + 1 {
+			}
+			func f() {
+				L: for {
+					break L
+				}
+			}
+			func h() {
+				L: for {
+					break L
+				}
+			}`,
+			expectedType:  &types.Signature{Result: types.Typ[types.Nil]},
+			expectedToErr: false,
+		},
+	}
+
+	runCheckerTests(t, tests)
+}
+
+func TestSwitchStatement(t *testing.T) {
+	tests := []checkerTest{
+		{
+			input: `
+			var x int = 2
+			switch x {
+			case 0, 1:
+				print x
+			case 2, -3:
+			default:
+				print x
+			}`,
+			expectedType:  types.Typ[types.Int],
+			expectedToErr: false,
+		},
+		{
+			input: `
+			var x int = 2
+			switch {
+			case x < 3:
+			case x == 5:
+			}`,
+			expectedType:  types.Typ[types.Int],
+			expectedToErr: false,
+		},
+		{
+			input: `
+			var s string = "a"
+			switch s {
+			case "a", "b":
+			}`,
+			expectedType:  types.Typ[types.String],
+			expectedToErr: false,
+		},
+		{
+			input: `
+			var p *int
+			switch p {
+			case nil:
+			}`,
+			expectedType:  &types.Pointer{Elem: types.Typ[types.Int]},
+			expectedToErr: false,
+		},
+		{
+			input: `
+			var x int = 2
+			switch x {
+			case "a":
+			}`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var x int = 2
+			switch {
+			case x:
+			}`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var x int = 2
+			switch x {
+			default:
+			default:
+			}`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var x int = 2
+			switch x {
+			case 1:
+			case 1:
+			}`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var f func()
+			switch f {
+			}`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			outer: for var i int = 0; i < 10; i = i + 1 {
+				inner: switch i {
+				case 1:
+					continue outer
+				case 2:
+					break inner
+				}
+			}`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			for var i int = 0; i < 10; i = i + 1 {
+				inner: switch i {
+				case 1:
+					continue inner
+				}
+			}`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var x int = 2
+			switch x {
+			case 1:
+				continue
+			}`,
+			expectedToErr: true,
+		},
+	}
+
+	runCheckerTests(t, tests)
+}
+
+func TestComparsion(t *testing.T) {
+	tests := []checkerTest{
+		{
+			input:         `2 < 2`,
+			expectedType:  types.Typ[types.Bool],
+			expectedToErr: false,
+		},
+		{
+			input:         `2 == 2`,
+			expectedType:  types.Typ[types.Bool],
+			expectedToErr: false,
+		},
+		{
+			input:         `2 != 2`,
+			expectedType:  types.Typ[types.Bool],
+			expectedToErr: false,
+		},
+		{
+			input:         `2 < false`,
+			expectedType:  nil,
+			expectedToErr: true,
+		},
+		{
+			input:         `2 < 3 == true`,
+			expectedType:  types.Typ[types.Bool],
+			expectedToErr: false,
+		},
+		{
+			input:         `2 < 3 == true`,
+			expectedType:  types.Typ[types.Bool],
+			expectedToErr: false,
+		},
+		{
+			input:         `true < true`,
+			expectedType:  types.Typ[types.Bool],
+			expectedToErr: true,
+		},
+		{
+			input:         `2 <= 3`,
+			expectedType:  types.Typ[types.Bool],
+			expectedToErr: false,
+		},
+		{
+			input:         `2 > 3`,
+			expectedType:  types.Typ[types.Bool],
+			expectedToErr: false,
+		},
+		{
+			input:         `2 >= 3`,
+			expectedType:  types.Typ[types.Bool],
+			expectedToErr: false,
+		},
+		{
+			input:         `2.5 >= 3.0`,
+			expectedType:  types.Typ[types.Bool],
+			expectedToErr: false,
+		},
+		{
+			input:         `"a" < "b"`,
+			expectedType:  types.Typ[types.Bool],
+			expectedToErr: false,
+		},
+		{
+			input:         `"a" == "b"`,
+			expectedType:  types.Typ[types.Bool],
+			expectedToErr: false,
+		},
+		{
+			input:         `true != false`,
+			expectedType:  types.Typ[types.Bool],
+			expectedToErr: false,
+		},
+		{
+			input:         `true >= false`,
+			expectedType:  nil,
+			expectedToErr: true,
+		},
+		{
+			input:         `2 < 2.0`,
+			expectedType:  types.Typ[types.Bool],
+			expectedToErr: false,
+		},
+		{
+			input:         `"2" >= 2`,
+			expectedType:  nil,
+			expectedToErr: true,
+		},
+	}
+
+	runCheckerTests(t, tests)
+}
+
+func TestStructComparison(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedToErr bool
+	}{
+		{
+			input: `
+			type inner struct { f float; s string }
+			type point struct { x int; in inner; p *int }
+			var a point
+			var b point
+			print a == b
+			print a != b
+			`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			type point struct { x int }
+			var a point
+			var b point
+			print a < b
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			type point struct { x int }
+			var a point
+			var b point
+			print a + b
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			type list struct { xs []int }
+			var a list
+			var b list
+			print a == b
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			type box struct { xs [2]int }
+			var a box
+			var b box
+			print a == b
+			`,
+			expectedToErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			t.Fatalf("%v", p.Errors())
+		}
+		if err := resolver.Resolve(program, symbol.NewTable()); err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		err := Check(program)
+
+		if err != nil && !tt.expectedToErr {
+			t.Fatalf("checker had errors which was not expected. got=%s", err)
+		}
+
+		if err == nil && tt.expectedToErr {
+			t.Fatalf("checker was assumed to fail, but it did not.")
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedToErr bool
+	}{
+		{
+			input: `
+			var s string = "hello"
+			var b byte = s[0]
+			var n int = len(s)
+			print b == s[n-1]
+			print b < "z"[0]
+			`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			var b byte = "a"[0]
+			b = b + b
+			b = b << b
+			print -b
+			`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			var s string = "hello"
+			s[0] = s[1]
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var s string = "hello"
+			var p *byte = &s[0]
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var s string = "hello"
+			var n int = s[0]
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var s string = "hello"
+			print cap(s)
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var s string = "hello"
+			print s["h"]
+			`,
+			expectedToErr: true,
+		},
+		{
+			// A byte is an index too.
+			input: `
+			var s string = "hello"
+			var i byte = 1
+			var a [3]int
+			var xs []int = make([]int, 3)
+			print s[i]
+			print s[i:]
+			a[i] = xs[i+1]
+			xs = xs[:i]
+			`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			var s string = "hello"
+			print s[0] + 300
+			`,
+			expectedToErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			t.Fatalf("%v", p.Errors())
+		}
+		if err := resolver.Resolve(program, symbol.NewTable()); err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		t.Logf("Program: %v", program.String())
+		err := Check(program)
+
+		if err != nil && !tt.expectedToErr {
+			t.Fatalf("checker had errors which was not expected. got=%s", err)
+		}
+
+		if err == nil && tt.expectedToErr {
+			t.Fatalf("checker was assumed to fail, but it did not.")
+		}
+	}
+}
+
+func TestConversion(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedToErr bool
+	}{
+		{
+			input: `
+			var i int = 2
+			var f float = float(i)
+			i = int(f * 1.5)
+			var b byte = byte(i)
+			i = int(b)
+			f = float(b)
+			b = byte(f)
+			var s string = string(b)
+			`,
+			expectedToErr: false,
+		},
+		{
+			// A named basic type converts to and from its basic type.
+			input: `
+			type celsius float
+			type name string
+			type id int
+			type small id
+			var c celsius = 100
+			var f float = float(c) + 0.5
+			c = celsius(f) * 2
+			var n name = "bob"
+			var s string = string(n) + "!"
+			n = n + name(s)
+			var i id = 3
+			i++
+			var b small = small(i)
+			const k celsius = 1.5
+			c = k + c
+			`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			type celsius float
+			type fahrenheit float
+			var c celsius
+			var f fahrenheit
+			print c + f
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			type celsius float
+			var c celsius
+			var f float = c
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			type name string
+			var n name
+			var i int = int(n)
+			`,
+			expectedToErr: true,
+		},
+		{
+			// A named basic type has methods too.
+			input: `
+			type celsius float
+			func (c celsius) kelvin() float {
+				return float(c) + 273.15
+			}
+			`,
+			expectedToErr: false,
+		},
+		{
+			// A constant must be a value of the number it is converted to.
+			input: `
+			const k = 65
+			var b byte = byte(k)
+			var i int = int(3.0)
+			var f float = float(1 << 3)
+			`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			var b byte = byte(300)
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var b byte = byte(321.5)
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			const k = 300
+			var b byte = byte(k)
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var i int = int(2.5)
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			type point struct { x int; y int }
+			type vec struct { x int; y int }
+			var p point
+			var v vec = vec(p)
+			p = point(v)
+			p = point(p)
+			`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			type namer interface { name() string }
+			type human struct { n string }
+			func (h human) name() string {
+				return h.n
+			}
+			var n namer = namer(human{"Bob"})
+			`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			var i int = 2.5
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var s string = string(65)
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var i int = int("65")
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var b bool = bool(1)
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			type point struct { x int; y int }
+			type vec struct { y int; x int }
+			var p point
+			var v vec = vec(p)
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			type point struct { x int; y int }
+			var p point = point(1, 2)
+			`,
+			expectedToErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			t.Fatalf("%v", p.Errors())
+		}
+		if err := resolver.Resolve(program, symbol.NewTable()); err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		t.Logf("Program: %v", program.String())
+		err := Check(program)
+
+		if err != nil && !tt.expectedToErr {
+			t.Fatalf("checker had errors which was not expected. got=%s", err)
+		}
+
+		if err == nil && tt.expectedToErr {
+			t.Fatalf("checker was assumed to fail, but it did not.")
+		}
+	}
+}
+
+func TestConst(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedToErr bool
+	}{
+		{
+			input: `
+			const pi float = 3.14
+			const ( a = iota; b; c )
+			const big = 1 << 40
+			const greeting = "hello, " + "world"
+			const ok = a < b && !false
+			var f float = pi * 2.0
+			var i int = c * big
+			var s string = greeting
+			var t bool = ok
+			`,
+			expectedToErr: false,
+		},
+		{
+			// An untyped constant is given the type of its use.
+			input: `
+			const two = 2
+			const half = 0.5
+			var f float = two
+			var b byte = two
+			var i int = two
+			b = b + two
+			f = two * half
+			i = -two
+			var s []float
+			s = append(s, two)
+			func scale(x float) float {
+				return x * two
+			}
+			f = scale(two)
+			`,
+			expectedToErr: false,
+		},
+		{
+			// Literals and constant expressions are untyped constants too.
+			input: `
+			const c = 2
+			var f float = c * 2
+			var g float = 2
+			var b byte = 65
+			f = f + 1
+			b = b + 1
+			g = 7 / 2
+			`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			var b byte = 256
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			const c = 200
+			var b byte = c + 100
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var i int = 1.5 * 3
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			const ( kb int = 1 << (10 * (iota + 1)); mb; gb )
+			var i int = gb
+			`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			const a = 1
+			a = 2
+			`,
+			expectedToErr: true,
+		},
+		{
+			input:         `print iota`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var x int = 2
+			const a = x
+			`,
+			expectedToErr: true,
+		},
+		{
+			input:         `const b byte = 256`,
+			expectedToErr: true,
+		},
+		{
+			input:         `const i int = 2.5`,
+			expectedToErr: true,
+		},
+		{
+			input:         `const f float = "pi"`,
+			expectedToErr: true,
+		},
+		{
+			input:         `const a = 1 / 0`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			const pi = 3.14
+			var i int = pi
+			`,
+			expectedToErr: true,
+		},
+		{
+			// A typed constant is not given the type of its use.
+			input: `
+			const two int = 2
+			var f float = two
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			const a = 1
+			var p *int = &a
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			type point struct { x int }
+			const p point = point{x: 1}
+			`,
+			expectedToErr: true,
+		},
+		{
+			input:         `print 1 << 64`,
+			expectedToErr: true,
+		},
+		{
+			input:         `print 9223372036854775807 + 1`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			const big = 1 << 64
+			print big >> 60
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			const min = -9223372036854775807 - 1
+			print min / -1
+			`,
+			expectedToErr: true,
+		},
+		{
+			input:         `print 1 / 0`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			const max = 9223372036854775807
+			print max - 1 + 1
+			print 1 << 62
+			print 0 << 64
+			print -1 >> 64
+			`,
+			expectedToErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			t.Fatalf("%v", p.Errors())
+		}
+		if err := resolver.Resolve(program, symbol.NewTable()); err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		t.Logf("Program: %v", program.String())
+		err := Check(program)
+
+		if err != nil && !tt.expectedToErr {
+			t.Fatalf("checker had errors which was not expected. got=%s", err)
+		}
+
+		if err == nil && tt.expectedToErr {
+			t.Fatalf("checker was assumed to fail, but it did not.")
+		}
+	}
+}
+
+func TestLogical(t *testing.T) {
+	tests := []checkerTest{
+		{
+			input:         `true && false`,
+			expectedType:  types.Typ[types.Bool],
+			expectedToErr: false,
+		},
+		{
+			input:         `2 < 3 || 3 == 4`,
+			expectedType:  types.Typ[types.Bool],
+			expectedToErr: false,
+		},
+		{
+			input:         `!true`,
+			expectedType:  types.Typ[types.Bool],
+			expectedToErr: false,
+		},
+		{
+			input:         `!(1 < 2) && !false`,
+			expectedType:  types.Typ[types.Bool],
+			expectedToErr: false,
+		},
+		{
+			input:         `1 && 2`,
+			expectedType:  nil,
+			expectedToErr: true,
+		},
+		{
+			input:         `true || 2`,
+			expectedType:  nil,
+			expectedToErr: true,
+		},
+		{
+			input:         `!2`,
+			expectedType:  nil,
+			expectedToErr: true,
+		},
+		{
+			input:         `!"hello"`,
+			expectedType:  nil,
+			expectedToErr: true,
+		},
+	}
+
+	runCheckerTests(t, tests)
+}
+
+func TestFuncStatement(t *testing.T) {
+	tests := []struct {
+		input             string
+		expectedFuncType  types.Type
+		expectedParamType types.Type
+		expectedToErr     bool
+	}{
+		{
+			input: `func none() {}`,
+			expectedFuncType: &types.Signature{
+				Result: types.Typ[types.Nil],
+			},
+		},
+		{
+			input: `func greeter(x string) {
+				print x
+			}`,
+			expectedFuncType: &types.Signature{
+				Parameters: []types.Type{types.Typ[types.String]},
+				Result:     types.Typ[types.Nil],
+			},
+			expectedParamType: types.Typ[types.String],
+			expectedToErr:     false,
+		},
+		{
+			input: `func greeter(x string) string {
+				print x
+			}`,
+			expectedFuncType:  &types.Signature{},
+			expectedParamType: types.Typ[types.String],
+			expectedToErr:     true,
+		},
+		{
+			input: `func greeter(x string) string {
+				print x
+				return x
+			}`,
+			expectedFuncType: &types.Signature{
+				Parameters: []types.Type{types.Typ[types.String]},
+				Result:     types.Typ[types.String],
+			},
+			expectedParamType: types.Typ[types.String],
+			expectedToErr:     false,
+		},
+		{
+			input: `
+			func greeter(x int) {
+				print x + "Hello"
+			}`,
+			expectedFuncType:  &types.Signature{},
+			expectedParamType: types.Typ[types.Int],
+			expectedToErr:     true,
+		},
+		{
+			input: `
+			func greeter(x int) string {
+				print x + "Hello"
+			}`,
+			expectedFuncType:  &types.Signature{},
+			expectedParamType: types.Typ[types.Int],
+			expectedToErr:     true,
+		},
+		{
+			input: `
+			func test(int) int
+
+			func test(x int) int {
+				print x
+				if x == 10 {
+					return 1
+				}
+
+				return x
+			}
+			`,
+			expectedFuncType: &types.Signature{
+				Parameters: []types.Type{types.Typ[types.Int]},
+				Result:     types.Typ[types.Int],
+			},
+			expectedParamType: types.Typ[types.Int],
+			expectedToErr:     false,
+		},
+		{
+			input: `
+			func test(int) int
+
+			func test(x int) int {
+				print x
+				if x == 10 {
+					return "I WILL FAIL" // returns wrong value!
+				}
+
+				return x
+			}
+			`,
+			expectedFuncType:  &types.Signature{},
+			expectedParamType: types.Typ[types.Int],
+			expectedToErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			t.Logf("%v", p.Errors())
+		}
+		resolver.Resolve(program, symbol.NewTable())
+
+		t.Logf("Program: %v", program.String())
+		err := Check(program)
+		if err != nil && tt.expectedToErr {
+			continue
+		}
+
+		t.Logf("Program after been checked: %v", program.String())
+
+		if err != nil && !tt.expectedToErr {
+			t.Fatalf("checker had errors which was not expected. got=%s", err)
+		}
+
+		if err == nil && tt.expectedToErr {
+			t.Fatalf("checker was assumed to fail, but it did not.")
+		}
+
+		funcStmt, _ := program.Statements[0].(*ast.FuncStatement)
+
+		if !reflect.DeepEqual(funcStmt.Name.T, tt.expectedFuncType) {
+			t.Fatalf("funcStmt.Name.T is not %q. got=%q", tt.expectedFuncType.String(), funcStmt.Name.T.String())
+		}
+
+		if len(funcStmt.Signature.Parameters) != 0 && !reflect.DeepEqual(funcStmt.Signature.Parameters[0].T, tt.expectedParamType) {
+			t.Fatalf("funcStmt.Parameters[0].T is not %s. got=%s", tt.expectedParamType, funcStmt.Signature.Parameters[0].T)
+		}
+	}
+}
+
+func TestTerminatingStatement(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedToErr bool
+	}{
+		{
+			input: `
+			func sign(x int) int {
+				if x < 0 {
+					return -1
+				} else if x == 0 {
+					return 0
+				} else {
+					return 1
+				}
+			}
+			`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			func sign(x int) int {
+				if x < 0 {
+					return -1
+				} else if x == 0 {
+					return 0
+				}
+			}
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			func name(x int) string {
+				switch x {
+				case 1:
+					return "one"
+				default:
+					return "many"
+				}
+			}
+			`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			func name(x int) string {
+				switch x {
+				case 1:
+					return "one"
+				case 2:
+					return "two"
+				}
+			}
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			func name(x int) string {
+				switch x {
+				case 1:
+					if x > 0 {
+						break
+					}
+					return "one"
+				default:
+					return "many"
+				}
+			}
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			func loop(x int) int {
+				for {
+					x++
+				}
+			}
+			`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			func loop(x int) int {
+				for {
+					switch x {
+					case 1:
+						break
+					}
+					for {
+						break
+					}
+				}
+			}
+			`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			func loop(x int) int {
+				outer: for {
+					for {
+						break outer
+					}
+				}
+			}
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			func loop(x int) int {
+				for x < 10 {
+					return x
+				}
+			}
+			`,
+			expectedToErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			t.Fatalf("%v", p.Errors())
+		}
+		if err := resolver.Resolve(program, symbol.NewTable()); err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		t.Logf("Program: %v", program.String())
+		err := Check(program)
+
+		if err != nil && !tt.expectedToErr {
+			t.Fatalf("checker had errors which was not expected. got=%s", err)
+		}
+
+		if err == nil && tt.expectedToErr {
+			t.Fatalf("checker was assumed to fail, but it did not.")
+		}
+	}
+}
+
+func TestFunctionPrototypes(t *testing.T) {
+	tests := []struct {
+		input             string
+		progIndex         int
+		expectedFuncType  types.Type
+		expectedParamType types.Type
+		expectedToErr     bool
+	}{
+		{
+			input: `
+			func greeter(string)
+
+			greeter("hejsa")
+
+			func greeter(x string) {
+				print x
 			}`,
 			expectedFuncType: &types.Signature{
-				Parameter: types.Typ[types.String],
-				Result:    types.Typ[types.Nil],
+				Parameters: []types.Type{types.Typ[types.String]},
+				Result:     types.Typ[types.Nil],
+			},
+			expectedParamType: types.Typ[types.String],
+			expectedToErr:     false,
+		},
+		{
+			input: `
+					type human struct { age int }
+
+					func test(human)
+
+					func test(h human) {
+						print h.age
+					}
+					`,
+			progIndex: 1,
+			expectedFuncType: &types.Signature{
+				Parameters: []types.Type{
+					&types.Struct{
+						Name: "human",
+						Fields: []*types.Field{
+							{
+								Name: "age",
+								Type: types.Typ[types.Int],
+							},
+						},
+					},
+				},
+				Result: types.Typ[types.Nil],
+			},
+			expectedParamType: &types.Struct{
+				Name: "human",
+				Fields: []*types.Field{
+					{
+						Name: "age",
+						Type: types.Typ[types.Int],
+					},
+				},
+			},
+			expectedToErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			t.Fatalf("%v", p.Errors())
+		}
+		if err := resolver.Resolve(program, symbol.NewTable()); err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		t.Logf("Program: %v", program.String())
+		err := Check(program)
+		if err != nil && tt.expectedToErr {
+			continue
+		}
+
+		t.Logf("Program after been checked: %v", program.String())
+
+		if err != nil && !tt.expectedToErr {
+			t.Fatalf("checker had errors which was not expected. got=%s", err)
+		}
+
+		if err == nil && tt.expectedToErr {
+			t.Fatalf("checker was assumed to fail, but it did not.")
+		}
+
+		funcStmt, _ := program.Statements[tt.progIndex].(*ast.FuncStatement)
+
+		if !reflect.DeepEqual(funcStmt.Name.T, tt.expectedFuncType) {
+			t.Fatalf("funcStmt.Name.T is not %q. got=%q", tt.expectedFuncType.String(), funcStmt.Name.T.String())
+		}
+
+		if len(funcStmt.Signature.Parameters) != 0 && !reflect.DeepEqual(funcStmt.Signature.Parameters[0].T, tt.expectedParamType) {
+			t.Fatalf("funcStmt.Parameters[0].T is not %s. got=%s", tt.expectedParamType, funcStmt.Signature.Parameters[0].T)
+		}
+	}
+
+}
+
+func TestFuncStatementWithStruct(t *testing.T) {
+	tests := []struct {
+		input             string
+		expectedFuncType  types.Type
+		expectedParamType types.Type
+		expectedToErr     bool
+	}{
+		{
+			input: `
+					type human struct{name string}
+
+					func greeter(x human) human {
+						return x
+					}`,
+			expectedFuncType: &types.Signature{
+				Parameters: []types.Type{
+					&types.Struct{
+						Name: "human",
+						Fields: []*types.Field{
+							{
+								Name: "name",
+								Type: types.Typ[types.String],
+							},
+						},
+					},
+				},
+				Result: &types.Struct{
+					Name: "human",
+					Fields: []*types.Field{
+						{
+							Name: "name",
+							Type: types.Typ[types.String],
+						},
+					},
+				},
 			},
-			expectedParamType: types.Typ[types.String],
-			expectedToErr:     false,
+			expectedParamType: &types.Struct{
+				Name: "human",
+				Fields: []*types.Field{
+					{
+						Name: "name",
+						Type: types.Typ[types.String],
+					},
+				},
+			},
+			expectedToErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			t.Logf("%v", p.Errors())
+		}
+		resolver.Resolve(program, symbol.NewTable())
+
+		t.Logf("Program: %v", program.String())
+
+		err := Check(program)
+		if err != nil && tt.expectedToErr {
+			continue
+		}
+
+		t.Logf("Program after been checked: %v", program.String())
+
+		if err != nil && !tt.expectedToErr {
+			t.Fatalf("checker had errors which was not expected. got=%s", err)
+		}
+
+		if err == nil && tt.expectedToErr {
+			t.Fatalf("checker was assumed to fail, but it did not.")
+		}
+
+		funcStmt, _ := program.Statements[1].(*ast.FuncStatement)
+
+		if !reflect.DeepEqual(funcStmt.Name.T, tt.expectedFuncType) {
+			t.Fatalf("funcStmt.Name.T is not %q. got=%q", tt.expectedFuncType.String(), funcStmt.Name.T.String())
+		}
+
+		if !reflect.DeepEqual(funcStmt.Signature.Parameters[0].T, tt.expectedParamType) {
+			t.Fatalf("funcStmt.Parameters[0].T is not %s. got=%s", tt.expectedParamType, funcStmt.Signature.Parameters[0].T)
+		}
+	}
+}
+
+func TestCallExpression(t *testing.T) {
+	tests := []struct {
+		input            string
+		progIndex        int
+		expectedCallType types.Type
+	}{
+		{
+			input: `func compile(x string) {
+				print x
+			}
+
+			compile("Hello")`,
+			progIndex:        1,
+			expectedCallType: types.Typ[types.Nil],
+		},
+		{
+			input: `func compile(x string) string{
+				return x
+			}
+
+			compile("Hello")`,
+			progIndex:        1,
+			expectedCallType: types.Typ[types.String],
+		},
+		{
+			input: `func compile(x string) string{
+				return x
+			}
+
+			compile("Hello Compiler World")`,
+			progIndex:        1,
+			expectedCallType: types.Typ[types.String],
+		},
+		{
+			input: `
+			var x func(int)
+			func test(int)
+
+			x = test
+			x(100)
+			`,
+			progIndex:        3,
+			expectedCallType: types.Typ[types.Nil],
+		},
+		{
+			input: `
+			var x func(int) int
+			func test(int) int
+
+			x = test
+			x(100)
+			`,
+			progIndex:        3,
+			expectedCallType: types.Typ[types.Int],
+		},
+		{
+			input: `func add(x int, y float) float {
+				return y
+			}
+
+			add(1, 2.0)`,
+			progIndex:        1,
+			expectedCallType: types.Typ[types.Float],
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			t.Logf("%v", p.Errors())
+		}
+		resolver.Resolve(program, symbol.NewTable())
+
+		t.Logf("Program: %v", program.String())
+		t.Logf("Program length: %v", len(program.Statements))
+
+		err := Check(program)
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		t.Logf("Program after been checked: %v", program.String())
+
+		exprStmt, _ := program.Statements[tt.progIndex].(*ast.ExpressionStatement)
+		call, _ := exprStmt.Expression.(*ast.CallExpression)
+
+		if call.T != tt.expectedCallType {
+			t.Fatalf("Call expression was evalutated to the wrong type, expected=%v, got=%s", tt.expectedCallType, call.T)
+		}
+	}
+}
+
+func TestCallArguments(t *testing.T) {
+	tests := []checkerTest{
+		{
+			input: `
+			func add(x int, y float) float {
+				return y
+			}
+
+			print add(1)
+			`,
+			expectedType:  nil,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			func add(x int, y float) float {
+				return y
+			}
+
+			print add(1, 2.0, 3)
+			`,
+			expectedType:  nil,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			func add(x int, y float) float {
+				return y
+			}
+
+			print add(1.5, 2)
+			`,
+			expectedType:  nil,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var f func(int, string)
+			f(1, 2)
+			`,
+			expectedType:  nil,
+			expectedToErr: true,
+		},
+	}
+
+	runCheckerTests(t, tests)
+}
+
+func TestMultipleResults(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedToErr bool
+	}{
+		{
+			input: `
+			func divmod(a int, b int) (int, float) {
+				return a / b, 2.5
+			}
+
+			var q int
+			var r float
+			q, r = divmod(7, 2)
+			`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			func divmod(a int, b int) (int, int) {
+				return a / b, a
+			}
+
+			func pass() (int, int) {
+				return divmod(1, 2)
+			}
+
+			var q, r int = divmod(7, 2)
+			q, r = r, q
+			divmod(1, 2)
+			`,
+			expectedToErr: false,
 		},
 		{
-			input: `func greeter(x string) string {
-				print x
+			input: `
+			func divmod(a int, b int) (int, int) {
+				return a / b
 			}`,
-			expectedFuncType:  &types.Signature{},
-			expectedParamType: types.Typ[types.String],
-			expectedToErr:     true,
+			expectedToErr: true,
 		},
 		{
-			input: `func greeter(x string) string {
-				print x
-				return x
+			input: `
+			func divmod(a int, b int) (int, int) {
+				return a / b, 2.5
 			}`,
-			expectedFuncType: &types.Signature{
-				Parameter: types.Typ[types.String],
-				Result:    types.Typ[types.String],
-			},
-			expectedParamType: types.Typ[types.String],
-			expectedToErr:     false,
+			expectedToErr: true,
 		},
 		{
 			input: `
-			func greeter(x int) {
-				print x + "Hello"
-			}`,
-			expectedFuncType:  &types.Signature{},
-			expectedParamType: types.Typ[types.Int],
-			expectedToErr:     true,
+			func divmod(a int, b int) (int, int) {
+				return a / b, a
+			}
+
+			var q, r, s int = divmod(7, 2)
+			`,
+			expectedToErr: true,
 		},
 		{
 			input: `
-			func greeter(x int) string {
-				print x + "Hello"
-			}`,
-			expectedFuncType:  &types.Signature{},
-			expectedParamType: types.Typ[types.Int],
-			expectedToErr:     true,
+			func divmod(a int, b int) (int, int) {
+				return a / b, a
+			}
+
+			var q int
+			q = divmod(7, 2)
+			`,
+			expectedToErr: true,
 		},
 		{
 			input: `
-			func test(int) int
-
-			func test(x int) int {
-				print x
-				if x == 10 {
-					return 1
-				}
-
-				return x
+			func divmod(a int, b int) (int, int) {
+				return a / b, a
 			}
+
+			print divmod(7, 2) + 1
 			`,
-			expectedFuncType: &types.Signature{
-				Parameter: types.Typ[types.Int],
-				Result:    types.Typ[types.Int],
-			},
-			expectedParamType: types.Typ[types.Int],
-			expectedToErr:     false,
+			expectedToErr: true,
 		},
 		{
 			input: `
-			func test(int) int
-
-			func test(x int) int {
-				print x
-				if x == 10 {
-					return "I WILL FAIL" // returns wrong value!
-				}
-
-				return x
-			}
+			var x, y int
+			x, y = 1
 			`,
-			expectedFuncType:  &types.Signature{},
-			expectedParamType: types.Typ[types.Int],
-			expectedToErr:     true,
+			expectedToErr: true,
 		},
 	}
 
@@ -526,17 +2896,14 @@ func TestFuncStatement(t *testing.T) {
 		p := parser.New(l)
 		program := p.ParseProgram()
 		if len(p.Errors()) != 0 {
-			t.Logf("%v", p.Errors())
+			t.Fatalf("%v", p.Errors())
+		}
+		if err := resolver.Resolve(program, symbol.NewTable()); err != nil {
+			t.Fatalf("%v", err)
 		}
-		resolver.Resolve(program, symbol.NewTable())
 
 		t.Logf("Program: %v", program.String())
 		err := Check(program)
-		if err != nil && tt.expectedToErr {
-			continue
-		}
-
-		t.Logf("Program after been checked: %v", program.String())
 
 		if err != nil && !tt.expectedToErr {
 			t.Fatalf("checker had errors which was not expected. got=%s", err)
@@ -545,75 +2912,250 @@ func TestFuncStatement(t *testing.T) {
 		if err == nil && tt.expectedToErr {
 			t.Fatalf("checker was assumed to fail, but it did not.")
 		}
+	}
+}
 
-		funcStmt, _ := program.Statements[0].(*ast.FuncStatement)
+func TestArray(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedToErr bool
+	}{
+		{
+			input: `
+			type point struct {
+				x int
+			}
 
-		if !reflect.DeepEqual(funcStmt.Name.T, tt.expectedFuncType) {
-			t.Fatalf("funcStmt.Name.T is not %q. got=%q", tt.expectedFuncType.String(), funcStmt.Name.T.String())
+			var a [3]int
+			var b [2][2]float
+			var p [2]point
+			var i int = 1
+			a[i] = a[0] + 2
+			b[1][i] = 2.5
+			p[1].x = a[2]
+			print p[0].x
+			`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			var a [3]int
+			var b [3]int
+			a = b
+			`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			var a [3]int
+			a[3] = 1
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var a [3]int
+			a[-1] = 1
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			const k = -1
+			var a [3]int
+			print a[k]
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var a [3]int
+			print a[1+2]
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			const k = 2
+			var a [3]int
+			print a[k] + a[k-2]
+			`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			var s []int
+			print s[-1]
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var a [3]int
+			a[1.5] = 1
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var a [3]int
+			a[1] = 1.5
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var a int
+			a[1] = 1
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var a [3]int
+			var b [2]int
+			a = b
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			func f(a [3]int) {
+			}
+			`,
+			expectedToErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			t.Fatalf("%v", p.Errors())
+		}
+		if err := resolver.Resolve(program, symbol.NewTable()); err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		t.Logf("Program: %v", program.String())
+		err := Check(program)
+
+		if err != nil && !tt.expectedToErr {
+			t.Fatalf("checker had errors which was not expected. got=%s", err)
 		}
 
-		if funcStmt.Signature.Parameter != nil && !reflect.DeepEqual(funcStmt.Signature.Parameter.T, tt.expectedParamType) {
-			t.Fatalf("funcStmt.Parameter.T is not %s. got=%s", tt.expectedParamType, funcStmt.Signature.Parameter.T)
+		if err == nil && tt.expectedToErr {
+			t.Fatalf("checker was assumed to fail, but it did not.")
 		}
 	}
 }
 
-func TestFunctionPrototypes(t *testing.T) {
+func TestSlice(t *testing.T) {
 	tests := []struct {
-		input             string
-		progIndex         int
-		expectedFuncType  types.Type
-		expectedParamType types.Type
-		expectedToErr     bool
+		input         string
+		expectedToErr bool
 	}{
 		{
 			input: `
-			func greeter(string)
-
-			greeter("hejsa")
+			func sum(xs []int) int {
+				return xs[0] + len(xs)
+			}
 
-			func greeter(x string) {
-				print x
-			}`,
-			expectedFuncType: &types.Signature{
-				Parameter: types.Typ[types.String],
-				Result:    types.Typ[types.Nil],
-			},
-			expectedParamType: types.Typ[types.String],
-			expectedToErr:     false,
+			var s []int = make([]int, 2, 4)
+			var n int = 3
+			s = append(s, 1, n)
+			s = s[1:len(s)]
+			s = s[:cap(s)]
+			s = s[:]
+			s[1] = sum(s[n:])
+			`,
+			expectedToErr: false,
 		},
 		{
 			input: `
-					type human struct { age int }
-
-					func test(human)
-
-					func test(h human) {
-						print h.age
-					}
-					`,
-			progIndex: 1,
-			expectedFuncType: &types.Signature{
-				Parameter: &types.Struct{
-					Fields: []*types.Field{
-						{
-							Name: "age",
-							Type: types.Typ[types.Int],
-						},
-					},
-				},
-				Result: types.Typ[types.Nil],
-			},
-			expectedParamType: &types.Struct{
-				Fields: []*types.Field{
-					{
-						Name: "age",
-						Type: types.Typ[types.Int],
-					},
-				},
-			},
+			var s [][3]float
+			s = append(s, s[0])
+			s[0][1] = 1.5
+			`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			var s []int
+			s = append(s, 1.5)
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var s []int
+			var t []float
+			s = t
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var s []int = make([]float, 2)
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var s []int = make([]int)
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var s []int = make([]int, 2.5)
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var a [3]int
+			var s []int = a[1:]
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var s string = "hello"
+			var t string = s[1:3]
+			print s[:2] + t[1:]
+			`,
 			expectedToErr: false,
 		},
+		{
+			input: `
+			var s string = "hello"
+			var t []int = s[1:]
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var s []int
+			print s[1.5:]
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var x int
+			print len(x)
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var x int
+			x = append(x, 1)
+			`,
+			expectedToErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -629,11 +3171,6 @@ func TestFunctionPrototypes(t *testing.T) {
 
 		t.Logf("Program: %v", program.String())
 		err := Check(program)
-		if err != nil && tt.expectedToErr {
-			continue
-		}
-
-		t.Logf("Program after been checked: %v", program.String())
 
 		if err != nil && !tt.expectedToErr {
 			t.Fatalf("checker had errors which was not expected. got=%s", err)
@@ -642,156 +3179,95 @@ func TestFunctionPrototypes(t *testing.T) {
 		if err == nil && tt.expectedToErr {
 			t.Fatalf("checker was assumed to fail, but it did not.")
 		}
-
-		funcStmt, _ := program.Statements[tt.progIndex].(*ast.FuncStatement)
-
-		if !reflect.DeepEqual(funcStmt.Name.T, tt.expectedFuncType) {
-			t.Fatalf("funcStmt.Name.T is not %q. got=%q", tt.expectedFuncType.String(), funcStmt.Name.T.String())
-		}
-
-		if funcStmt.Signature.Parameter != nil && !reflect.DeepEqual(funcStmt.Signature.Parameter.T, tt.expectedParamType) {
-			t.Fatalf("funcStmt.Parameter.T is not %s. got=%s", tt.expectedParamType, funcStmt.Signature.Parameter.T)
-		}
 	}
-
 }
 
-func TestFuncStatementWithStruct(t *testing.T) {
+func TestPointer(t *testing.T) {
 	tests := []struct {
-		input             string
-		expectedFuncType  types.Type
-		expectedParamType types.Type
-		expectedToErr     bool
+		input         string
+		expectedToErr bool
 	}{
 		{
 			input: `
-					type human struct{name string}
+			type node struct {
+				val int
+				next *node
+			}
 
-					func greeter(x human) human {
-						return x
-					}`,
-			expectedFuncType: &types.Signature{
-				Parameter: &types.Struct{
-					Fields: []*types.Field{
-						{
-							Name: "name",
-							Type: types.Typ[types.String],
-						},
-					},
-				},
-				Result: &types.Struct{
-					Fields: []*types.Field{
-						{
-							Name: "name",
-							Type: types.Typ[types.String],
-						},
-					},
-				},
-			},
-			expectedParamType: &types.Struct{
-				Fields: []*types.Field{
-					{
-						Name: "name",
-						Type: types.Typ[types.String],
-					},
-				},
-			},
+			var head *node = nil
+			var n *node = new(node)
+			n.val = 1
+			n.next = head
+			head = n
+			if head.next == nil {
+				print head.val
+			}
+			`,
 			expectedToErr: false,
 		},
-	}
-
-	for _, tt := range tests {
-		l := lexer.New(tt.input)
-		p := parser.New(l)
-		program := p.ParseProgram()
-		if len(p.Errors()) != 0 {
-			t.Logf("%v", p.Errors())
-		}
-		resolver.Resolve(program, symbol.NewTable())
-
-		t.Logf("Program: %v", program.String())
-
-		err := Check(program)
-		if err != nil && tt.expectedToErr {
-			continue
-		}
-
-		t.Logf("Program after been checked: %v", program.String())
-
-		if err != nil && !tt.expectedToErr {
-			t.Fatalf("checker had errors which was not expected. got=%s", err)
-		}
-
-		if err == nil && tt.expectedToErr {
-			t.Fatalf("checker was assumed to fail, but it did not.")
-		}
-
-		funcStmt, _ := program.Statements[1].(*ast.FuncStatement)
-
-		if !reflect.DeepEqual(funcStmt.Name.T, tt.expectedFuncType) {
-			t.Fatalf("funcStmt.Name.T is not %q. got=%q", tt.expectedFuncType.String(), funcStmt.Name.T.String())
-		}
-
-		if !reflect.DeepEqual(funcStmt.Signature.Parameter.T, tt.expectedParamType) {
-			t.Fatalf("funcStmt.Parameter.T is not %s. got=%s", tt.expectedParamType, funcStmt.Signature.Parameter.T)
-		}
-	}
-}
-
-func TestCallExpression(t *testing.T) {
-	tests := []struct {
-		input            string
-		progIndex        int
-		expectedCallType types.Type
-	}{
 		{
-			input: `func compile(x string) {
-				print x
+			input: `
+			func inc(x *int) {
+				*x = *x + 1
 			}
 
-			compile("Hello")`,
-			progIndex:        1,
-			expectedCallType: types.Typ[types.Nil],
+			var x int
+			var p *int = &x
+			inc(p)
+			inc(&x)
+			print *p
+			`,
+			expectedToErr: false,
 		},
 		{
-			input: `func compile(x string) string{
-				return x
-			}
-
-			compile("Hello")`,
-			progIndex:        1,
-			expectedCallType: types.Typ[types.String],
+			input: `
+			var a [3]float
+			var p *float = &a[1]
+			*p = 1.5
+			var q **float = &p
+			**q = 2.5
+			`,
+			expectedToErr: false,
 		},
 		{
-			input: `func compile(x string) string{
-				return x
-			}
-
-			compile("Hello Compiler World")`,
-			progIndex:        1,
-			expectedCallType: types.Typ[types.String],
+			input: `
+			var p *int = &1
+			`,
+			expectedToErr: true,
 		},
 		{
 			input: `
-			var x func(int)
-			func test(int)
-
-			x = test
-			x(100)
+			var x int
+			print *x
 			`,
-			progIndex:        3,
-			expectedCallType: types.Typ[types.Nil],
+			expectedToErr: true,
 		},
 		{
 			input: `
-			var x func(int) int
-			func test(int) int
-
-			x = test
-			x(100)
+			var x int = nil
 			`,
-			progIndex:        3,
-			expectedCallType: types.Typ[types.Int],
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var x float
+			var p *int = &x
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var p *int
+			var q *int
+			print p + q
+			`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			print nil == nil
+			`,
+			expectedToErr: true,
 		},
 	}
 
@@ -800,25 +3276,21 @@ func TestCallExpression(t *testing.T) {
 		p := parser.New(l)
 		program := p.ParseProgram()
 		if len(p.Errors()) != 0 {
-			t.Logf("%v", p.Errors())
+			t.Fatalf("%v", p.Errors())
 		}
-		resolver.Resolve(program, symbol.NewTable())
-
-		t.Logf("Program: %v", program.String())
-		t.Logf("Program length: %v", len(program.Statements))
-
-		err := Check(program)
-		if err != nil {
+		if err := resolver.Resolve(program, symbol.NewTable()); err != nil {
 			t.Fatalf("%v", err)
 		}
 
-		t.Logf("Program after been checked: %v", program.String())
+		t.Logf("Program: %v", program.String())
+		err := Check(program)
 
-		exprStmt, _ := program.Statements[tt.progIndex].(*ast.ExpressionStatement)
-		call, _ := exprStmt.Expression.(*ast.CallExpression)
+		if err != nil && !tt.expectedToErr {
+			t.Fatalf("checker had errors which was not expected. got=%s", err)
+		}
 
-		if call.T != tt.expectedCallType {
-			t.Fatalf("Call expression was evalutated to the wrong type, expected=%v, got=%s", tt.expectedCallType, call.T)
+		if err == nil && tt.expectedToErr {
+			t.Fatalf("checker was assumed to fail, but it did not.")
 		}
 	}
 }
@@ -859,11 +3331,81 @@ func TestArithmetic(t *testing.T) {
 		},
 		{
 			input:         `2.0 + 2`,
+			expectedType:  types.Typ[types.Float],
+			expectedToErr: false,
+		},
+		{
+			input:         `"Hello" + "World"`,
+			expectedType:  types.Typ[types.String],
+			expectedToErr: false,
+		},
+		{
+			input:         `"Hello" - "World"`,
 			expectedType:  nil,
 			expectedToErr: true,
 		},
 		{
-			input:         `"Hello" + "World"`,
+			input:         `"Hello" + 2`,
+			expectedType:  nil,
+			expectedToErr: true,
+		},
+		{
+			input:         `7 % 2`,
+			expectedType:  types.Typ[types.Int],
+			expectedToErr: false,
+		},
+		{
+			input:         `7 & 2 | 1 ^ 4 &^ 8`,
+			expectedType:  types.Typ[types.Int],
+			expectedToErr: false,
+		},
+		{
+			input:         `1 << 4 >> 2`,
+			expectedType:  types.Typ[types.Int],
+			expectedToErr: false,
+		},
+		{
+			input:         `7.0 % 2.0`,
+			expectedType:  nil,
+			expectedToErr: true,
+		},
+		{
+			input:         `true & false`,
+			expectedType:  nil,
+			expectedToErr: true,
+		},
+		{
+			input:         `"a" | "b"`,
+			expectedType:  nil,
+			expectedToErr: true,
+		},
+		{
+			input:         `1.0 << 2.0`,
+			expectedType:  nil,
+			expectedToErr: true,
+		},
+		{
+			input:         `-2`,
+			expectedType:  types.Typ[types.Int],
+			expectedToErr: false,
+		},
+		{
+			input:         `+2.5`,
+			expectedType:  types.Typ[types.Float],
+			expectedToErr: false,
+		},
+		{
+			input:         `-"Hello"`,
+			expectedType:  nil,
+			expectedToErr: true,
+		},
+		{
+			input:         `-true`,
+			expectedType:  nil,
+			expectedToErr: true,
+		},
+		{
+			input:         `+false`,
 			expectedType:  nil,
 			expectedToErr: true,
 		},
@@ -913,22 +3455,24 @@ func runCheckerTests(t *testing.T, tests []checkerTest) {
 					t.Fatalf("added wrong type: expected=%s, got=%s", tt.expectedType, node.Value.Type())
 				}
 			case *ast.VarStatement:
-				if v, ok := node.Name.T.(*types.Struct); ok {
-					vv, _ := tt.expectedType.(*types.Struct)
-					for i, f := range v.Fields {
-						if f.Type != vv.Fields[i].Type {
-							t.Fatalf("identifier: %q was defined with the wrong type. expected=%s, got=%s", node.Name.TokenLiteral(), tt.expectedType, node.Name.T)
+				for _, name := range node.Names {
+					if v, ok := name.T.(*types.Struct); ok {
+						vv, _ := tt.expectedType.(*types.Struct)
+						for i, f := range v.Fields {
+							if f.Type != vv.Fields[i].Type {
+								t.Fatalf("identifier: %q was defined with the wrong type. expected=%s, got=%s", name.TokenLiteral(), tt.expectedType, name.T)
+							}
 						}
+					} else if !reflect.DeepEqual(name.T, tt.expectedType) {
+						t.Fatalf(
+							"variable: %s was defined with the wrong type. expected=%s, got=%s",
+							name.TokenLiteral(), tt.expectedType, name.T)
 					}
-				} else if !reflect.DeepEqual(node.Name.T, tt.expectedType) {
-					t.Fatalf(
-						"variable: %s was defined with the wrong type. expected=%s, got=%s",
-						node.Name.TokenLiteral(), tt.expectedType, node.Name.T)
 				}
 
-				if node.Value != nil {
-					if node.Name.Type() != node.Value.Type() {
-						t.Fatalf("allowed to add type: %s to an identifier with type: %s", node.Value.Type(), node.Name.Type())
+				for i, v := range node.Values {
+					if node.Names[i].Type() != v.Type() {
+						t.Fatalf("allowed to add type: %s to an identifier with type: %s", v.Type(), node.Names[i].Type())
 					}
 				}
 			case *ast.TypeStatement:
@@ -940,6 +3484,10 @@ func runCheckerTests(t *testing.T, tests []checkerTest) {
 					}
 				}
 			case *ast.AssignStatement:
+				for _, n := range node.Names {
+					testing(n)
+				}
+			case *ast.IncDecStatement:
 				testing(node.Name)
 			case *ast.ExpressionStatement:
 				testing(node.Expression)
@@ -950,9 +3498,20 @@ func runCheckerTests(t *testing.T, tests []checkerTest) {
 			case *ast.ForStatement:
 				// TODO: Should test that the initialise, condition, and next
 				// all have the correct type.
-				if node.Condition.Type() != types.Typ[types.Bool] {
+				if node.Condition != nil && node.Condition.Type() != types.Typ[types.Bool] {
 					t.Fatalf("condition in for statement does not evalutate to a bool")
 				}
+			case *ast.SwitchStatement:
+				for _, c := range node.Cases {
+					// The cases of a switch without a tag are conditions.
+					for _, v := range c.Values {
+						if node.Tag == nil && v.Type() != types.Typ[types.Bool] {
+							t.Fatalf("case in switch statement does not evalutate to a bool")
+						}
+					}
+
+					testing(c.Body)
+				}
 			case *ast.Identifier:
 				t.Logf("%v", node)
 				if !reflect.DeepEqual(node.T, tt.expectedType) {
@@ -971,6 +3530,11 @@ func runCheckerTests(t *testing.T, tests []checkerTest) {
 					t.Fatalf("infx expression have unexpected type. expected=%s, got=%s",
 						tt.expectedType, node.T)
 				}
+			case *ast.PrefixExpression:
+				if node.T != tt.expectedType {
+					t.Fatalf("prefix expression have unexpected type. expected=%s, got=%s",
+						tt.expectedType, node.T)
+				}
 			case *ast.IntegerLiteral:
 				if node.T != tt.expectedType {
 					t.Fatalf("int literal have unexpected type. expected=%s, got=%s",
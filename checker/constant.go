@@ -0,0 +1,541 @@
+package checker
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/Glorforidor/didactic_compiler/ast"
+	"github.com/Glorforidor/didactic_compiler/symbol"
+	"github.com/Glorforidor/didactic_compiler/types"
+)
+
+// checkConst checks the constant declaration and gives each constant its type
+// and value. A spec without values repeats the type and the values of the
+// previous spec, but with its own iota.
+func checkConst(node *ast.ConstStatement, symbolTable *symbol.Table) error {
+	var tnode ast.TypeNode
+	var values []ast.Expression
+
+	for _, spec := range node.Specs {
+		if len(spec.Values) != 0 {
+			tnode, values = spec.Tnode, spec.Values
+		}
+
+		if len(spec.Names) != len(values) {
+			return fmt.Errorf(
+				"type error: assignment mismatch: %d constants but %d values",
+				len(spec.Names),
+				len(values),
+			)
+		}
+
+		var t types.Type
+		if tnode != nil {
+			var err error
+			t, err = typeNodetoType(tnode, symbolTable)
+			if err != nil {
+				return err
+			}
+
+			if !constType(t) {
+				return fmt.Errorf("type error: invalid constant type: %s", t)
+			}
+		}
+
+		for i, n := range spec.Names {
+			value, vt, err := constant(values[i], spec.Iota, symbolTable)
+			if err != nil {
+				return err
+			}
+
+			if t != nil {
+				if !untyped(vt) && vt != t {
+					return fmt.Errorf(
+						"type error: identifier: %q of type: %s is assigned the wrong type: %s",
+						n.Value,
+						t,
+						vt,
+					)
+				}
+
+				value, err = representable(value, vt, t)
+				if err != nil {
+					return err
+				}
+				vt = t
+			}
+
+			s, _ := symbolTable.Resolve(n.Value)
+			s.Type, s.Value = vt, value
+
+			n.T = defaultType(vt)
+		}
+	}
+
+	return nil
+}
+
+// constant evaluates the constant expression, where iota is the index of the
+// spec of the expression. It returns the value and the type of the
+// expression, which is untyped for a number unless a typed constant is used.
+func constant(expr ast.Expression, iota int, symbolTable *symbol.Table) (interface{}, types.Type, error) {
+	switch expr := expr.(type) {
+	case *ast.IntegerLiteral:
+		return expr.Value, types.Typ[types.UntypedInt], nil
+	case *ast.FloatLiteral:
+		return expr.Value, types.Typ[types.UntypedFloat], nil
+	case *ast.StringLiteral:
+		return expr.Value, types.Typ[types.String], nil
+	case *ast.BoolLiteral:
+		return expr.Value, types.Typ[types.Bool], nil
+	case *ast.Iota:
+		return int64(iota), types.Typ[types.UntypedInt], nil
+	case *ast.Identifier:
+		s, ok := symbolTable.Resolve(expr.Value)
+		if !ok || s.Scope != symbol.ConstScope {
+			return nil, nil, fmt.Errorf("type error: %s is not constant", expr.Value)
+		}
+
+		return s.Value, s.Type.(types.Type), nil
+	case *ast.PrefixExpression:
+		v, t, err := constant(expr.Right, iota, symbolTable)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch v := v.(type) {
+		case int64:
+			switch expr.Operator {
+			case "+":
+				return v, t, nil
+			case "-":
+				if v == math.MinInt64 {
+					return nil, nil, fmt.Errorf("type error: constant %s overflows int", expr)
+				}
+
+				value, err := representable(-v, t, t)
+				return value, t, err
+			}
+		case float64:
+			switch expr.Operator {
+			case "+":
+				return v, t, nil
+			case "-":
+				return -v, t, nil
+			}
+		case bool:
+			if expr.Operator == "!" {
+				return !v, t, nil
+			}
+		}
+
+		if expr.Operator == "&" || expr.Operator == "*" {
+			return nil, nil, fmt.Errorf("type error: %s is not constant", expr)
+		}
+
+		return nil, nil, fmt.Errorf("type error: operator: %v does not support type: %v", expr.Operator, t)
+	case *ast.InfixExpression:
+		return constantInfix(expr, iota, symbolTable)
+	case *ast.ConversionExpression:
+		t, err := typeNodetoType(expr.Tnode, symbolTable)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		v, vt, err := constant(expr.Value, iota, symbolTable)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// A byte is converted into the string of the byte.
+		if t.Kind() == types.String && (vt.Kind() == types.Byte || vt.Kind() == types.UntypedInt) {
+			b, err := representable(v, vt, types.Typ[types.Byte])
+			if err != nil {
+				return nil, nil, err
+			}
+
+			return escape(byte(b.(int64))), t, nil
+		}
+
+		if !constType(t) || !convertible(defaultType(vt), t) {
+			return nil, nil, fmt.Errorf("type error: cannot convert %s (type %s) to type %s", expr.Value, vt, t)
+		}
+
+		v, err = representable(v, vt, t)
+		return v, t, err
+	default:
+		return nil, nil, fmt.Errorf("type error: %s is not constant", expr)
+	}
+}
+
+// escape returns the string of the byte as it is written in a string literal,
+// which is also how the assembler reads it.
+func escape(b byte) string {
+	switch b {
+	case '\n':
+		return `\n`
+	case '\t':
+		return `\t`
+	case '"':
+		return `\"`
+	case '\\':
+		return `\\`
+	default:
+		return string([]byte{b})
+	}
+}
+
+// constantInfix evaluates the constant infix expression. An untyped operand is
+// given the type of the other operand, and an untyped int is given the type of
+// an untyped float.
+func constantInfix(expr *ast.InfixExpression, iota int, symbolTable *symbol.Table) (interface{}, types.Type, error) {
+	l, lt, err := constant(expr.Left, iota, symbolTable)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, rt, err := constant(expr.Right, iota, symbolTable)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	t := lt
+	switch {
+	case lt == rt:
+	case untyped(lt) && !untyped(rt):
+		t = rt
+	case untyped(lt) && untyped(rt):
+		t = types.Typ[types.UntypedFloat]
+	case !untyped(rt):
+		return nil, nil, fmt.Errorf("type error: mismatch of types %s and %s", lt, rt)
+	}
+
+	if l, err = representable(l, lt, t); err != nil {
+		return nil, nil, err
+	}
+	if r, err = representable(r, rt, t); err != nil {
+		return nil, nil, err
+	}
+
+	if comparison(expr.Operator) {
+		ok, err := constantComparison(expr.Operator, l, r)
+		if err != nil {
+			return nil, nil, fmt.Errorf("type error: operator: %v does not support type: %v", expr.Operator, t)
+		}
+
+		return ok, types.Typ[types.Bool], nil
+	}
+
+	var v interface{}
+	switch l := l.(type) {
+	case int64:
+		r := r.(int64)
+
+		var n int64
+		fits := true
+		switch expr.Operator {
+		case "+", "-", "*":
+			n, fits = arithmetic(expr.Operator, l, r)
+		case "/", "%":
+			if r == 0 {
+				return nil, nil, fmt.Errorf("type error: invalid operation: division by zero")
+			}
+
+			if expr.Operator == "/" {
+				n, fits = arithmetic(expr.Operator, l, r)
+			} else {
+				n = l % r
+			}
+		case "&":
+			n = l & r
+		case "|":
+			n = l | r
+		case "^":
+			n = l ^ r
+		case "&^":
+			n = l &^ r
+		case "<<", ">>":
+			if r < 0 {
+				return nil, nil, fmt.Errorf("type error: invalid negative shift count: %d", r)
+			}
+
+			if expr.Operator == "<<" {
+				n, fits = arithmetic(expr.Operator, l, r)
+			} else {
+				n = l >> r
+			}
+		default:
+			return nil, nil, fmt.Errorf("type error: operator: %v does not support type: %v", expr.Operator, t)
+		}
+
+		if !fits {
+			return nil, nil, fmt.Errorf("type error: constant %s overflows int", expr)
+		}
+		v = n
+	case float64:
+		r := r.(float64)
+		switch expr.Operator {
+		case "+":
+			v = l + r
+		case "-":
+			v = l - r
+		case "*":
+			v = l * r
+		case "/":
+			if r == 0 {
+				return nil, nil, fmt.Errorf("type error: invalid operation: division by zero")
+			}
+
+			v = l / r
+		}
+	case string:
+		if expr.Operator == "+" {
+			v = l + r.(string)
+		}
+	case bool:
+		switch expr.Operator {
+		case "&&":
+			v = l && r.(bool)
+		case "||":
+			v = l || r.(bool)
+		}
+	}
+
+	if v == nil {
+		return nil, nil, fmt.Errorf("type error: operator: %v does not support type: %v", expr.Operator, t)
+	}
+
+	// The result must still be a value of a typed constant, e.g. a byte.
+	v, err = representable(v, t, t)
+	return v, t, err
+}
+
+// arithmetic computes the operation on the integer constants l and r. It
+// reports whether the result can be represented by an int, as an integer
+// constant is held by an int64.
+func arithmetic(operator string, l, r int64) (int64, bool) {
+	x, y := big.NewInt(l), big.NewInt(r)
+
+	switch operator {
+	case "+":
+		x.Add(x, y)
+	case "-":
+		x.Sub(x, y)
+	case "*":
+		x.Mul(x, y)
+	case "/":
+		x.Quo(x, y)
+	case "<<":
+		// Any bit shifted out of an int64 overflows it.
+		if r >= 64 {
+			return 0, l == 0
+		}
+		x.Lsh(x, uint(r))
+	}
+
+	return x.Int64(), x.IsInt64()
+}
+
+// constantComparison compares the constant values l and r, which have the
+// same type.
+func constantComparison(operator string, l, r interface{}) (bool, error) {
+	switch operator {
+	case "==":
+		return l == r, nil
+	case "!=":
+		return l != r, nil
+	}
+
+	var less bool
+	switch l := l.(type) {
+	case int64:
+		less = l < r.(int64)
+	case float64:
+		less = l < r.(float64)
+	case string:
+		less = l < r.(string)
+	default:
+		return false, fmt.Errorf("operator: %s is not defined on %v", operator, l)
+	}
+
+	switch operator {
+	case "<":
+		return less, nil
+	case "<=":
+		return less || l == r, nil
+	case ">":
+		return !less && l != r, nil
+	default:
+		return !less, nil
+	}
+}
+
+// representable returns the constant value v of type vt as a value of type t.
+// It reports an error if the value can not be represented by t, like a float
+// with a fraction by an int or an int out of range of a byte.
+func representable(v interface{}, vt, t types.Type) (interface{}, error) {
+	err := fmt.Errorf("type error: cannot use constant %v (type %s) as type %s", v, vt, t)
+
+	switch t.Kind() {
+	case types.Int, types.Byte, types.UntypedInt:
+		var n int64
+		switch v := v.(type) {
+		case int64:
+			n = v
+		case float64:
+			if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+				return nil, err
+			}
+			n = int64(v)
+		default:
+			return nil, err
+		}
+
+		if t.Kind() == types.Byte && (n < 0 || n > math.MaxUint8) {
+			return nil, err
+		}
+
+		return n, nil
+	case types.Float, types.UntypedFloat:
+		switch v := v.(type) {
+		case int64:
+			return float64(v), nil
+		case float64:
+			return v, nil
+		}
+	case types.String:
+		if _, ok := v.(string); ok {
+			return v, nil
+		}
+	case types.Bool:
+		if _, ok := v.(bool); ok {
+			return v, nil
+		}
+	}
+
+	return nil, err
+}
+
+// convertUntyped gives an untyped constant the type t, if its value can be
+// represented by t, like nil is given the type it is compared to. It returns
+// the type of the expression.
+func convertUntyped(expr ast.Expression, t types.Type, symbolTable *symbol.Table) types.Type {
+	switch e := expr.(type) {
+	case *ast.IntegerLiteral:
+		if _, err := representable(e.Value, types.Typ[types.UntypedInt], t); err == nil {
+			e.T = t
+		}
+	case *ast.FloatLiteral:
+		if _, err := representable(e.Value, types.Typ[types.UntypedFloat], t); err == nil {
+			e.T = t
+		}
+	case *ast.StringLiteral:
+		if t.Kind() == types.String {
+			e.T = t
+		}
+	case *ast.BoolLiteral:
+		if t.Kind() == types.Bool {
+			e.T = t
+		}
+	case *ast.InfixExpression:
+		// The value is folded, since the operation on the untyped operands
+		// may differ from the operation on the converted operands, like
+		// 7 / 2 is 3 and not 3.5.
+		v, vt, err := constant(e, 0, symbolTable)
+		if err != nil || !untyped(vt) {
+			break
+		}
+
+		if v, err := representable(v, vt, t); err == nil {
+			e.T, e.Value = t, v
+		}
+	case *ast.Identifier:
+		s, ok := symbolTable.Resolve(e.Value)
+		if !ok || s.Scope != symbol.ConstScope || !untyped(s.Type.(types.Type)) {
+			break
+		}
+
+		if _, err := representable(s.Value, s.Type.(types.Type), t); err == nil {
+			e.T = t
+		}
+	case *ast.PrefixExpression:
+		if e.Operator != "-" && e.Operator != "+" {
+			break
+		}
+
+		// A negative number is never a byte.
+		if e.Operator == "-" && t.Kind() == types.Byte {
+			break
+		}
+
+		e.T = convertUntyped(e.Right, t, symbolTable)
+	}
+
+	return expr.Type()
+}
+
+// untypedConstant reports whether the expression is an untyped constant, or
+// the sign of one.
+func untypedConstant(expr ast.Expression, symbolTable *symbol.Table) bool {
+	switch e := expr.(type) {
+	case *ast.IntegerLiteral, *ast.FloatLiteral, *ast.StringLiteral, *ast.BoolLiteral:
+		return true
+	case *ast.InfixExpression:
+		_, t, err := constant(e, 0, symbolTable)
+		return err == nil && untyped(t)
+	case *ast.Identifier:
+		s, ok := symbolTable.Resolve(e.Value)
+		return ok && s.Scope == symbol.ConstScope && untyped(s.Type.(types.Type))
+	case *ast.PrefixExpression:
+		return (e.Operator == "-" || e.Operator == "+") && untypedConstant(e.Right, symbolTable)
+	default:
+		return false
+	}
+}
+
+// constantOperands reports whether the expression is made only of constants,
+// so it is folded when it is checked.
+func constantOperands(expr ast.Expression, symbolTable *symbol.Table) bool {
+	switch e := expr.(type) {
+	case *ast.IntegerLiteral, *ast.FloatLiteral, *ast.StringLiteral, *ast.BoolLiteral:
+		return true
+	case *ast.InfixExpression:
+		return constantOperands(e.Left, symbolTable) && constantOperands(e.Right, symbolTable)
+	case *ast.Identifier:
+		s, ok := symbolTable.Resolve(e.Value)
+		return ok && s.Scope == symbol.ConstScope
+	case *ast.PrefixExpression:
+		return (e.Operator == "-" || e.Operator == "+" || e.Operator == "!") && constantOperands(e.Right, symbolTable)
+	default:
+		return false
+	}
+}
+
+// untyped reports whether t is the type of an untyped number.
+func untyped(t types.Type) bool {
+	return t.Kind() == types.UntypedInt || t.Kind() == types.UntypedFloat
+}
+
+// defaultType returns the type an untyped constant has, when it is not given
+// another type by its use.
+func defaultType(t types.Type) types.Type {
+	switch t.Kind() {
+	case types.UntypedInt:
+		return types.Typ[types.Int]
+	case types.UntypedFloat:
+		return types.Typ[types.Float]
+	default:
+		return t
+	}
+}
+
+// constType reports whether a constant can have type t.
+func constType(t types.Type) bool {
+	switch t.Kind() {
+	case types.Int, types.Byte, types.Float, types.String, types.Bool:
+		return true
+	default:
+		return false
+	}
+}
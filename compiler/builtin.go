@@ -0,0 +1,300 @@
+package compiler
+
+import (
+	"fmt"
+
+	"github.com/Glorforidor/didactic_compiler/ast"
+	"github.com/Glorforidor/didactic_compiler/token"
+	"github.com/Glorforidor/didactic_compiler/types"
+)
+
+// A slice variable holds the address of a slice header on the heap. The
+// header is never changed after it has been created, instead append and
+// slicing create a new header.
+const (
+	slicePtr        = 0  // The address of the elements.
+	sliceLen        = 8  // The number of elements.
+	sliceCap        = 16 // The number of elements there is space for.
+	sliceHeaderSize = 24
+)
+
+// builtin emits the instructions for a call to a builtin function.
+func (c *Compiler) builtin(node *ast.BuiltinExpression) error {
+	var regs []string
+	for _, a := range node.Arguments {
+		if err := c.Compile(a); err != nil {
+			return err
+		}
+		c.loadGlobalOrPtrValue(a)
+
+		regs = append(regs, a.Register())
+	}
+
+	switch node.Token.Type {
+	case token.Make:
+		elem := node.T.(*types.Slice).Elem
+
+		c.emitf("mv a0, %s", regs[0])
+		if len(regs) == 2 {
+			c.emitf("mv a1, %s", regs[1])
+			c.registerTable.dealloc(regs[1])
+		} else {
+			// The capacity is the same as the length.
+			c.emitf("mv a1, %s", regs[0])
+		}
+		c.emitf("li a2, %d", types.Sizeof(elem))
+		c.emitf("call runtime.makeslice")
+		c.emitf("mv %s, a0", regs[0])
+
+		c.useRuntime("runtime.makeslice")
+
+		if err := c.allocateElements(elem, regs[0], "zero"); err != nil {
+			return err
+		}
+
+		node.Reg = regs[0]
+	case token.New:
+		t := node.T.(*types.Pointer).Elem
+
+		reg, err := c.registerTable.allocGeneral()
+		if err != nil {
+			return err
+		}
+
+		if s, ok := t.(*types.Struct); ok {
+			c.heapAllocate(s.Size())
+			c.emitf("mv %s, a0", reg)
+			c.allocateStructFields(s, reg)
+		} else {
+			c.heapAllocate(types.Sizeof(t))
+			c.emitf("mv %s, a0", reg)
+			c.allocateHeap(t, reg, 0)
+		}
+
+		node.Reg = reg
+	case token.Append:
+		elem := node.T.(*types.Slice).Elem
+		size := types.Sizeof(elem)
+		s := regs[0]
+		values := regs[1:]
+
+		// Every element is given its own copy of a struct.
+		if st, ok := elem.(*types.Struct); ok {
+			for _, v := range values {
+				c.copyStruct(st, v)
+			}
+		}
+
+		for i, v := range values {
+			if err := c.convert(node.Arguments[i+1].Type(), elem, v); err != nil {
+				return err
+			}
+		}
+
+		// The elements of new space beyond the length are allocated, as
+		// the space is only zeroed memory.
+		var old string
+		if usesHeap(elem) && len(values) > 0 {
+			reg, err := c.registerTable.allocGeneral()
+			if err != nil {
+				return err
+			}
+			old = reg
+
+			c.emitf("ld %s, %d(%s)", old, slicePtr, s)
+		}
+
+		c.emitf("mv a0, %s", s)
+		c.emitf("li a1, %d", len(values))
+		c.emitf("li a2, %d", size)
+		c.emitf("call runtime.append")
+		c.emitf("mv %s, a0", s)
+
+		c.useRuntime("runtime.append")
+
+		if old != "" {
+			sameLabel := c.label.create()
+
+			c.emitf("ld a0, %d(%s)", slicePtr, s)
+			c.emitf("beq a0, %s, %s", old, sameLabel)
+			c.emitf("ld %s, %d(%s)", old, sliceLen, s)
+			if err := c.allocateElements(elem, s, old); err != nil {
+				return err
+			}
+			c.emitf("%s:", sameLabel)
+
+			c.registerTable.dealloc(old)
+		}
+
+		node.Reg = s
+
+		if len(values) == 0 {
+			break
+		}
+
+		// The values are stored right after the old elements.
+		addr, err := c.registerTable.allocGeneral()
+		if err != nil {
+			return err
+		}
+
+		reg, err := c.registerTable.allocGeneral()
+		if err != nil {
+			return err
+		}
+
+		c.emitf("ld %s, %d(%s)", addr, sliceLen, s)
+		c.emitf("addi %s, %s, -%d", addr, addr, len(values))
+		c.emitf("li %s, %d", reg, size)
+		c.emitf("mul %s, %s, %s", addr, addr, reg)
+		c.emitf("ld %s, %d(%s)", reg, slicePtr, s)
+		c.emitf("add %s, %s, %s", addr, addr, reg)
+
+		c.registerTable.dealloc(reg)
+
+		for i, v := range values {
+			c.store(elem, v, addr, i*size)
+			c.registerTable.dealloc(v)
+		}
+
+		c.registerTable.dealloc(addr)
+	case token.Len, token.Cap:
+		reg := regs[0]
+
+		switch t := node.Arguments[0].Type().(type) {
+		case *types.Array:
+			c.emitf("li %s, %d", reg, t.Len)
+		case *types.Slice:
+			if node.Token.Type == token.Len {
+				c.emitf("ld %s, %d(%s)", reg, sliceLen, reg)
+			} else {
+				c.emitf("ld %s, %d(%s)", reg, sliceCap, reg)
+			}
+		default:
+			// Otherwise it is the length of a string.
+			c.strlen(reg, reg)
+		}
+
+		node.Reg = reg
+	default:
+		return fmt.Errorf("compiler error: builtin function: %q is not implemented", node.Token.Literal)
+	}
+
+	return nil
+}
+
+// sliceExpression emits the instructions for slicing a slice, which creates a
+// new slice header sharing the elements with the sliced slice, or for slicing
+// a string, which creates a new string.
+func (c *Compiler) sliceExpression(node *ast.SliceExpression) error {
+	if err := c.Compile(node.Left); err != nil {
+		return err
+	}
+	c.loadGlobalOrPtrValue(node.Left)
+
+	left := node.Left.Register()
+
+	var low, high string
+	if node.Low != nil {
+		if err := c.Compile(node.Low); err != nil {
+			return err
+		}
+		c.loadGlobalOrPtrValue(node.Low)
+
+		low = node.Low.Register()
+	}
+
+	if node.High != nil {
+		if err := c.Compile(node.High); err != nil {
+			return err
+		}
+		c.loadGlobalOrPtrValue(node.High)
+
+		high = node.High.Register()
+	}
+
+	// The high bound of a string is its length.
+	if high == "" && node.T.Kind() == types.String {
+		reg, err := c.registerTable.allocGeneral()
+		if err != nil {
+			return err
+		}
+		c.strlen(reg, left)
+
+		high = reg
+	}
+
+	c.emitf("mv a0, %s", left)
+	if low == "" {
+		c.emitf("li a1, 0")
+	} else {
+		c.emitf("mv a1, %s", low)
+	}
+	if high == "" {
+		c.emitf("ld a2, %d(%s)", sliceLen, left)
+	} else {
+		c.emitf("mv a2, %s", high)
+	}
+
+	if node.T.Kind() == types.String {
+		c.emitf("call runtime.slicestring")
+		c.useRuntime("runtime.slicestring")
+	} else {
+		c.emitf("li a3, %d", types.Sizeof(node.T.(*types.Slice).Elem))
+		c.emitf("call runtime.slice")
+		c.useRuntime("runtime.slice")
+	}
+	c.emitf("mv %s, a0", left)
+
+	c.useRuntime("runtime.panicslice")
+
+	c.registerTable.dealloc(low)
+	c.registerTable.dealloc(high)
+
+	node.Reg = left
+
+	return nil
+}
+
+// allocateElements emits the instructions which allocate space on the heap
+// for the elements of the slice header in the register s, from the index in
+// the register from up to the capacity, if the elements hold structs or
+// slices. The space of the elements is otherwise only zeroed memory.
+func (c *Compiler) allocateElements(elem types.Type, s, from string) error {
+	if !usesHeap(elem) {
+		return nil
+	}
+
+	addr, err := c.registerTable.allocGeneral()
+	if err != nil {
+		return err
+	}
+	end, err := c.registerTable.allocGeneral()
+	if err != nil {
+		return err
+	}
+
+	size := types.Sizeof(elem)
+	c.emitf("li %s, %d", addr, size)
+	c.emitf("ld %s, %d(%s)", end, sliceCap, s)
+	c.emitf("mul %s, %s, %s", end, end, addr)
+	c.emitf("mul %s, %s, %s", addr, addr, from)
+	c.emitf("ld a0, %d(%s)", slicePtr, s)
+	c.emitf("add %s, %s, a0", addr, addr)
+	c.emitf("add %s, %s, a0", end, end)
+
+	loopLabel := c.label.create()
+	doneLabel := c.label.create()
+
+	c.emitf("%s:", loopLabel)
+	c.emitf("bgeu %s, %s, %s", addr, end, doneLabel)
+	c.allocateHeap(elem, addr, 0)
+	c.emitf("addi %s, %s, %d", addr, addr, size)
+	c.emitf("j %s", loopLabel)
+	c.emitf("%s:", doneLabel)
+
+	c.registerTable.dealloc(addr)
+	c.registerTable.dealloc(end)
+
+	return nil
+}
@@ -0,0 +1,442 @@
+package compiler
+
+import (
+	"fmt"
+
+	"github.com/Glorforidor/didactic_compiler/ast"
+	"github.com/Glorforidor/didactic_compiler/symbol"
+	"github.com/Glorforidor/didactic_compiler/types"
+)
+
+const (
+	// The number of integer and floating argument registers, a0-a7 and
+	// fa0-fa7.
+	argumentRegisters = 8
+	// The number of integer and floating result registers, a0-a1 and
+	// fa0-fa1.
+	resultRegisters = 2
+)
+
+// location describes where an argument or a result is passed in a call.
+type location struct {
+	// reg is the register of the value. It is empty if the value is passed on
+	// the stack.
+	reg string
+	// offset is the offset into the stack space the caller allocates for the
+	// call. Arguments passed in registers are kept there until all arguments
+	// have been computed.
+	offset int
+}
+
+// locate computes where values of the given types are passed, when at most
+// registers of each kind are used. Floating point values are passed in the
+// fa registers and every other value in the a registers. When the registers
+// run out, the values are passed on the stack in the order they appear. It
+// also returns the offset right after the last value on the stack.
+func locate(ts []types.Type, registers int) ([]location, int) {
+	locations := make([]location, len(ts))
+
+	var general, floating int
+	// The first value on the stack is at 8(sp), since the callee saves its
+	// return address at 0(sp).
+	offset := 8
+
+	for i, t := range ts {
+		switch {
+		case t.Kind() == types.Float && floating < registers:
+			locations[i].reg = fmt.Sprintf("fa%d", floating)
+			floating++
+		case t.Kind() != types.Float && general < registers:
+			locations[i].reg = fmt.Sprintf("a%d", general)
+			general++
+		default:
+			locations[i].offset = offset
+			offset += 8
+		}
+	}
+
+	return locations, offset
+}
+
+// A tuple holds the values of an expression list or the results of a call.
+// Like the results of a call, at most resultRegisters values of each kind are
+// held in registers, and the rest are spilled to the stack, so a long tuple
+// does not run out of registers.
+type tuple struct {
+	types []types.Type
+	// regs holds the register of each value, which is empty if the value is
+	// spilled.
+	regs []string
+	// slots holds the offset of each spilled value into the spill space.
+	slots []int
+	// space is the size of the spill space.
+	space int
+	// mark is the call space right after the spill space was allocated, so
+	// the spill space is found even if the stack pointer has moved.
+	mark int
+}
+
+// newTuple allocates the spill space on the stack of a tuple of values of the
+// given types. The spill space must be released by freeTuple.
+func (c *Compiler) newTuple(ts []types.Type) *tuple {
+	locations, end := locate(ts, resultRegisters)
+
+	tp := &tuple{
+		types: ts,
+		regs:  make([]string, len(ts)),
+		slots: make([]int, len(ts)),
+	}
+	for i, loc := range locations {
+		if loc.reg == "" {
+			tp.slots[i] = loc.offset
+		}
+	}
+
+	if end != 8 {
+		// The stack space always need to be 16 byte alligned.
+		tp.space = (end + 15) / 16 * 16
+		c.emitf("addi sp, sp, -%d", tp.space)
+		c.callSpace += tp.space
+	}
+	tp.mark = c.callSpace
+
+	return tp
+}
+
+// spilled reports whether the i'th value of the tuple resides on the stack.
+func (tp *tuple) spilled(i int) bool {
+	return tp.slots[i] != 0
+}
+
+// slot returns the offset of the i'th value of the tuple relative to the
+// current stack pointer.
+func (c *Compiler) slot(tp *tuple, i int) int {
+	return c.callSpace - tp.mark + tp.slots[i]
+}
+
+// setValue sets the i'th value of the tuple to the value in the register. A
+// spilled value is stored on the stack, which frees the register.
+func (c *Compiler) setValue(tp *tuple, i int, reg string) {
+	if !tp.spilled(i) {
+		tp.regs[i] = reg
+		return
+	}
+
+	if tp.types[i].Kind() == types.Float {
+		c.emitf("fsd %s, %d(sp)", reg, c.slot(tp, i))
+	} else {
+		c.emitf("sd %s, %d(sp)", reg, c.slot(tp, i))
+	}
+	c.registerTable.dealloc(reg)
+}
+
+// value returns the register holding the i'th value of the tuple. A spilled
+// value is loaded into a new register.
+func (c *Compiler) value(tp *tuple, i int) (string, error) {
+	if !tp.spilled(i) {
+		return tp.regs[i], nil
+	}
+
+	reg, err := c.allocateRegByType(tp.types[i])
+	if err != nil {
+		return "", err
+	}
+
+	if tp.types[i].Kind() == types.Float {
+		c.emitf("fld %s, %d(sp)", reg, c.slot(tp, i))
+	} else {
+		c.emitf("ld %s, %d(sp)", reg, c.slot(tp, i))
+	}
+
+	return reg, nil
+}
+
+// freeTuple releases the spill space of the tuple. Its values must already
+// have been used, so no register holds them.
+func (c *Compiler) freeTuple(tp *tuple) {
+	if tp.space == 0 {
+		return
+	}
+
+	c.emitf("addi sp, sp, %d", tp.space)
+	c.callSpace -= tp.space
+}
+
+// call emits a call to the function and returns the tuple of the results of
+// the call.
+func (c *Compiler) call(node *ast.CallExpression) (*tuple, error) {
+	// The spill space of the results is allocated before anything else, so
+	// it is still there after the call.
+	tp := c.newTuple(types.Values(node.T))
+
+	saved, savedSpace := c.saveRegisters()
+
+	args := node.Arguments
+	paramTypes := node.Function.Type().(*types.Signature).Parameters
+
+	// The receiver of a method is passed as the first argument. Both a struct
+	// and a pointer to it are held as the address of the struct, so the
+	// receiver is passed the same way for value and pointer methods. A method
+	// with a value receiver copies the struct itself.
+	sel, ok := node.Function.(*ast.SelectorExpression)
+	isMethod := ok && sel.Method != nil
+	if isMethod {
+		args = append([]ast.Expression{sel.X}, args...)
+		paramTypes = append([]types.Type{sel.X.Type()}, paramTypes...)
+	}
+
+	// A method of an interface is called through the itab of the interface
+	// value, which is passed the struct of the value as its receiver.
+	dynamic := isMethod && sel.X.Type().Kind() == types.InterfaceKind
+
+	arguments, end := locate(paramTypes, argumentRegisters)
+	// The arguments passed in registers are placed after the ones passed on
+	// the stack, so the callee finds its stack arguments at the bottom.
+	for i := range arguments {
+		if arguments[i].reg != "" {
+			arguments[i].offset = end
+			end += 8
+		}
+	}
+
+	// The results passed on the stack are written by the callee to the same
+	// stack space as the arguments.
+	results, resultEnd := locate(types.Values(node.T), resultRegisters)
+	end = max(end, resultEnd)
+
+	var space int
+	if end != 8 {
+		// The stack space always need to be 16 byte alligned.
+		space = (end + 15) / 16 * 16
+		c.emitf("addi sp, sp, -%d", space)
+		c.callSpace += space
+	}
+
+	// A function value, which is neither a named function nor a method, is
+	// compiled before the arguments.
+	if !isMethod && !c.isFuncName(node.Function) {
+		if err := c.Compile(node.Function); err != nil {
+			return nil, err
+		}
+		c.loadGlobalOrPtrValue(node.Function)
+	}
+
+	// Each argument is stored on the stack right after it has been compiled,
+	// so an argument never occupies a register while the next one is
+	// compiled.
+	for i, a := range args {
+		if err := c.Compile(a); err != nil {
+			return nil, err
+		}
+
+		// We need to load the value from a global variable otherwise we would
+		// pass along the address of the variable in the data segment and not
+		// the value it points to.
+		c.loadGlobalOrPtrValue(a)
+
+		switch {
+		case isMethod && i == 0:
+			if dynamic {
+				if err := c.lookupMethod(sel); err != nil {
+					return nil, err
+				}
+			}
+		case paramTypes[i].Kind() == types.StructKind:
+			// A struct is passed as a copy, so the function can not change
+			// the struct of the caller.
+			c.copyStruct(paramTypes[i].(*types.Struct), a.Register())
+		default:
+			if err := c.convert(a.Type(), paramTypes[i], a.Register()); err != nil {
+				return nil, err
+			}
+		}
+
+		if a.Type().Kind() == types.Float {
+			c.emitf("fsd %s, %d(sp)", a.Register(), arguments[i].offset)
+		} else {
+			c.emitf("sd %s, %d(sp)", a.Register(), arguments[i].offset)
+		}
+
+		c.registerTable.dealloc(a.Register())
+	}
+
+	for _, loc := range arguments {
+		switch {
+		case loc.reg == "":
+			// Already in place for the callee.
+		case isFloating(loc.reg):
+			c.emitf("fld %s, %d(sp)", loc.reg, loc.offset)
+		default:
+			c.emitf("ld %s, %d(sp)", loc.reg, loc.offset)
+		}
+	}
+
+	c.jump(node.Function)
+
+	// Move the results out of the result registers and the stack, so they
+	// are not overwritten by the next call. A result on the stack is moved
+	// to the spill space of the tuple one at a time.
+	for i, t := range types.Values(node.T) {
+		reg, err := c.allocateRegByType(t)
+		if err != nil {
+			return nil, err
+		}
+
+		loc := results[i]
+		switch {
+		case loc.reg == "" && t.Kind() == types.Float:
+			c.emitf("fld %s, %d(sp)", reg, loc.offset)
+		case loc.reg == "":
+			c.emitf("ld %s, %d(sp)", reg, loc.offset)
+		case t.Kind() == types.Float:
+			c.emitf("fmv.d %s, %s", reg, loc.reg)
+		default:
+			c.emitf("mv %s, %s", reg, loc.reg)
+		}
+
+		c.setValue(tp, i, reg)
+	}
+
+	if space != 0 {
+		c.emitf("addi sp, sp, %d", space)
+		c.callSpace -= space
+	}
+
+	c.restoreRegisters(saved, savedSpace)
+
+	return tp, nil
+}
+
+// jump emits the jump to the function, which is either named by an identifier,
+// a method, a method looked up in an itab or a compiled function value.
+func (c *Compiler) jump(function ast.Expression) {
+	switch f := function.(type) {
+	case *ast.Identifier:
+		if c.isFuncName(f) {
+			c.emitf("call %s", f.Value)
+			return
+		}
+	case *ast.SelectorExpression:
+		if f.Method == nil {
+			break
+		}
+
+		t := f.X.Type()
+		if t.Kind() == types.InterfaceKind {
+			// The register holds the address of the method.
+			c.emitf("jalr %s", f.Reg)
+			c.registerTable.dealloc(f.Reg)
+			return
+		}
+
+		if p, ok := t.(*types.Pointer); ok {
+			t = p.Elem
+		}
+
+		name := t.String()
+		if s, ok := t.(*types.Struct); ok {
+			name = s.Name
+		}
+
+		c.emitf("call %s", ast.MethodLabel(name, f.Method.Name))
+		return
+	}
+
+	// The register holds the address of the closure, which is passed to the
+	// function in the context register.
+	reg := function.Register()
+	c.emitf("mv %s, %s", contextRegister, reg)
+	c.emitf("ld %s, 0(%s)", reg, reg)
+	c.emitf("jalr %s", reg)
+
+	c.registerTable.dealloc(reg)
+}
+
+// isFuncName reports whether the expression is the name of a function declared
+// by a func statement.
+func (c *Compiler) isFuncName(expr ast.Expression) bool {
+	id, ok := expr.(*ast.Identifier)
+	if !ok {
+		return false
+	}
+
+	s, _ := c.symbolTable.Resolve(id.Value)
+	return s.Scope == symbol.FuncScope
+}
+
+// isTypeName reports whether the expression is the name of a type, which makes
+// a call of it a conversion.
+func (c *Compiler) isTypeName(expr ast.Expression) bool {
+	id, ok := expr.(*ast.Identifier)
+	if !ok {
+		return false
+	}
+
+	s, ok := c.symbolTable.Resolve(id.Value)
+	return ok && s.Scope == symbol.TypeScope
+}
+
+// lookupMethod emits the instructions which load the address of the method of
+// the selector from the itab of the interface value in the register of X into
+// the register of the selector. The register of X is updated to hold the
+// struct of the interface value.
+func (c *Compiler) lookupMethod(sel *ast.SelectorExpression) error {
+	reg, err := c.registerTable.allocGeneral()
+	if err != nil {
+		return err
+	}
+
+	x := sel.X.Register()
+	index := methodIndex(sel.X.Type().(*types.Interface), sel.Method)
+
+	c.emitf("ld %s, %d(%s)", reg, interfaceItab, x)
+	c.emitf("ld %s, %d(%s)", reg, index*8, reg)
+	c.emitf("ld %s, %d(%s)", x, interfaceData, x)
+
+	sel.Reg = reg
+
+	return nil
+}
+
+// saveRegisters stores the allocated temporary registers on the stack, since
+// the callee of a call is free to overwrite them. It returns the saved
+// registers and the stack space used, which must be passed to
+// restoreRegisters after the call.
+func (c *Compiler) saveRegisters() ([]string, int) {
+	regs := c.registerTable.temporaries()
+	if len(regs) == 0 {
+		return nil, 0
+	}
+
+	// Like everywhere else 0(sp) is left free, as that is where the callee
+	// saves its return address.
+	space := (8 + len(regs)*8 + 15) / 16 * 16
+	c.emitf("addi sp, sp, -%d", space)
+	c.callSpace += space
+	for i, reg := range regs {
+		if isFloating(reg) {
+			c.emitf("fsd %s, %d(sp)", reg, 8+i*8)
+		} else {
+			c.emitf("sd %s, %d(sp)", reg, 8+i*8)
+		}
+	}
+
+	return regs, space
+}
+
+// restoreRegisters loads the registers saved by saveRegisters.
+func (c *Compiler) restoreRegisters(regs []string, space int) {
+	if len(regs) == 0 {
+		return
+	}
+
+	for i, reg := range regs {
+		if isFloating(reg) {
+			c.emitf("fld %s, %d(sp)", reg, 8+i*8)
+		} else {
+			c.emitf("ld %s, %d(sp)", reg, 8+i*8)
+		}
+	}
+	c.emitf("addi sp, sp, %d", space)
+	c.callSpace -= space
+}
@@ -0,0 +1,118 @@
+package compiler
+
+import (
+	"fmt"
+
+	"github.com/Glorforidor/didactic_compiler/ast"
+	"github.com/Glorforidor/didactic_compiler/symbol"
+	"github.com/Glorforidor/didactic_compiler/types"
+)
+
+// A function value is the address of a closure on the heap. The closure holds
+// the address of the code of the function followed by the address of each
+// variable captured by the function. The closure is passed to the function in
+// the context register, when the function value is called.
+const contextRegister = "s11"
+
+// funcLiteral emits the instructions which create the closure of the function
+// literal. The code of the function literal is emitted after the functions.
+func (c *Compiler) funcLiteral(node *ast.FuncLiteral) error {
+	free := node.SymbolTable.Free()
+
+	reg, err := c.registerTable.allocGeneral()
+	if err != nil {
+		return err
+	}
+
+	c.heapAllocate(8 * (len(free) + 1))
+	c.emitf("mv %s, a0", reg)
+
+	tmp, err := c.registerTable.allocGeneral()
+	if err != nil {
+		return err
+	}
+	c.emitf("la %s, %s", tmp, node.Label)
+	c.emitf("sd %s, 0(%s)", tmp, reg)
+	c.registerTable.dealloc(tmp)
+
+	// The captured variables are found by their names in the enclosing
+	// function, which gives the address of the variable on the heap.
+	for _, f := range free {
+		addr, err := c.loadSymbol(&ast.Identifier{Value: f.Name})
+		if err != nil {
+			return err
+		}
+
+		c.emitf("sd %s, %d(%s)", addr, 8*(f.FreeIndex()+1), reg)
+		c.registerTable.dealloc(addr)
+	}
+
+	node.Reg = reg
+
+	// The function literal may be in the middle of the code of another
+	// function, so its code is compiled on its own.
+	fun, inFunc, registerTable := c.fun, c.inFunc, c.registerTable
+	frameSpace, stackSpace, callSpace := c.frameSpace, c.stackSpace, c.callSpace
+	c.fun, c.inFunc, c.registerTable = nil, true, riscvTable()
+	c.frameSpace, c.stackSpace, c.callSpace = 0, 0, 0
+
+	c.enterScope(node.SymbolTable)
+	err = c.function(node.Label, nil, node.Signature, node.Body)
+	c.leaveScope(node.SymbolTable)
+
+	c.literals = append(c.literals, c.fun...)
+
+	c.fun, c.inFunc, c.registerTable = fun, inFunc, registerTable
+	c.frameSpace, c.stackSpace, c.callSpace = frameSpace, stackSpace, callSpace
+
+	return err
+}
+
+// funcValue returns the label of the closure of the named function, and emits
+// the closure the first time it is used. The function captures no variables,
+// so its closure is never changed and can reside in the data segment.
+func (c *Compiler) funcValue(name string) string {
+	label := fmt.Sprintf("%s.closure", name)
+
+	if c.funcValues == nil {
+		c.funcValues = make(map[string]bool)
+	}
+
+	if !c.funcValues[label] {
+		c.funcValues[label] = true
+		c.addConstantf("%s: .dword %s", label, name)
+	}
+
+	return label
+}
+
+// allocateCaptured allocates the heap space of a captured variable of type t,
+// and stores its address at offset(sp).
+func (c *Compiler) allocateCaptured(t types.Type, offset int) {
+	c.heapAllocate(types.Sizeof(t))
+	c.emitf("sd a0, %d(sp)", offset)
+
+	if !usesHeap(t) {
+		return
+	}
+
+	reg, err := c.registerTable.allocGeneral()
+	if err != nil {
+		// This should probably not happen as there are many registers to
+		// allocate, but if there is a error then panic.
+		panic(err)
+	}
+	c.emitf("mv %s, a0", reg)
+
+	c.allocateHeap(t, reg, 0)
+
+	c.registerTable.dealloc(reg)
+}
+
+// byAddress reports whether an identifier of the symbol is compiled into the
+// address of its value, like a global variable is compiled into the address of
+// its label. That is the case for captured and free variables, which reside
+// on the heap.
+func byAddress(s *symbol.Symbol) bool {
+	return s.Scope == symbol.GlobalScope || s.Scope == symbol.FreeScope || s.Captured
+}
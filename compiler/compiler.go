@@ -6,6 +6,7 @@ import (
 
 	"github.com/Glorforidor/didactic_compiler/ast"
 	"github.com/Glorforidor/didactic_compiler/symbol"
+	"github.com/Glorforidor/didactic_compiler/token"
 	"github.com/Glorforidor/didactic_compiler/types"
 )
 
@@ -44,11 +45,56 @@ type Compiler struct {
 	// block deallocates.
 	stackSpace int
 
+	// frameSpace is the stack space of the frame of the function being
+	// compiled.
+	frameSpace int
+
+	// callSpace is the stack space temporarily allocated while setting up
+	// calls. Local symbols are that much further away from the stack pointer.
+	callSpace int
+
+	// runtime holds the names of the runtime routines used by the program.
+	runtime map[string]bool
+
+	// itabs holds the labels of the itabs emitted for the program.
+	itabs map[string]bool
+
+	// literals contains the assembly code for function literals, which will
+	// reside in the text segment after the functions.
+	literals []string
+
+	// funcValues holds the labels of the closures of the named functions
+	// used as values.
+	funcValues map[string]bool
+
+	// constLabels holds the labels of the float and string constants, so
+	// every use of a constant shares the same data.
+	constLabels map[*symbol.Symbol]string
+
+	// targets holds the loops and the switches enclosing the statement being
+	// compiled, where the innermost is the last.
+	targets []*target
+
 	// isTest is used for testing purposes. This will skip the wrapping of the
 	// program in __start and __end.
 	isTest bool
 }
 
+// target holds what a break or a continue statement needs to jump out of a
+// loop or a switch, or to the next iteration of a loop.
+type target struct {
+	node ast.Statement
+	// breakLabel is the label right after the loop or the switch.
+	breakLabel string
+	// continueLabel is the label of the next statement of a loop, which is
+	// only created when a continue statement uses it.
+	continueLabel string
+	// stackSpace is the stack space at the start of the body. A jump from
+	// a block inside the body must first deallocate the stack space of the
+	// blocks it leaves.
+	stackSpace int
+}
+
 func New() *Compiler {
 	return &Compiler{
 		registerTable: riscvTable(),
@@ -102,6 +148,8 @@ func (c *Compiler) Compile(node ast.Node) error {
 			printType = 3
 		case types.String:
 			printType = 4
+		case types.Byte:
+			printType = 11
 		default:
 			return fmt.Errorf("compile error: can not print type: %q", node.Value.Type())
 		}
@@ -112,21 +160,28 @@ func (c *Compiler) Compile(node ast.Node) error {
 
 		c.registerTable.dealloc(reg)
 	case *ast.VarStatement:
-		s, _ := c.symbolTable.Resolve(node.Name.Value)
-
-		if s.Scope == symbol.GlobalScope {
-			err := c.createASMLabelIdentifier(s.Name, node.Name.T)
-			if err != nil {
-				return err
-			}
-		} else {
-			if v, ok := node.Name.Type().(*types.Struct); ok {
-				c.heapAllocate(v.Size())
-				c.emitf("sd a0, %d(sp)", s.Code().(int))
+		names := make([]ast.Expression, len(node.Names))
+		for i, n := range node.Names {
+			s, _ := c.symbolTable.Resolve(n.Value)
+
+			switch {
+			case node.IsRedeclared(i):
+				// A redeclared variable already exists and is only assigned.
+			case s.Scope == symbol.GlobalScope:
+				err := c.createASMLabelIdentifier(s.Name, n.T)
+				if err != nil {
+					return err
+				}
+			case s.Captured:
+				c.allocateCaptured(n.T, c.stackPosition(s))
+			default:
+				c.allocateHeap(n.T, "sp", c.stackPosition(s))
 			}
+
+			names[i] = n
 		}
 
-		if node.Value == nil {
+		if len(node.Values) == 0 {
 			break
 		}
 
@@ -138,8 +193,8 @@ func (c *Compiler) Compile(node ast.Node) error {
 		// x = 2
 		if err := c.Compile(
 			&ast.AssignStatement{
-				Name:  node.Name,
-				Value: node.Value,
+				Names:  names,
+				Values: node.Values,
 			},
 		); err != nil {
 			return err
@@ -149,68 +204,85 @@ func (c *Compiler) Compile(node ast.Node) error {
 		// interation is kept, even though it is declared again. I need to
 		// explicit set a zero value if there is non assigned.
 	case *ast.AssignStatement:
-		// TODO: maybe move this to into the global scope check?
-		// Otherwise we will emit an unnecessary load instruction for locals.
-		if err := c.Compile(node.Name); err != nil {
-			return err
+		if node.Operator != "" {
+			if err := c.compoundAssign(node.Names[0], node.Operator, node.Values[0]); err != nil {
+				return err
+			}
+			break
 		}
 
-		if err := c.Compile(node.Value); err != nil {
+		for _, n := range node.Names {
+			// A local variable is stored directly on the stack, so there is
+			// no need to load it.
+			if id, ok := n.(*ast.Identifier); ok {
+				if s, _ := c.symbolTable.Resolve(id.Value); !byAddress(s) {
+					continue
+				}
+			}
+
+			if err := c.Compile(n); err != nil {
+				return err
+			}
+		}
+
+		// All the values are computed before any of them are assigned, so
+		// "x, y = y, x" swaps x and y.
+		tp, err := c.compileValues(node.Values)
+		if err != nil {
 			return err
 		}
-		c.loadGlobalOrPtrValue(node.Value)
 
-		var s *symbol.Symbol
-		var offset int
-		var isStruct bool
-		switch t := node.Name.(type) {
-		case *ast.Identifier:
-			s, _ = c.symbolTable.Resolve(t.Value)
-		case *ast.SelectorExpression:
-			switch tt := t.X.(type) {
-			case *ast.Identifier:
-				s, _ = c.symbolTable.Resolve(tt.Value)
-				offset = t.Offset
-				isStruct = true
+		// A struct is assigned by overwriting the struct of the name, so
+		// with more names every struct value is copied first. Otherwise
+		// "x, y = y, x" would overwrite y before it is assigned to x.
+		if len(node.Names) > 1 {
+			for i, t := range tp.types {
+				if s, ok := t.(*types.Struct); ok {
+					reg, err := c.value(tp, i)
+					if err != nil {
+						return err
+					}
+
+					c.copyStruct(s, reg)
+					c.setValue(tp, i, reg)
+				}
 			}
-		default:
-			// We should never end here, so panic if we do.
-			panic("unhandled type in assign statement")
 		}
 
-		regVal := node.Value.Register()
-		regName := node.Name.Register()
-
-		if s.Scope == symbol.GlobalScope {
-			switch node.Name.Type().Kind() {
-			case types.Float:
-				c.emitf("fsd %s, %d(%v)", regVal, offset, regName)
-			default:
-				c.emitf("sd %s, %d(%v)", regVal, offset, regName)
+		for i, n := range node.Names {
+			reg, err := c.value(tp, i)
+			if err != nil {
+				return err
 			}
-		} else {
-			switch node.Name.Type().Kind() {
-			case types.Float:
-				if isStruct {
-					c.emitf("fsd %s, %d(%v)", regVal, offset, regName)
-				} else {
-					c.emitf("fsd %s, %d(sp)", regVal, offset+s.Code().(int))
-				}
-			default:
-				if isStruct {
-					c.emitf("sd %s, %d(%v)", regVal, offset, regName)
-				} else {
-					c.emitf("sd %s, %d(sp)", regVal, offset+s.Code().(int))
-				}
+
+			if err := c.convert(tp.types[i], n.Type(), reg); err != nil {
+				return err
 			}
+
+			c.assign(n, reg)
 		}
 
-		c.registerTable.dealloc(regVal)
-		c.registerTable.dealloc(regName)
+		c.freeTuple(tp)
+	case *ast.IncDecStatement:
+		operator := "+"
+		if node.Token.Type == token.Decrement {
+			operator = "-"
+		}
+
+		// "i++" is like "i += 1" with a 1 of the type of i.
+		var one ast.Expression = &ast.IntegerLiteral{Value: 1, T: node.Name.Type()}
+		if node.Name.Type().Kind() == types.Float {
+			one = &ast.FloatLiteral{Value: 1, T: node.Name.Type()}
+		}
+
+		if err := c.compoundAssign(node.Name, operator, one); err != nil {
+			return err
+		}
 	case *ast.IfStatement:
 		if err := c.Compile(node.Condition); err != nil {
 			return err
 		}
+		c.loadGlobalOrPtrValue(node.Condition)
 
 		falseLabel := c.label.create()
 		doneLabel := c.label.create()
@@ -239,8 +311,10 @@ func (c *Compiler) Compile(node ast.Node) error {
 		defer c.leaveScope(c.enterScope(node.SymbolTable))
 		defer c.stackDealloc(c.stackAlloc())
 
-		if err := c.Compile(node.Init); err != nil {
-			return err
+		if node.Init != nil {
+			if err := c.Compile(node.Init); err != nil {
+				return err
+			}
 		}
 
 		topLabel := c.label.create()
@@ -248,25 +322,67 @@ func (c *Compiler) Compile(node ast.Node) error {
 
 		doneLabel := c.label.create()
 
-		if err := c.Compile(node.Condition); err != nil {
-			return err
-		}
-		condRes := node.Condition.Register()
+		// A loop without a condition is only left by a break.
+		if node.Condition != nil {
+			if err := c.Compile(node.Condition); err != nil {
+				return err
+			}
+			c.loadGlobalOrPtrValue(node.Condition)
+			condRes := node.Condition.Register()
 
-		c.emitf("beqz %s, %s", condRes, doneLabel)
+			c.emitf("beqz %s, %s", condRes, doneLabel)
 
-		c.registerTable.dealloc(condRes)
+			c.registerTable.dealloc(condRes)
+		}
 
-		if err := c.Compile(node.Body); err != nil {
+		t := &target{node: node, breakLabel: doneLabel, stackSpace: c.stackSpace}
+		c.targets = append(c.targets, t)
+		err := c.Compile(node.Body)
+		c.targets = c.targets[:len(c.targets)-1]
+		if err != nil {
 			return err
 		}
 
-		if err := c.Compile(node.Next); err != nil {
-			return err
+		if t.continueLabel != "" {
+			c.emitf("%s:", t.continueLabel)
+		}
+
+		if node.Next != nil {
+			if err := c.Compile(node.Next); err != nil {
+				return err
+			}
 		}
 
 		c.emitf("b %s", topLabel)
 		c.emitf("%s:", doneLabel)
+	case *ast.SwitchStatement:
+		if err := c.switchStatement(node); err != nil {
+			return err
+		}
+	case *ast.BranchStatement:
+		var t *target
+		for _, t = range c.targets {
+			if t.node == node.Target {
+				break
+			}
+		}
+
+		if space := c.stackSpace - t.stackSpace; space != 0 {
+			c.emitf("addi sp, sp, %d", space)
+		}
+
+		if node.Token.Type == token.Break {
+			c.emitf("b %s", t.breakLabel)
+			break
+		}
+
+		if t.continueLabel == "" {
+			t.continueLabel = c.label.create()
+		}
+		c.emitf("b %s", t.continueLabel)
+	case *ast.ConstStatement:
+		// The checker has given each constant its value, which is loaded
+		// where the constant is used.
 	case *ast.TypeStatement:
 		// type statements is only needed for the semantic analysis.
 	case *ast.FuncStatement:
@@ -280,170 +396,273 @@ func (c *Compiler) Compile(node ast.Node) error {
 
 		defer c.leaveScope(c.enterScope(node.SymbolTable))
 
-		space := c.symbolTable.ComputeStack()
+		if err := c.function(node.Label(), node.Receiver, node.Signature, node.Body); err != nil {
+			return err
+		}
+	case *ast.ReturnStatement:
+		// A single call to a function with multiple results gives all of
+		// its results.
+		var tp *tuple
+		if len(node.Values) == 1 {
+			if call, ok := node.Values[0].(*ast.CallExpression); ok && !c.isTypeName(call.Function) {
+				t, err := c.call(call)
+				if err != nil {
+					return err
+				}
+				tp = t
+			}
+		}
 
-		c.emitf("%s:", node.Name.Value)
+		var ts []types.Type
+		for _, v := range node.Values {
+			ts = append(ts, types.Values(v.Type())...)
+		}
 
-		if node.Signature.Parameter != nil {
-			const wordAllignment = 8
-			c.emitf("addi sp, sp, -%d", space)
-			switch node.Signature.Parameter.Type().Kind() {
-			case types.Float:
-				c.emitf("fsd fa0, %d(sp)", wordAllignment)
-			case types.StructKind:
-				c.emitf("sd a0, %d(sp)", wordAllignment)
-			default:
-				c.emitf("sd a0, %d(sp)", wordAllignment)
+		// A result on the stack is stored as soon as it has been computed,
+		// while the results in registers are moved into place after they
+		// all have been computed, as computing one of them could overwrite
+		// the result registers.
+		resultTypes := types.Values(node.Function.T.(*types.Signature).Result)
+		results, _ := locate(ts, resultRegisters)
+		regs := make([]string, len(ts))
+		for i, t := range ts {
+			var reg string
+			if tp != nil {
+				r, err := c.value(tp, i)
+				if err != nil {
+					return err
+				}
+				reg = r
+			} else {
+				if err := c.Compile(node.Values[i]); err != nil {
+					return err
+				}
+				c.loadGlobalOrPtrValue(node.Values[i])
+				reg = node.Values[i].Register()
 			}
-		} else {
-			space = 16
-			c.emitf("addi sp, sp, -%d", space)
-		}
 
-		c.emitf("sd ra, %d(sp)", space)
+			// A struct is returned as a copy, so the caller does not share
+			// it with the function.
+			if s, ok := t.(*types.Struct); ok {
+				c.copyStruct(s, reg)
+			}
 
-		if err := c.Compile(node.Body); err != nil {
-			return err
-		}
+			if err := c.convert(t, resultTypes[i], reg); err != nil {
+				return err
+			}
 
-		// Epilogue of the function
-		c.emitf("%s.epilogue:", node.Name.Value)
-		c.emitf("ld ra, %d(sp)", space)
-		c.emitf("addi sp, sp, %d", space)
-		c.emitf("ret")
+			loc := results[i]
+			if loc.reg != "" {
+				regs[i] = reg
+				continue
+			}
 
-		c.stackSpace = 0
-	case *ast.ReturnStatement:
-		if node.Value == nil {
-			// Clean up any stack space before jumping.
-			c.emitf("addi sp, sp, %d", c.stackSpace)
-			// Unconditionally jump to the functions epilogue.
-			c.emitf("j %s.epilogue", node.Function.Value)
-			return nil
+			// The result resides in the stack space of the caller, which is
+			// right above this frame.
+			offset := c.frameSpace + c.stackSpace + c.callSpace + loc.offset
+			if t.Kind() == types.Float {
+				c.emitf("fsd %s, %d(sp)", reg, offset)
+			} else {
+				c.emitf("sd %s, %d(sp)", reg, offset)
+			}
+			c.registerTable.dealloc(reg)
 		}
 
-		if err := c.Compile(node.Value); err != nil {
-			return err
+		for i, loc := range results {
+			switch {
+			case loc.reg == "":
+				// Already in place for the caller.
+			case ts[i].Kind() == types.Float:
+				c.emitf("fmv.d %s, %s", loc.reg, regs[i])
+			default:
+				c.emitf("mv %s, %s", loc.reg, regs[i])
+			}
+
+			c.registerTable.dealloc(regs[i])
 		}
-		c.loadGlobalOrPtrValue(node.Value)
 
-		switch node.Value.Type().Kind() {
-		case types.Float:
-			c.emitf("fmv.d fa0, %s", node.Value.Register())
-		default:
-			c.emitf("mv a0, %s", node.Value.Register())
+		if tp != nil {
+			c.freeTuple(tp)
 		}
 
 		// Clean up any stack space before jumping.
 		c.emitf("addi sp, sp, %d", c.stackSpace)
 		// Unconditionally jump to the functions epilogue.
 		c.emitf("j %s.epilogue", node.Function.Value)
-
-		c.registerTable.dealloc(node.Value.Register())
 	case *ast.CallExpression:
-		if node.T.Kind() != types.Nil {
-			switch node.T.Kind() {
-			case types.Float:
-				node.Reg = "fa0"
-			default:
-				node.Reg = "a0"
+		// A call of a named type is a conversion to the type.
+		if c.isTypeName(node.Function) {
+			reg, err := c.conversion(node.Arguments[0], node.T)
+			if err != nil {
+				return err
 			}
+
+			node.Reg = reg
+			break
 		}
 
-		id, ok := node.Function.(*ast.Identifier)
-		if !ok {
-			// TODO: This would be nice if one could create a call chain.
-			// Although this is a future work thing!
-			panic("calling a non identifier is not supported")
+		tp, err := c.call(node)
+		if err != nil {
+			return err
 		}
 
-		if node.Argument != nil {
-			if err := c.Compile(node.Argument); err != nil {
-				return err
+		switch len(tp.types) {
+		case 1:
+			node.Reg = tp.regs[0]
+		default:
+			// The results of a function with multiple results can only be
+			// used in an assignment. Anywhere else they are thrown away.
+			for _, reg := range tp.regs {
+				c.registerTable.dealloc(reg)
 			}
-			c.registerTable.dealloc(node.Argument.Register())
+			c.freeTuple(tp)
+		}
+	case *ast.Identifier:
+		reg, err := c.loadSymbol(node)
+		if err != nil {
+			return err
+		}
 
-			// We need to load the value from a global variable otherwise we would
-			// pass along the address of the variable in the data segment and not
-			// the value it points to.
-			c.loadGlobalOrPtrValue(node.Argument)
+		node.Reg = reg
+	case *ast.SelectorExpression:
+		if err := c.Compile(node.X); err != nil {
+			return err
+		}
 
-			switch node.Argument.Type().Kind() {
-			case types.Float:
-				c.emitf("fmv.d fa0, %s", node.Argument.Register())
-			default:
-				c.emitf("mv a0, %s", node.Argument.Register())
-			}
+		// The value of X is the address of the struct, which for a global
+		// identifier or an element must be loaded first. The field itself
+		// is loaded by loadSelectorValue.
+		c.loadGlobalOrPtrValue(node.X)
+		node.Reg = node.X.Register()
+	case *ast.IndexExpression:
+		if err := c.Compile(node.Left); err != nil {
+			return err
 		}
+		c.loadGlobalOrPtrValue(node.Left)
 
-		s, _ := c.symbolTable.Resolve(id.Value)
-		switch s.Scope {
-		case symbol.FuncScope:
-			c.emitf("call %s", s.Name)
-		case symbol.GlobalScope:
-			reg, err := c.registerTable.allocGeneral()
-			if err != nil {
-				return err
-			}
+		left := node.Left.Register()
+		size := types.Sizeof(node.T)
+		if node.Left.Type().Kind() == types.String {
+			// The bytes of a string are packed.
+			size = 1
+		}
 
-			c.emitf("la %s, %s", reg, s.Code())
-			c.emitf("ld %s, 0(%s)", reg, reg)
-			c.emitf("jalr %s", reg)
+		// A constant index of an array has already been checked by the
+		// checker, so the offset of the element is known.
+		i, ok := node.Index.(*ast.IntegerLiteral)
+		if ok && node.Left.Type().Kind() == types.ArrayKind {
+			c.emitf("addi %s, %s, %d", left, left, int(i.Value)*size)
+			node.Reg = left
+			break
+		}
 
-			c.registerTable.dealloc(reg)
-		case symbol.LocalScope:
-			reg, err := c.registerTable.allocGeneral()
-			if err != nil {
-				return err
-			}
+		if err := c.Compile(node.Index); err != nil {
+			return err
+		}
+		c.loadGlobalOrPtrValue(node.Index)
 
-			c.emitf("ld %s, %d(sp)", reg, s.Code().(int))
-			c.emitf("jalr %s", reg)
+		index := node.Index.Register()
 
-			c.registerTable.dealloc(reg)
-		}
-	case *ast.Identifier:
-		reg, err := c.loadSymbol(node)
+		reg, err := c.registerTable.allocGeneral()
 		if err != nil {
 			return err
 		}
 
-		node.Reg = reg
-	case *ast.SelectorExpression:
-		if err := c.Compile(node.X); err != nil {
+		switch t := node.Left.Type().(type) {
+		case *types.Array:
+			c.emitf("li %s, %d", reg, t.Len)
+		case *types.Slice:
+			c.emitf("ld %s, %d(%s)", reg, sliceLen, left)
+			c.emitf("ld %s, %d(%s)", left, slicePtr, left)
+		default:
+			// Otherwise it is a string, whose length is counted.
+			c.strlen(reg, left)
+		}
+		c.boundsCheck(index, reg)
+
+		c.emitf("li %s, %d", reg, size)
+		c.emitf("mul %s, %s, %s", index, index, reg)
+		c.emitf("add %s, %s, %s", left, left, index)
+
+		c.registerTable.dealloc(reg)
+		c.registerTable.dealloc(index)
+
+		// The register holds the address of the element.
+		node.Reg = left
+	case *ast.CompositeLiteral:
+		if err := c.compositeLiteral(node); err != nil {
+			return err
+		}
+	case *ast.FuncLiteral:
+		if err := c.funcLiteral(node); err != nil {
+			return err
+		}
+	case *ast.SliceExpression:
+		if err := c.sliceExpression(node); err != nil {
+			return err
+		}
+	case *ast.BuiltinExpression:
+		if err := c.builtin(node); err != nil {
+			return err
+		}
+	case *ast.ConversionExpression:
+		reg, err := c.conversion(node.Value, node.T)
+		if err != nil {
 			return err
 		}
 
-		switch v := node.X.(type) {
-		case *ast.Identifier:
-			s, _ := c.symbolTable.Resolve(v.Value)
-			if s.Scope == symbol.GlobalScope {
-				c.emitf("ld %s, 0(%s)", v.Reg, v.Reg)
-				node.Reg = v.Reg
-			} else {
-				node.Reg = v.Reg
+		node.Reg = reg
+	case *ast.PrefixExpression:
+		switch node.Operator {
+		case "&":
+			reg, err := c.address(node.Right)
+			if err != nil {
+				return err
 			}
-		case *ast.CallExpression:
-			switch node.Field.T.Kind() {
-			case types.Float:
-				reg, err := c.registerTable.allocFloating()
-				if err != nil {
-					return err
-				}
-				c.emitf("fld %s, %d(a0)", reg, node.Offset)
-				node.Reg = reg
-			default:
-				reg, err := c.registerTable.allocGeneral()
-				if err != nil {
-					return err
-				}
-				c.emitf("ld %s, %d(a0)", reg, node.Offset)
-				node.Reg = reg
+
+			node.Reg = reg
+		case "*":
+			if err := c.Compile(node.Right); err != nil {
+				return err
+			}
+			c.loadGlobalOrPtrValue(node.Right)
+
+			// The register holds the address of the value pointed to.
+			node.Reg = node.Right.Register()
+		case "!":
+			if err := c.Compile(node.Right); err != nil {
+				return err
+			}
+			c.loadGlobalOrPtrValue(node.Right)
+
+			node.Reg = node.Right.Register()
+			c.emitf("xori %s, %s, %d", node.Reg, node.Reg, cTrue)
+		case "-", "+":
+			if err := c.sign(node); err != nil {
+				return err
 			}
 		default:
-			return fmt.Errorf("compiler error: SelectorExpression.X of type: %T is not implemented", v)
+			return fmt.Errorf("compiler error: unknown prefix operator: %s", node.Operator)
 		}
 	case *ast.InfixExpression:
+		// The checker has folded the constant into its value.
+		if node.Value != nil {
+			lit := numberLiteral(node.Value, node.T)
+			if err := c.Compile(lit); err != nil {
+				return err
+			}
+
+			node.Reg = lit.Register()
+			break
+		}
+
+		if node.Operator == "&&" || node.Operator == "||" {
+			if err := c.logical(node); err != nil {
+				return err
+			}
+			break
+		}
+
 		if err := c.Compile(node.Left); err != nil {
 			return err
 		}
@@ -459,8 +678,18 @@ func (c *Compiler) Compile(node ast.Node) error {
 		}
 
 		c.registerTable.dealloc(node.Right.Register())
-		node.Reg = node.Left.Register()
 	case *ast.IntegerLiteral:
+		// An untyped integer may be used as a float.
+		if node.T.Kind() == types.Float {
+			f := &ast.FloatLiteral{Token: node.Token, Value: float64(node.Value), T: node.T}
+			if err := c.Compile(f); err != nil {
+				return err
+			}
+
+			node.Reg = f.Reg
+			break
+		}
+
 		reg, err := c.registerTable.allocGeneral()
 		if err != nil {
 			return err
@@ -470,6 +699,17 @@ func (c *Compiler) Compile(node ast.Node) error {
 
 		c.emitf("li %s, %d", node.Reg, node.Value)
 	case *ast.FloatLiteral:
+		// An untyped float without a fraction may be used as an integer.
+		if node.T.Kind() != types.Float {
+			i := &ast.IntegerLiteral{Token: node.Token, Value: int64(node.Value), T: node.T}
+			if err := c.Compile(i); err != nil {
+				return err
+			}
+
+			node.Reg = i.Reg
+			break
+		}
+
 		reg, err := c.registerTable.allocFloating()
 		if err != nil {
 			return err
@@ -512,6 +752,15 @@ func (c *Compiler) Compile(node ast.Node) error {
 		c.addConstant(la)
 
 		c.emitf("la %s, %s", node.Reg, stringLabel)
+	case *ast.NilLiteral:
+		reg, err := c.registerTable.allocGeneral()
+		if err != nil {
+			return err
+		}
+
+		node.Reg = reg
+
+		c.emitf("li %s, 0", reg)
 	case *ast.BoolLiteral:
 		reg, err := c.registerTable.allocGeneral()
 		if err != nil {
@@ -550,9 +799,15 @@ func (c *Compiler) loadSymbol(node *ast.Identifier) (string, error) {
 	s, _ := c.symbolTable.Resolve(node.Value)
 	switch s.Scope {
 	case symbol.GlobalScope, symbol.FuncScope:
+		// A named function is used by the address of its closure.
+		label := s.Code()
+		if s.Scope == symbol.FuncScope {
+			label = c.funcValue(s.Name)
+		}
+
 		// Loading a global or function identifier insde a function should not
-		// use the s{1..11} registers and instead allocate general registers
-		// t{0..7}. We use the s{1..11} when operating on identifiers in the
+		// use the s{1..10} registers and instead allocate general registers
+		// t{0..7}. We use the s{1..10} when operating on identifiers in the
 		// global scope.
 		if c.inFunc {
 			reg, err := c.registerTable.allocGeneral()
@@ -560,7 +815,7 @@ func (c *Compiler) loadSymbol(node *ast.Identifier) (string, error) {
 				return "", err
 			}
 
-			c.emitf("la %s, %s", reg, s.Code())
+			c.emitf("la %s, %s", reg, label)
 			return reg, nil
 		}
 
@@ -568,35 +823,168 @@ func (c *Compiler) loadSymbol(node *ast.Identifier) (string, error) {
 		if err != nil {
 			return "", err
 		}
-		c.emitf("la %s, %s", reg, s.Code())
+		c.emitf("la %s, %s", reg, label)
 
 		return reg, nil
-	case symbol.LocalScope:
-		reg, err := c.allocateRegByType(node.T)
-		if err != nil {
-			return "", err
-		}
-
-		ld, err := loadASM(node.T)
+	case symbol.FreeScope:
+		// The address of the variable is in the closure.
+		reg, err := c.registerTable.allocGeneral()
 		if err != nil {
 			return "", err
 		}
 
-		c.emitf(ld, reg, s.Code())
+		c.emitf("ld %s, %d(sp)", reg, c.stackPosition(s))
+		c.emitf("ld %s, %d(%s)", reg, 8*(s.FreeIndex()+1), reg)
 
 		return reg, nil
-	default:
-		return "", fmt.Errorf("compile error: can not load symbol: %s of type: %s", s.Name, s.Type)
-	}
-}
+	case symbol.LocalScope:
+		if s.Captured {
+			// The stack holds the address of the captured variable.
+			reg, err := c.registerTable.allocGeneral()
+			if err != nil {
+				return "", err
+			}
 
-func loadASM(t types.Type) (string, error) {
-	switch t.Kind() {
-	case types.Bool, types.Int, types.String:
-		return "ld %s, %d(sp)", nil
-	case types.Float:
-		return "fld %s, %d(sp)", nil
-	case types.StructKind:
+			c.emitf("ld %s, %d(sp)", reg, c.stackPosition(s))
+
+			return reg, nil
+		}
+
+		if node.T.Kind() == types.ArrayKind {
+			// Arrays are stored in place on the stack, so the address of
+			// the array is used instead.
+			reg, err := c.registerTable.allocGeneral()
+			if err != nil {
+				return "", err
+			}
+
+			c.emitf("addi %s, sp, %d", reg, c.stackPosition(s))
+
+			return reg, nil
+		}
+
+		reg, err := c.allocateRegByType(node.T)
+		if err != nil {
+			return "", err
+		}
+
+		ld, err := loadASM(node.T)
+		if err != nil {
+			return "", err
+		}
+
+		c.emitf(ld, reg, c.stackPosition(s))
+
+		return reg, nil
+	case symbol.ConstScope:
+		return c.constant(s, node.T)
+	default:
+		return "", fmt.Errorf("compile error: can not load symbol: %s of type: %s", s.Name, s.Type)
+	}
+}
+
+// constant loads the value of the constant as a value of type t, which is the
+// type the checker gave its use. An integer or a bool is loaded as an
+// immediate, while a float or a string is loaded from the data shared by
+// every use of the constant.
+func (c *Compiler) constant(s *symbol.Symbol, t types.Type) (string, error) {
+	switch t.Kind() {
+	case types.Float:
+		reg, err := c.registerTable.allocFloating()
+		if err != nil {
+			return "", err
+		}
+
+		// register a temporay register for the fld instruction.
+		tmp, err := c.registerTable.allocGeneral()
+		if err != nil {
+			return "", err
+		}
+
+		label, err := c.constantLabel(s, t)
+		if err != nil {
+			return "", err
+		}
+
+		c.emitf("fld %s, %s, %s", reg, label, tmp)
+		c.registerTable.dealloc(tmp)
+
+		return reg, nil
+	case types.String:
+		reg, err := c.registerTable.allocGeneral()
+		if err != nil {
+			return "", err
+		}
+
+		label, err := c.constantLabel(s, t)
+		if err != nil {
+			return "", err
+		}
+
+		c.emitf("la %s, %s", reg, label)
+
+		return reg, nil
+	}
+
+	reg, err := c.registerTable.allocGeneral()
+	if err != nil {
+		return "", err
+	}
+
+	switch v := s.Value.(type) {
+	case int64:
+		c.emitf("li %s, %d", reg, v)
+	case float64:
+		// An untyped float constant used as an integer has no fraction.
+		c.emitf("li %s, %d", reg, int64(v))
+	case bool:
+		if v {
+			c.emitf("li %s, %d", reg, cTrue)
+		} else {
+			c.emitf("li %s, %d", reg, cFalse)
+		}
+	default:
+		return "", fmt.Errorf("compile error: can not load constant: %s of type: %s", s.Name, t)
+	}
+
+	return reg, nil
+}
+
+// constantLabel returns the label of the data of the float or string constant,
+// and emits the data the first time it is used.
+func (c *Compiler) constantLabel(s *symbol.Symbol, t types.Type) (string, error) {
+	if label, ok := c.constLabels[s]; ok {
+		return label, nil
+	}
+
+	value := s.Value
+	if n, ok := value.(int64); ok && t.Kind() == types.Float {
+		// An untyped integer constant used as a float.
+		value = float64(n)
+	}
+
+	label := c.label.create()
+	la, err := c.createASMLabelLiteral(label, t, value)
+	if err != nil {
+		return "", err
+	}
+	c.addConstant(la)
+
+	if c.constLabels == nil {
+		c.constLabels = make(map[*symbol.Symbol]string)
+	}
+	c.constLabels[s] = label
+
+	return label, nil
+}
+
+func loadASM(t types.Type) (string, error) {
+	switch t.Kind() {
+	case types.Bool, types.Int, types.Byte, types.String:
+		return "ld %s, %d(sp)", nil
+	case types.Float:
+		return "fld %s, %d(sp)", nil
+	case types.StructKind, types.SliceKind, types.PointerKind, types.InterfaceKind:
 		return "ld %s, %d(sp)", nil
 	case types.Func:
 		return "ld %s, %d(sp)", nil
@@ -605,17 +993,15 @@ func loadASM(t types.Type) (string, error) {
 	}
 }
 
-// loadSelectorValue emits the load instruction iff the SelectorExpression
-// selects from a global identifier. Otherwise emits nothing.
+// loadSelectorValue emits the load instruction of the selected field. A field
+// of struct or array type is stored in place, so the address of the field is
+// computed instead.
 func (c *Compiler) loadSelectorValue(sel *ast.SelectorExpression) {
-	_, ok := sel.X.(*ast.Identifier)
-	if !ok {
-		return
-	}
-
 	offset := sel.Offset
 
 	switch sel.T.Kind() {
+	case types.StructKind, types.ArrayKind:
+		c.emitf("addi %s, %s, %d", sel.Register(), sel.Register(), offset)
 	case types.Float:
 		// The identifier will always have normal register allocated to each
 		// since we fetch them by address, so when identifier is a float, we
@@ -640,12 +1026,13 @@ func (c *Compiler) loadSelectorValue(sel *ast.SelectorExpression) {
 	}
 }
 
-// loadIdentifier emits the load instruction iff the identifier is global.
-// Otherwise emits nothing.
+// loadIdentifier emits the load instruction iff the identifier is compiled
+// into the address of its value and is not an array, as an array is used by
+// its address. Otherwise emits nothing.
 func (c *Compiler) loadIdentifier(id *ast.Identifier) {
 	s, _ := c.symbolTable.Resolve(id.Value)
 
-	if s.Scope != symbol.GlobalScope {
+	if !byAddress(s) || id.T.Kind() == types.ArrayKind {
 		return
 	}
 
@@ -672,42 +1059,309 @@ func (c *Compiler) loadIdentifier(id *ast.Identifier) {
 	}
 }
 
+// loadIndexValue emits the load instruction of the element iff the element is
+// not an array. Otherwise emits nothing.
+func (c *Compiler) loadIndexValue(ie *ast.IndexExpression) {
+	if ie.Left.Type().Kind() == types.String {
+		c.emitf("lbu %s, 0(%s)", ie.Reg, ie.Reg)
+		return
+	}
+
+	switch ie.T.Kind() {
+	case types.ArrayKind:
+		// An array is used by its address.
+	case types.Float:
+		reg, err := c.registerTable.allocFloating()
+		if err != nil {
+			// This should probably not happen as there are many floating
+			// registers to allocate, but if there is a error then panic.
+			panic(err)
+		}
+		c.emitf("fld %s, 0(%s)", reg, ie.Reg)
+
+		// Deallocate the old normal register which held the address of the
+		// element.
+		c.registerTable.dealloc(ie.Reg)
+
+		ie.Reg = reg
+	default:
+		c.emitf("ld %s, 0(%s)", ie.Reg, ie.Reg)
+	}
+}
+
+// loadDerefValue emits the load instruction of the value pointed to iff the
+// expression is an indirection of a pointer to neither a struct nor an array,
+// as they are used by their address. Otherwise emits nothing.
+func (c *Compiler) loadDerefValue(pe *ast.PrefixExpression) {
+	if pe.Operator != "*" {
+		return
+	}
+
+	switch pe.T.Kind() {
+	case types.StructKind, types.ArrayKind:
+		// A struct or an array is used by its address.
+	case types.Float:
+		reg, err := c.registerTable.allocFloating()
+		if err != nil {
+			// This should probably not happen as there are many floating
+			// registers to allocate, but if there is a error then panic.
+			panic(err)
+		}
+		c.emitf("fld %s, 0(%s)", reg, pe.Reg)
+
+		// Deallocate the old normal register which held the address.
+		c.registerTable.dealloc(pe.Reg)
+
+		pe.Reg = reg
+	default:
+		c.emitf("ld %s, 0(%s)", pe.Reg, pe.Reg)
+	}
+}
+
 // loadGlobalOrPtrValue emits the load instructions iff the given node is of type
-// *ast.Identifier or *ast.SelectorExpression.
+// *ast.Identifier, *ast.SelectorExpression, *ast.IndexExpression or an
+// indirection *ast.PrefixExpression.
 func (c *Compiler) loadGlobalOrPtrValue(node ast.Expression) {
 	switch node := node.(type) {
 	case *ast.Identifier:
 		c.loadIdentifier(node)
 	case *ast.SelectorExpression:
 		c.loadSelectorValue(node)
+	case *ast.IndexExpression:
+		c.loadIndexValue(node)
+	case *ast.PrefixExpression:
+		c.loadDerefValue(node)
 	default:
 		// Ignore every other ast node as they do not have a global value to be
 		// loaded.
 	}
 }
 
+// infix emits the instructions of the operator on the registers of the left and
+// the right operand. The result is in the register of the left operand, unless
+// the result needs another kind of register.
 func (c *Compiler) infix(inf *ast.InfixExpression) error {
 	left := inf.Left.Register()
 	right := inf.Right.Register()
+	inf.Reg = left
+
 	switch inf.Operator {
+	case "==", "!=", "<", "<=", ">", ">=":
+		return c.comparison(inf)
+	}
+
+	return c.binary(inf.Operator, left, right, inf.T)
+}
+
+// binary emits the instructions of the arithmetic operator on the left and the
+// right register of type t. The result is in the left register.
+func (c *Compiler) binary(operator, left, right string, t types.Type) error {
+	switch operator {
 	case "+":
-		c.arithmetic("add", left, right, inf.T)
+		if t.Kind() == types.String {
+			c.concat(left, right)
+			break
+		}
+
+		c.arithmetic("add", left, right, t)
 	case "-":
-		c.arithmetic("sub", left, right, inf.T)
+		c.arithmetic("sub", left, right, t)
 	case "*":
-		c.arithmetic("mul", left, right, inf.T)
+		c.arithmetic("mul", left, right, t)
 	case "/":
-		c.arithmetic("div", left, right, inf.T)
-	case "<":
-		c.compare("blt", left, right, inf.T)
-	case "==":
-		c.compare("beq", left, right, inf.T)
-	case "!=":
-		c.compare("bne", left, right, inf.T)
+		c.arithmetic("div", left, right, t)
+	case "%":
+		c.arithmetic("rem", left, right, t)
+	case "&":
+		c.arithmetic("and", left, right, t)
+	case "|":
+		c.arithmetic("or", left, right, t)
+	case "^":
+		c.arithmetic("xor", left, right, t)
+	case "&^":
+		// x &^ y is x & ^y, where the right register is not needed after.
+		c.emitf("not %s, %s", right, right)
+		c.arithmetic("and", left, right, t)
+	case "<<", ">>":
+		if err := c.shift(operator, left, right); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown operator: %s", operator)
+	}
+
+	// A byte wraps around like the bytes of Go.
+	if t.Kind() == types.Byte {
+		c.emitf("andi %s, %s, 255", left, left)
+	}
+
+	return nil
+}
+
+// numberLiteral returns the literal of the constant number v of type t.
+func numberLiteral(v interface{}, t types.Type) ast.Expression {
+	if f, ok := v.(float64); ok {
+		return &ast.FloatLiteral{Value: f, T: t}
+	}
+
+	return &ast.IntegerLiteral{Value: v.(int64), T: t}
+}
+
+// shift emits the instructions of shifting the left register by the count in
+// the right register. The shift instructions only use the low 6 bits of the
+// count, so a count of 64 or more is handled on its own like in Go: a left
+// shift gives 0 and a right shift fills with the sign. A negative count
+// panics.
+func (c *Compiler) shift(operator, left, right string) error {
+	okLabel := c.label.create()
+
+	c.emitf("bgez %s, %s", right, okLabel)
+	c.emitf("j runtime.panicshift")
+	c.emitf("%s:", okLabel)
+
+	c.useRuntime("runtime.panicshift")
+
+	tmp, err := c.registerTable.allocGeneral()
+	if err != nil {
+		return err
+	}
+	c.emitf("sltiu %s, %s, 64", tmp, right)
+
+	if operator == "<<" {
+		// The mask is all ones if the count is below 64, otherwise zero.
+		c.emitf("neg %s, %s", tmp, tmp)
+		c.emitf("sll %s, %s, %s", left, left, right)
+		c.emitf("and %s, %s, %s", left, left, tmp)
+	} else {
+		// A right shift by 63 fills every bit with the sign.
+		shiftLabel := c.label.create()
+		c.emitf("bnez %s, %s", tmp, shiftLabel)
+		c.emitf("li %s, 63", right)
+		c.emitf("%s:", shiftLabel)
+		c.emitf("sra %s, %s, %s", left, left, right)
+	}
+
+	c.registerTable.dealloc(tmp)
+
+	return nil
+}
+
+// sign emits the instructions of the unary minus or plus. The minus of a
+// literal is folded into a negative literal.
+func (c *Compiler) sign(pe *ast.PrefixExpression) error {
+	right := pe.Right
+	if pe.Operator == "-" {
+		switch r := pe.Right.(type) {
+		case *ast.IntegerLiteral:
+			right = &ast.IntegerLiteral{Token: r.Token, Value: -r.Value, T: r.T}
+		case *ast.FloatLiteral:
+			right = &ast.FloatLiteral{Token: r.Token, Value: -r.Value, T: r.T}
+		}
+	}
+
+	if err := c.Compile(right); err != nil {
+		return err
+	}
+	c.loadGlobalOrPtrValue(right)
+
+	pe.Reg = right.Register()
+
+	if pe.Operator == "+" || right != pe.Right {
+		return nil
+	}
+
+	switch pe.T.Kind() {
+	case types.Float:
+		c.emitf("fneg.d %s, %s", pe.Reg, pe.Reg)
+	default:
+		c.emitf("neg %s, %s", pe.Reg, pe.Reg)
+	}
+
+	return nil
+}
+
+// conversion emits the instructions of converting the value to type t, and
+// returns the register of the converted value. A struct is converted as it is,
+// since only its type changes.
+func (c *Compiler) conversion(value ast.Expression, t types.Type) (string, error) {
+	if err := c.Compile(value); err != nil {
+		return "", err
+	}
+	c.loadGlobalOrPtrValue(value)
+
+	v := value.Type()
+	reg := value.Register()
+
+	switch {
+	case v.Kind() == types.Float && t.Kind() != types.Float:
+		// The float is truncated towards zero like in Go.
+		r, err := c.registerTable.allocGeneral()
+		if err != nil {
+			return "", err
+		}
+		c.emitf("fcvt.l.d %s, %s, rtz", r, reg)
+
+		c.registerTable.dealloc(reg)
+		reg = r
+	case v.Kind() != types.Float && t.Kind() == types.Float:
+		r, err := c.registerTable.allocFloating()
+		if err != nil {
+			return "", err
+		}
+		c.emitf("fcvt.d.l %s, %s", r, reg)
+
+		c.registerTable.dealloc(reg)
+		reg = r
+	case v.Kind() == types.Byte && t.Kind() == types.String:
+		// The zeros after the byte terminate the string.
+		c.heapAllocate(8)
+		c.emitf("sd %s, 0(a0)", reg)
+		c.emitf("mv %s, a0", reg)
+	default:
+		if err := c.convert(v, t, reg); err != nil {
+			return "", err
+		}
+	}
+
+	if t.Kind() == types.Byte && v.Kind() != types.Byte {
+		c.emitf("andi %s, %s, 255", reg, reg)
+	}
+
+	return reg, nil
+}
+
+// logical emits the instructions of the logical operator, where the right
+// operand is only evaluated if the left operand does not decide the result.
+func (c *Compiler) logical(inf *ast.InfixExpression) error {
+	if err := c.Compile(inf.Left); err != nil {
+		return err
+	}
+	c.loadGlobalOrPtrValue(inf.Left)
+
+	left := inf.Left.Register()
+	doneLabel := c.label.create()
+
+	switch inf.Operator {
+	case "&&":
+		c.emitf("beqz %s, %s", left, doneLabel)
+	case "||":
+		c.emitf("bnez %s, %s", left, doneLabel)
 	default:
 		return fmt.Errorf("unknown operator: %s", inf.Operator)
 	}
 
+	if err := c.Compile(inf.Right); err != nil {
+		return err
+	}
+	c.loadGlobalOrPtrValue(inf.Right)
+
+	c.emitf("mv %s, %s", left, inf.Right.Register())
+	c.registerTable.dealloc(inf.Right.Register())
+
+	c.emitf("%s:", doneLabel)
+
+	inf.Reg = left
+
 	return nil
 }
 
@@ -723,21 +1377,202 @@ func (c *Compiler) arithmetic(operator, left, right string, t types.Type) {
 	}
 }
 
-func (c *Compiler) compare(operator, left, right string, t types.Type) {
-	trueLabel := c.label.create()
-	doneLabel := c.label.create()
-	switch t.Kind() {
+// concat emits the instructions which concatenate the string in the left
+// register with the string in the right register into a new string on the
+// heap, whose address is put in the left register.
+func (c *Compiler) concat(left, right string) {
+	c.emitf("mv a0, %s", left)
+	c.emitf("mv a1, %s", right)
+	c.emitf("call runtime.concat")
+	c.emitf("mv %s, a0", left)
+
+	c.useRuntime("runtime.concat")
+}
+
+// strlen emits the instructions which put the length of the string in the
+// register s into the register reg.
+func (c *Compiler) strlen(reg, s string) {
+	c.emitf("mv a0, %s", s)
+	c.emitf("call runtime.strlen")
+	c.emitf("mv %s, a0", reg)
+
+	c.useRuntime("runtime.strlen")
+}
+
+// branches maps the comparison operators to the branch instructions, which
+// branch if the comparison is true.
+var branches = map[string]string{
+	"==": "beq",
+	"!=": "bne",
+	"<":  "blt",
+	"<=": "ble",
+	">":  "bgt",
+	">=": "bge",
+}
+
+// comparison emits the instructions of the comparison operator on the operands
+// of the infix expression, which results in a bool.
+func (c *Compiler) comparison(inf *ast.InfixExpression) error {
+	left := inf.Left.Register()
+	right := inf.Right.Register()
+
+	switch inf.Left.Type().Kind() {
 	case types.Float:
-		// TODO: Maybe panicing over floating comparison is a bit excessive.
-		panic("compile error: floating point comparison is not implemented yet")
-	default:
-		c.emitf("%s %s, %s, %s", operator, left, right, trueLabel)
-		c.emitf("li %s, %d", left, cFalse)
-		c.emitf("b %s", doneLabel)
-		c.emitf("%s:", trueLabel)
-		c.emitf("li %s, %d", left, cTrue)
-		c.emitf("%s:", doneLabel)
+		// The floating point comparisons write the result to a normal
+		// register.
+		reg, err := c.registerTable.allocGeneral()
+		if err != nil {
+			return err
+		}
+
+		switch inf.Operator {
+		case "==":
+			c.emitf("feq.d %s, %s, %s", reg, left, right)
+		case "!=":
+			c.emitf("feq.d %s, %s, %s", reg, left, right)
+			c.emitf("xori %s, %s, %d", reg, reg, cTrue)
+		case "<":
+			c.emitf("flt.d %s, %s, %s", reg, left, right)
+		case "<=":
+			c.emitf("fle.d %s, %s, %s", reg, left, right)
+		case ">":
+			c.emitf("flt.d %s, %s, %s", reg, right, left)
+		case ">=":
+			c.emitf("fle.d %s, %s, %s", reg, right, left)
+		}
+
+		c.registerTable.dealloc(left)
+		inf.Reg = reg
+
+		return nil
+	case types.String:
+		// The strings are compared by runtime.cmpstring, whose result is
+		// compared to zero instead.
+		c.emitf("mv a0, %s", left)
+		c.emitf("mv a1, %s", right)
+		c.emitf("call runtime.cmpstring")
+		c.emitf("mv %s, a0", left)
+
+		c.useRuntime("runtime.cmpstring")
+
+		right = "zero"
+	case types.StructKind:
+		return c.structComparison(inf)
+	}
+
+	c.compare(branches[inf.Operator], left, right)
+
+	return nil
+}
+
+// structComparison emits the instructions of comparing the structs at the
+// addresses in the registers of the infix expression. The structs are equal if
+// every field is equal.
+func (c *Compiler) structComparison(inf *ast.InfixExpression) error {
+	left := inf.Left.Register()
+	right := inf.Right.Register()
+
+	notEqualLabel := c.label.create()
+	doneLabel := c.label.create()
+
+	if err := c.compareFields(inf.Left.Type().(*types.Struct), left, right, 0, notEqualLabel); err != nil {
+		return err
+	}
+
+	equal, notEqual := cTrue, cFalse
+	if inf.Operator == "!=" {
+		equal, notEqual = cFalse, cTrue
+	}
+
+	c.emitf("li %s, %d", left, equal)
+	c.emitf("b %s", doneLabel)
+	c.emitf("%s:", notEqualLabel)
+	c.emitf("li %s, %d", left, notEqual)
+	c.emitf("%s:", doneLabel)
+
+	return nil
+}
+
+// compareFields emits the instructions of comparing each field of the structs
+// at offset of the addresses in the left and the right register. It jumps to
+// the label if a field is not equal. A field of struct type is in place, so
+// its fields are compared as well.
+func (c *Compiler) compareFields(s *types.Struct, left, right string, offset int, notEqualLabel string) error {
+	for i, f := range s.Fields {
+		fieldOffset := offset + s.Offset(i)
+
+		var err error
+		if t, ok := f.Type.(*types.Struct); ok {
+			err = c.compareFields(t, left, right, fieldOffset, notEqualLabel)
+		} else {
+			err = c.compareField(f.Type, left, right, fieldOffset, notEqualLabel)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// compareField emits the instructions of comparing the field of type t at
+// offset of the addresses in the left and the right register. It jumps to the
+// label if the fields are not equal.
+func (c *Compiler) compareField(t types.Type, left, right string, offset int, notEqualLabel string) error {
+	if t.Kind() == types.String {
+		c.emitf("ld a0, %d(%s)", offset, left)
+		c.emitf("ld a1, %d(%s)", offset, right)
+		c.emitf("call runtime.cmpstring")
+		c.emitf("bnez a0, %s", notEqualLabel)
+
+		c.useRuntime("runtime.cmpstring")
+
+		return nil
+	}
+
+	l, err := c.allocateRegByType(t)
+	if err != nil {
+		return err
+	}
+	r, err := c.allocateRegByType(t)
+	if err != nil {
+		return err
+	}
+
+	if t.Kind() == types.Float {
+		tmp, err := c.registerTable.allocGeneral()
+		if err != nil {
+			return err
+		}
+
+		c.emitf("fld %s, %d(%s)", l, offset, left)
+		c.emitf("fld %s, %d(%s)", r, offset, right)
+		c.emitf("feq.d %s, %s, %s", tmp, l, r)
+		c.emitf("beqz %s, %s", tmp, notEqualLabel)
+
+		c.registerTable.dealloc(tmp)
+	} else {
+		c.emitf("ld %s, %d(%s)", l, offset, left)
+		c.emitf("ld %s, %d(%s)", r, offset, right)
+		c.emitf("bne %s, %s, %s", l, r, notEqualLabel)
 	}
+
+	c.registerTable.dealloc(l)
+	c.registerTable.dealloc(r)
+
+	return nil
+}
+
+func (c *Compiler) compare(operator, left, right string) {
+	trueLabel := c.label.create()
+	doneLabel := c.label.create()
+
+	c.emitf("%s %s, %s, %s", operator, left, right, trueLabel)
+	c.emitf("li %s, %d", left, cFalse)
+	c.emitf("b %s", doneLabel)
+	c.emitf("%s:", trueLabel)
+	c.emitf("li %s, %d", left, cTrue)
+	c.emitf("%s:", doneLabel)
 }
 
 func (c *Compiler) allocateRegByType(t types.Type) (string, error) {
@@ -754,15 +1589,16 @@ func (c *Compiler) allocateRegByType(t types.Type) (string, error) {
 // space on the heap for it.
 func (c *Compiler) createASMLabelIdentifier(name string, t types.Type) error {
 	switch t.Kind() {
-	case types.Int, types.String, types.Bool, types.Func:
+	case types.Int, types.Byte, types.String, types.Bool, types.Func, types.PointerKind, types.InterfaceKind:
 		// string identifiers are treated as memory address of the actual
 		// string.
 		c.addConstantf("%s: .dword 0", name)
 	case types.Float:
 		c.addConstantf("%s: .double 0", name)
-	case types.StructKind:
-		str := t.(*types.Struct)
-		c.heapAllocate(str.Size())
+	case types.StructKind, types.SliceKind:
+		// The variable holds the address of the struct or the slice header,
+		// which is allocated on the heap.
+		c.heapAllocate(heapSize(t))
 		reg, err := c.registerTable.allocGeneral()
 		if err != nil {
 			return err
@@ -773,6 +1609,27 @@ func (c *Compiler) createASMLabelIdentifier(name string, t types.Type) error {
 		c.addConstantf("%s: .dword 0", name)
 
 		c.registerTable.dealloc(reg)
+
+		if s, ok := t.(*types.Struct); ok {
+			c.allocateStructFields(s, "a0")
+		}
+	case types.ArrayKind:
+		// The elements are stored in place, so the array must be alligned
+		// to the word size.
+		c.addConstant(".align 3")
+		c.addConstantf("%s: .space %d", name, types.Sizeof(t))
+
+		if usesHeap(t) {
+			reg, err := c.registerTable.allocGeneral()
+			if err != nil {
+				return err
+			}
+			c.emitf("la %s, %s", reg, name)
+
+			c.allocateHeap(t, reg, 0)
+
+			c.registerTable.dealloc(reg)
+		}
 	default:
 		return fmt.Errorf("compiler error: could not create label: %s with type: %s", name, t)
 	}
@@ -830,12 +1687,544 @@ func (c *Compiler) stackDealloc(s int) {
 	c.emitf("addi sp, sp, %d", s)
 }
 
+// compileValues compiles the values of an assignment and returns the tuple of
+// them. A single call to a function with multiple results gives all of its
+// results.
+func (c *Compiler) compileValues(values []ast.Expression) (*tuple, error) {
+	if len(values) == 1 {
+		if call, ok := values[0].(*ast.CallExpression); ok && !c.isTypeName(call.Function) {
+			return c.call(call)
+		}
+	}
+
+	var ts []types.Type
+	for _, v := range values {
+		ts = append(ts, v.Type())
+	}
+
+	// Each value is set right after it has been compiled, so a spilled value
+	// never occupies a register while the next one is compiled.
+	tp := c.newTuple(ts)
+	for i, v := range values {
+		if err := c.Compile(v); err != nil {
+			return nil, err
+		}
+		c.loadGlobalOrPtrValue(v)
+
+		c.setValue(tp, i, v.Register())
+	}
+
+	return tp, nil
+}
+
+// function emits the instructions of the function with the label, where the
+// current symbol table is the table of the function. The receiver is nil
+// unless the function is a method.
+func (c *Compiler) function(label string, receiver *ast.Identifier, signature *ast.FuncType, body *ast.BlockStatement) error {
+	space := c.symbolTable.ComputeFrame()
+	c.frameSpace = space
+
+	c.emitf("%s:", label)
+	c.emitf("addi sp, sp, -%d", space)
+
+	// Save the arguments into the stack space of the parameters. The
+	// receiver of a method is the first argument.
+	var paramTypes []types.Type
+	if receiver != nil {
+		paramTypes = append(paramTypes, receiver.T)
+	}
+	for _, p := range signature.Parameters {
+		paramTypes = append(paramTypes, p.Type())
+	}
+
+	locations, _ := locate(paramTypes, argumentRegisters)
+	for i, sym := range c.symbolTable.Parameters() {
+		loc := locations[i]
+		if loc.reg == "" {
+			// The argument resides in the stack space of the caller, which
+			// is right above this frame.
+			reg, err := c.allocateRegByType(paramTypes[i])
+			if err != nil {
+				return err
+			}
+
+			ld, err := loadASM(paramTypes[i])
+			if err != nil {
+				return err
+			}
+
+			c.emitf(ld, reg, space+loc.offset)
+			loc.reg = reg
+
+			c.registerTable.dealloc(reg)
+		}
+
+		switch paramTypes[i].Kind() {
+		case types.Float:
+			c.emitf("fsd %s, %d(sp)", loc.reg, sym.Code().(int))
+		default:
+			c.emitf("sd %s, %d(sp)", loc.reg, sym.Code().(int))
+		}
+	}
+
+	c.emitf("sd ra, %d(sp)", space)
+
+	// The closure of a function literal is saved with the other variables.
+	if pos, ok := c.symbolTable.Closure(); ok {
+		c.emitf("sd %s, %d(sp)", contextRegister, pos)
+	}
+
+	// A value receiver is a copy, so the method can not change the struct of
+	// the caller. The copy is made by the method, as a call through an
+	// interface does not know whether the method takes a value.
+	if receiver != nil && receiver.T.Kind() == types.StructKind {
+		reg, err := c.registerTable.allocGeneral()
+		if err != nil {
+			return err
+		}
+
+		pos := c.symbolTable.Parameters()[0].Code().(int)
+		c.emitf("ld %s, %d(sp)", reg, pos)
+		c.copyStruct(receiver.T.(*types.Struct), reg)
+		c.emitf("sd %s, %d(sp)", reg, pos)
+
+		c.registerTable.dealloc(reg)
+	}
+
+	// A captured parameter is moved to the heap.
+	for i, sym := range c.symbolTable.Parameters() {
+		if !sym.Captured {
+			continue
+		}
+
+		reg, err := c.allocateRegByType(paramTypes[i])
+		if err != nil {
+			return err
+		}
+
+		ld, err := loadASM(paramTypes[i])
+		if err != nil {
+			return err
+		}
+
+		pos := sym.Code().(int)
+		c.emitf(ld, reg, pos)
+		c.heapAllocate(types.Sizeof(paramTypes[i]))
+		c.store(paramTypes[i], reg, "a0", 0)
+		c.emitf("sd a0, %d(sp)", pos)
+
+		c.registerTable.dealloc(reg)
+	}
+
+	if err := c.Compile(body); err != nil {
+		return err
+	}
+
+	// Epilogue of the function
+	c.emitf("%s.epilogue:", label)
+	c.emitf("ld ra, %d(sp)", space)
+	c.emitf("addi sp, sp, %d", space)
+	c.emitf("ret")
+
+	c.stackSpace = 0
+
+	return nil
+}
+
+// assign stores the value in the register regVal into name. The address of
+// name must already have been compiled.
+func (c *Compiler) assign(name ast.Expression, regVal string) {
+	regName := name.Register()
+
+	// The value is stored at offset(base).
+	base, offset := regName, 0
+	switch t := name.(type) {
+	case *ast.Identifier:
+		s, _ := c.symbolTable.Resolve(t.Value)
+		if !byAddress(s) {
+			base, offset = "sp", c.stackPosition(s)
+		}
+
+		if st, ok := t.T.(*types.Struct); ok {
+			// The variable holds the address of its struct, which is
+			// overwritten by the struct value. That way a pointer to the
+			// variable also sees the new value.
+			reg, err := c.registerTable.allocGeneral()
+			if err != nil {
+				// This should probably not happen as there are many
+				// registers to allocate, but if there is a error then panic.
+				panic(err)
+			}
+
+			c.emitf("ld %s, %d(%s)", reg, offset, base)
+			c.copy(reg, 0, regVal, st.Size())
+
+			c.registerTable.dealloc(reg)
+			c.registerTable.dealloc(regVal)
+			c.registerTable.dealloc(regName)
+			return
+		}
+	case *ast.SelectorExpression:
+		// The register holds the address of the struct.
+		offset = t.Offset
+		if s, ok := t.T.(*types.Struct); ok {
+			// The field holds the struct in place, so it is overwritten by
+			// the struct value.
+			c.copy(regName, offset, regVal, s.Size())
+			c.registerTable.dealloc(regVal)
+			c.registerTable.dealloc(regName)
+			return
+		}
+	case *ast.IndexExpression:
+		// The register holds the address of the element.
+		if s, ok := t.T.(*types.Struct); ok {
+			// The element holds the address of its struct, which is
+			// overwritten by the struct value.
+			reg, err := c.registerTable.allocGeneral()
+			if err != nil {
+				// This should probably not happen as there are many
+				// registers to allocate, but if there is a error then panic.
+				panic(err)
+			}
+
+			c.emitf("ld %s, 0(%s)", reg, regName)
+			c.copy(reg, 0, regVal, s.Size())
+
+			c.registerTable.dealloc(reg)
+			c.registerTable.dealloc(regVal)
+			c.registerTable.dealloc(regName)
+			return
+		}
+	case *ast.PrefixExpression:
+		// The register holds the address of the value pointed to.
+		if s, ok := t.T.(*types.Struct); ok {
+			// The struct pointed to is overwritten by the struct value.
+			c.copy(regName, 0, regVal, s.Size())
+			c.registerTable.dealloc(regVal)
+			c.registerTable.dealloc(regName)
+			return
+		}
+	default:
+		// We should never end here, so panic if we do.
+		panic("unhandled type in assign statement")
+	}
+
+	c.store(name.Type(), regVal, base, offset)
+
+	c.registerTable.dealloc(regVal)
+	c.registerTable.dealloc(regName)
+}
+
+// compoundAssign emits the instructions of the compound assignment e.g.
+// "x += 2", where the address of x is only computed once.
+func (c *Compiler) compoundAssign(name ast.Expression, operator string, value ast.Expression) error {
+	addr, err := c.address(name)
+	if err != nil {
+		return err
+	}
+
+	t := name.Type()
+
+	reg, err := c.allocateRegByType(t)
+	if err != nil {
+		return err
+	}
+
+	if t.Kind() == types.Float {
+		c.emitf("fld %s, 0(%s)", reg, addr)
+	} else {
+		c.emitf("ld %s, 0(%s)", reg, addr)
+	}
+
+	if err := c.Compile(value); err != nil {
+		return err
+	}
+	c.loadGlobalOrPtrValue(value)
+
+	if err := c.binary(operator, reg, value.Register(), t); err != nil {
+		return err
+	}
+	c.registerTable.dealloc(value.Register())
+
+	c.store(t, reg, addr, 0)
+
+	c.registerTable.dealloc(reg)
+	c.registerTable.dealloc(addr)
+
+	return nil
+}
+
+// store emits the instructions which store the value of type t in the register
+// regVal at offset(base).
+func (c *Compiler) store(t types.Type, regVal, base string, offset int) {
+	switch t.Kind() {
+	case types.Float:
+		c.emitf("fsd %s, %d(%s)", regVal, offset, base)
+	case types.ArrayKind:
+		// Every element of the array is copied.
+		c.copy(base, offset, regVal, types.Sizeof(t))
+	default:
+		c.emitf("sd %s, %d(%s)", regVal, offset, base)
+	}
+}
+
+// copy emits the instructions which copy size bytes from the address in the
+// register src to offset(dst).
+func (c *Compiler) copy(dst string, offset int, src string, size int) {
+	c.emitf("addi a0, %s, %d", dst, offset)
+	c.emitf("mv a1, %s", src)
+	c.emitf("li a2, %d", size)
+	c.emitf("call runtime.copy")
+	c.useRuntime("runtime.copy")
+}
+
+// copyStruct emits the instructions which copy the struct at the address in the
+// register reg to new space on the heap, and updates the register to hold the
+// address of the copy.
+func (c *Compiler) copyStruct(s *types.Struct, reg string) {
+	c.heapAllocate(s.Size())
+	c.emitf("mv a1, %s", reg)
+	c.emitf("mv %s, a0", reg)
+	c.emitf("li a2, %d", s.Size())
+	c.emitf("call runtime.copy")
+	c.useRuntime("runtime.copy")
+}
+
+// address compiles the expression into the address of its value and returns
+// the register holding it. Structs and arrays are already used by their
+// address.
+func (c *Compiler) address(expr ast.Expression) (string, error) {
+	switch expr.Type().Kind() {
+	case types.StructKind, types.ArrayKind:
+		if err := c.Compile(expr); err != nil {
+			return "", err
+		}
+		c.loadGlobalOrPtrValue(expr)
+
+		return expr.Register(), nil
+	}
+
+	switch e := expr.(type) {
+	case *ast.Identifier:
+		s, _ := c.symbolTable.Resolve(e.Value)
+		if !byAddress(s) {
+			reg, err := c.registerTable.allocGeneral()
+			if err != nil {
+				return "", err
+			}
+
+			c.emitf("addi %s, sp, %d", reg, c.stackPosition(s))
+
+			return reg, nil
+		}
+
+		// A global or captured identifier is compiled into its address.
+		if err := c.Compile(e); err != nil {
+			return "", err
+		}
+
+		return e.Reg, nil
+	case *ast.SelectorExpression:
+		if err := c.Compile(e); err != nil {
+			return "", err
+		}
+
+		c.emitf("addi %s, %s, %d", e.Reg, e.Reg, e.Offset)
+
+		return e.Reg, nil
+	case *ast.IndexExpression, *ast.PrefixExpression:
+		// The register already holds the address.
+		if err := c.Compile(e); err != nil {
+			return "", err
+		}
+
+		return e.Register(), nil
+	default:
+		return "", fmt.Errorf("compiler error: can not take the address of: %s", expr)
+	}
+}
+
+// stackPosition returns the position of a local symbol relative to the
+// current stack pointer.
+func (c *Compiler) stackPosition(s *symbol.Symbol) int {
+	return s.Code().(int) + c.callSpace
+}
+
 func (c *Compiler) heapAllocate(size int) {
 	c.emitf("li a0, %d", size)
 	c.emitf("li a7, 9")
 	c.emitf("ecall")
 }
 
+// allocateHeap allocates space on the heap for every struct and slice header
+// of the variable of type t stored at offset(base). A struct or slice variable
+// only holds the address of its heap space, while an array holds each of its
+// elements.
+func (c *Compiler) allocateHeap(t types.Type, base string, offset int) {
+	switch t := t.(type) {
+	case *types.Struct:
+		c.heapAllocate(t.Size())
+		c.emitf("sd a0, %d(%s)", offset, base)
+		c.allocateStructFields(t, "a0")
+	case *types.Slice:
+		c.heapAllocate(heapSize(t))
+		c.emitf("sd a0, %d(%s)", offset, base)
+	case *types.Array:
+		size := types.Sizeof(t.Elem)
+		for i := 0; i < t.Len; i++ {
+			c.allocateHeap(t.Elem, base, offset+i*size)
+		}
+	}
+}
+
+// compositeLiteral emits the instructions which allocate the struct of the
+// composite literal on the heap and store the values in its fields.
+func (c *Compiler) compositeLiteral(node *ast.CompositeLiteral) error {
+	s := node.T.(*types.Struct)
+
+	reg, err := c.registerTable.allocGeneral()
+	if err != nil {
+		return err
+	}
+
+	c.heapAllocate(s.Size())
+	c.emitf("mv %s, a0", reg)
+	c.allocateStructFields(s, reg)
+
+	for i, v := range node.Values {
+		field := i
+		if node.Keys[i] != nil {
+			field = fieldIndex(s, node.Keys[i].Value)
+		}
+		t, offset := s.Fields[field].Type, s.Offset(field)
+
+		if err := c.Compile(v); err != nil {
+			return err
+		}
+		c.loadGlobalOrPtrValue(v)
+
+		if err := c.convert(v.Type(), t, v.Register()); err != nil {
+			return err
+		}
+
+		if st, ok := t.(*types.Struct); ok {
+			// The field holds the struct in place.
+			c.copy(reg, offset, v.Register(), st.Size())
+		} else {
+			c.store(t, v.Register(), reg, offset)
+		}
+
+		c.registerTable.dealloc(v.Register())
+	}
+
+	node.Reg = reg
+
+	return nil
+}
+
+// fieldIndex returns the index of the field with the name in the struct.
+func fieldIndex(s *types.Struct, name string) int {
+	for i, f := range s.Fields {
+		if f.Name == name {
+			return i
+		}
+	}
+
+	panic(fmt.Sprintf("compiler error: field: %s is not in struct: %s", name, s))
+}
+
+// allocateStructFields allocates space on the heap for the fields of the struct
+// at the address in the register reg.
+func (c *Compiler) allocateStructFields(s *types.Struct, reg string) {
+	if !fieldsUseHeap(s) {
+		return
+	}
+
+	base, err := c.registerTable.allocGeneral()
+	if err != nil {
+		// This should probably not happen as there are many registers to
+		// allocate, but if there is a error then panic.
+		panic(err)
+	}
+	c.emitf("mv %s, %s", base, reg)
+
+	c.allocateFields(s, base, 0)
+
+	c.registerTable.dealloc(base)
+}
+
+// allocateFields allocates space on the heap for the fields of the struct
+// stored at offset(base). A field of struct type holds the struct in place, so
+// only its own fields are allocated.
+func (c *Compiler) allocateFields(s *types.Struct, base string, offset int) {
+	for i, f := range s.Fields {
+		switch t := f.Type.(type) {
+		case *types.Struct:
+			c.allocateFields(t, base, offset+s.Offset(i))
+		default:
+			c.allocateHeap(t, base, offset+s.Offset(i))
+		}
+	}
+}
+
+// fieldsUseHeap reports whether any of the fields of the struct holds any
+// structs or slices.
+func fieldsUseHeap(s *types.Struct) bool {
+	for _, f := range s.Fields {
+		switch t := f.Type.(type) {
+		case *types.Struct:
+			if fieldsUseHeap(t) {
+				return true
+			}
+		default:
+			if usesHeap(t) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// usesHeap reports whether a variable of type t holds any structs or slices.
+func usesHeap(t types.Type) bool {
+	switch t := t.(type) {
+	case *types.Struct, *types.Slice:
+		return true
+	case *types.Array:
+		return usesHeap(t.Elem)
+	default:
+		return false
+	}
+}
+
+// heapSize returns the size of the heap space of a struct or a slice header.
+func heapSize(t types.Type) int {
+	switch t := t.(type) {
+	case *types.Struct:
+		return t.Size()
+	case *types.Slice:
+		return sliceHeaderSize
+	default:
+		panic(fmt.Sprintf("compiler error: type: %s is not allocated on the heap", t))
+	}
+}
+
+// boundsCheck emits a check of the index being within the length, where both
+// are registers. If it is not, then the program panics.
+func (c *Compiler) boundsCheck(index, length string) {
+	okLabel := c.label.create()
+
+	// Comparing unsigned also catches a negative index.
+	c.emitf("bltu %s, %s, %s", index, length, okLabel)
+	c.emitf("mv a0, %s", index)
+	c.emitf("mv a1, %s", length)
+	c.emitf("j runtime.panicindex")
+	c.emitf("%s:", okLabel)
+
+	c.useRuntime("runtime.panicindex")
+}
+
 // Asm returns the compiled assembly code.
 func (c *Compiler) Asm() string {
 	var sb strings.Builder
@@ -864,6 +2253,11 @@ func (c *Compiler) Asm() string {
 		sb.WriteString("\n")
 		sb.WriteString(f)
 	}
+	for _, l := range c.literals {
+		sb.WriteString("\n")
+		sb.WriteString(l)
+	}
+	sb.WriteString(c.runtimeAsm())
 
 	if !c.isTest {
 		sb.WriteString("\n")
@@ -148,6 +148,213 @@ func TestArithmetic(t *testing.T) {
 			li t1, 5
 			div t0, t0, t1`,
 		},
+		{
+			input: "-2",
+			expected: `
+			.data
+			.text
+			li t0, -2
+			`,
+		},
+		{
+			input: "-2.5",
+			expected: `
+			.data
+			.L1: .double -2.5
+			.text
+			fld ft0, .L1, t0
+			`,
+		},
+		{
+			input: `var x int
+			print -x`,
+			expected: `
+			.data
+			x: .dword 0
+			.text
+			la s1, x
+			ld s1, 0(s1)
+			neg s1, s1
+			mv a0, s1
+			li a7, 1
+			ecall
+			`,
+		},
+		{
+			input: `var x float
+			print +x`,
+			expected: `
+			.data
+			x: .double 0
+			.text
+			la s1, x
+			fld ft0, 0(s1)
+			fmv.d fa0, ft0
+			li a7, 3
+			ecall
+			`,
+		},
+		{
+			input: "7 % 2",
+			expected: `
+			.data
+			.text
+			li t0, 7
+			li t1, 2
+			rem t0, t0, t1
+			`,
+		},
+		{
+			input: "6 & 3",
+			expected: `
+			.data
+			.text
+			li t0, 6
+			li t1, 3
+			and t0, t0, t1
+			`,
+		},
+		{
+			input: "6 | 3",
+			expected: `
+			.data
+			.text
+			li t0, 6
+			li t1, 3
+			or t0, t0, t1
+			`,
+		},
+		{
+			input: "6 ^ 3",
+			expected: `
+			.data
+			.text
+			li t0, 6
+			li t1, 3
+			xor t0, t0, t1
+			`,
+		},
+		{
+			input: "6 &^ 3",
+			expected: `
+			.data
+			.text
+			li t0, 6
+			li t1, 3
+			not t1, t1
+			and t0, t0, t1
+			`,
+		},
+		{
+			input: "1 << 3",
+			expected: `
+			.data
+			runtime.panicshift.msg0: .string "panic: runtime error: negative shift amount\n"
+			.text
+			li t0, 1
+			li t1, 3
+			bgez t1, .L1
+			j runtime.panicshift
+			.L1:
+			sltiu t2, t1, 64
+			neg t2, t2
+			sll t0, t0, t1
+			and t0, t0, t2
+			runtime.panicshift:
+			la a0, runtime.panicshift.msg0
+			li a7, 4
+			ecall
+			li a0, 2
+			li a7, 93
+			ecall
+			`,
+		},
+		{
+			input: "-8 >> 1",
+			expected: `
+			.data
+			runtime.panicshift.msg0: .string "panic: runtime error: negative shift amount\n"
+			.text
+			li t0, -8
+			li t1, 1
+			bgez t1, .L1
+			j runtime.panicshift
+			.L1:
+			sltiu t2, t1, 64
+			bnez t2, .L2
+			li t1, 63
+			.L2:
+			sra t0, t0, t1
+			runtime.panicshift:
+			la a0, runtime.panicshift.msg0
+			li a7, 4
+			ecall
+			li a0, 2
+			li a7, 93
+			ecall
+			`,
+		},
+		{
+			input: `"a" + "b"`,
+			expected: `
+			.data
+			.L1: .string "a"
+			.L2: .string "b"
+			.text
+			la t0, .L1
+			la t1, .L2
+			mv a0, t0
+			mv a1, t1
+			call runtime.concat
+			mv t0, a0
+			runtime.concat:
+			mv a2, a0
+			mv a3, a1
+			li a0, 1
+			mv a4, a2
+			beqz a4, runtime.concat.len1
+			runtime.concat.len0:
+			lbu a5, 0(a4)
+			beqz a5, runtime.concat.len1
+			addi a0, a0, 1
+			addi a4, a4, 1
+			j runtime.concat.len0
+			runtime.concat.len1:
+			mv a4, a3
+			beqz a4, runtime.concat.alloc
+			runtime.concat.len1.loop:
+			lbu a5, 0(a4)
+			beqz a5, runtime.concat.alloc
+			addi a0, a0, 1
+			addi a4, a4, 1
+			j runtime.concat.len1.loop
+			runtime.concat.alloc:
+			addi a0, a0, 7
+			andi a0, a0, -8
+			li a7, 9
+			ecall
+			mv a4, a0
+			beqz a2, runtime.concat.copy1
+			runtime.concat.copy0:
+			lbu a5, 0(a2)
+			beqz a5, runtime.concat.copy1
+			sb a5, 0(a4)
+			addi a2, a2, 1
+			addi a4, a4, 1
+			j runtime.concat.copy0
+			runtime.concat.copy1:
+			sb zero, 0(a4)
+			beqz a3, runtime.concat.done
+			runtime.concat.copy1.loop:
+			lbu a5, 0(a3)
+			sb a5, 0(a4)
+			addi a3, a3, 1
+			addi a4, a4, 1
+			bnez a5, runtime.concat.copy1.loop
+			runtime.concat.done:
+			ret
+			`,
+		},
 	}
 
 	runCompilerTests(t, tests)
@@ -361,9 +568,10 @@ func TestVarStatement(t *testing.T) {
 			expected: `
 			.data
 			x: .dword 0
+			incrementer.closure: .dword incrementer
 			.text
 			la s1, x
-			la s10, incrementer
+			la s10, incrementer.closure
 			sd s10, 0(s1)
 			incrementer:
 			addi sp, sp, -16
@@ -382,6 +590,99 @@ func TestVarStatement(t *testing.T) {
 			addi sp, sp, 16
 			ret`,
 		},
+		{
+			input: `
+			x := 2.5
+			var s = "a"`,
+			expected: `
+			.data
+			x: .double 0
+			.L1: .double 2.5
+			s: .dword 0
+			.L2: .string "a"
+			.text
+			la s1, x
+			fld ft0, .L1, t0
+			fsd ft0, 0(s1)
+			la s1, s
+			la t0, .L2
+			sd t0, 0(s1)
+			`,
+		},
+		{
+			input: `
+			func test() {
+				x := 2
+				for i := 0; i < x; i = i + 1 {
+					print i
+				}
+			}`,
+			expected: `
+			.data
+			.text
+			test:
+			addi sp, sp, -16
+			sd ra, 16(sp)
+			addi sp, sp, -16
+			li t0, 2
+			sd t0, 8(sp)
+			addi sp, sp, -16
+			li t0, 0
+			sd t0, 8(sp)
+			.L1:
+			ld t0, 8(sp)
+			ld t1, 24(sp)
+			blt t0, t1, .L3
+			li t0, 0
+			b .L4
+			.L3:
+			li t0, 1
+			.L4:
+			beqz t0, .L2
+			addi sp, sp, -0
+			ld t0, 8(sp)
+			mv a0, t0
+			li a7, 1
+			ecall
+			addi sp, sp, 0
+			ld t0, 8(sp)
+			li t1, 1
+			add t0, t0, t1
+			sd t0, 8(sp)
+			b .L1
+			.L2:
+			addi sp, sp, 16
+			addi sp, sp, 16
+			test.epilogue:
+			ld ra, 16(sp)
+			addi sp, sp, 16
+			ret
+			`,
+		},
+		{
+			input: `
+			x, y := 1, 2
+			y, z := 3, 4`,
+			expected: `
+			.data
+			x: .dword 0
+			y: .dword 0
+			z: .dword 0
+			.text
+			la s1, x
+			la s10, y
+			li t0, 1
+			li t1, 2
+			sd t0, 0(s1)
+			sd t1, 0(s10)
+			la s1, y
+			la s10, z
+			li t0, 3
+			li t1, 4
+			sd t0, 0(s1)
+			sd t1, 0(s10)
+			`,
+		},
 	}
 
 	runCompilerTests(t, tests)
@@ -482,9 +783,8 @@ func TestBlockStatement(t *testing.T) {
 			.data
 			.text
 			addi sp, sp, -16
-			ld t0, 8(sp)
-			li t1, 2
-			sd t1, 8(sp)
+			li t0, 2
+			sd t0, 8(sp)
 			addi sp, sp, 16`,
 		},
 		{
@@ -497,9 +797,8 @@ func TestBlockStatement(t *testing.T) {
 			.L1: .string "Hello Block statement"
 			.text
 			addi sp, sp, -16
-			ld t0, 8(sp)
-			la t1, .L1
-			sd t1, 8(sp)
+			la t0, .L1
+			sd t0, 8(sp)
 			addi sp, sp, 16`,
 		},
 		{
@@ -514,12 +813,10 @@ func TestBlockStatement(t *testing.T) {
 			.L1: .double 32
 			.text
 			addi sp, sp, -16
-			fld ft0, 16(sp)
-			fld ft1, .L1, t0
-			fsd ft1, 16(sp)
-			ld t0, 8(sp)
-			li t1, 2
-			sd t1, 8(sp)
+			fld ft0, .L1, t0
+			fsd ft0, 16(sp)
+			li t0, 2
+			sd t0, 8(sp)
 			addi sp, sp, 16`,
 		},
 	}
@@ -543,151 +840,82 @@ func TestConditional(t *testing.T) {
 			li t0, 1
 			.L2:`,
 		},
-	}
-
-	runCompilerTests(t, tests)
-}
-
-func TestIfStatement(t *testing.T) {
-	tests := []compilerTest{
 		{
-			input: `
-			if 2 < 3 {
-				print 2
-			}`,
+			input: "2 >= 3",
 			expected: `
 			.data
 			.text
 			li t0, 2
 			li t1, 3
-			blt t0, t1, .L1
+			bge t0, t1, .L1
 			li t0, 0
 			b .L2
 			.L1:
 			li t0, 1
 			.L2:
-			beqz t0, .L3
-			addi sp, sp, -0
-			li t0, 2
-			mv a0, t0
-			li a7, 1
-			ecall
-			addi sp, sp, 0
-			b .L4
-			.L3:
-			.L4:
 			`,
 		},
 		{
-			input: `
-			if 2 < 3 {
-				print 2
-			} else {
-				print 3
-			}`,
+			input: "2.0 > 3.0",
 			expected: `
 			.data
+			.L1: .double 2
+			.L2: .double 3
 			.text
-			li t0, 2
-			li t1, 3
-			blt t0, t1, .L1
-			li t0, 0
-			b .L2
-			.L1:
-			li t0, 1
-			.L2:
-			beqz t0, .L3
-			addi sp, sp, -0
-			li t0, 2
-			mv a0, t0
-			li a7, 1
-			ecall
-			addi sp, sp, 0
-			b .L4
-			.L3:
-			addi sp, sp, -0
-			li t0, 3
-			mv a0, t0
-			li a7, 1
-			ecall
-			addi sp, sp, 0
-			.L4:
+			fld ft0, .L1, t0
+			fld ft1, .L2, t0
+			flt.d t0, ft1, ft0
 			`,
 		},
 		{
-			input: `
-			if 2 < 3 {
-				var x int = 2
-				print x
-			} else {
-				var x int = 3
-				print x
-			}`,
+			input: "2.0 != 3.0",
 			expected: `
 			.data
+			.L1: .double 2
+			.L2: .double 3
 			.text
-			li t0, 2
-			li t1, 3
-			blt t0, t1, .L1
-			li t0, 0
-			b .L2
-			.L1:
-			li t0, 1
-			.L2:
-			beqz t0, .L3
-			addi sp, sp, -16
-			ld t0, 8(sp)
-			li t1, 2
-			sd t1, 8(sp)
-			ld t0, 8(sp)
-			mv a0, t0
-			li a7, 1
-			ecall
-			addi sp, sp, 16
-			b .L4
-			.L3:
-			addi sp, sp, -16
-			ld t0, 8(sp)
-			li t1, 3
-			sd t1, 8(sp)
-			ld t0, 8(sp)
-			mv a0, t0
-			li a7, 1
-			ecall
-			addi sp, sp, 16
-			.L4:
+			fld ft0, .L1, t0
+			fld ft1, .L2, t0
+			feq.d t0, ft0, ft1
+			xori t0, t0, 1
 			`,
 		},
 		{
-			input: `
-			if 2 + 2 < 3 + 3 {
-				print 2
-			}`,
+			input: `"a" <= "b"`,
 			expected: `
 			.data
+			.L1: .string "a"
+			.L2: .string "b"
 			.text
-			li t0, 2
-			li t1, 2
-			add t0, t0, t1
-			li t1, 3
-			li t2, 3
-			add t1, t1, t2
-			blt t0, t1, .L1
-			li t0, 0
-			b .L2
-			.L1:
-			li t0, 1
-			.L2:
-			beqz t0, .L3
-			addi sp, sp, -0
-			li t0, 2
+			la t0, .L1
+			la t1, .L2
 			mv a0, t0
-			li a7, 1
-			ecall
-			addi sp, sp, 0
+			mv a1, t1
+			call runtime.cmpstring
+			mv t0, a0
+			ble t0, zero, .L3
+			li t0, 0
 			b .L4
 			.L3:
+			li t0, 1
 			.L4:
+			runtime.cmpstring:
+			li a2, 0
+			beqz a0, runtime.cmpstring.right
+			lbu a2, 0(a0)
+			runtime.cmpstring.right:
+			li a3, 0
+			beqz a1, runtime.cmpstring.test
+			lbu a3, 0(a1)
+			runtime.cmpstring.test:
+			bne a2, a3, runtime.cmpstring.done
+			beqz a2, runtime.cmpstring.done
+			addi a0, a0, 1
+			addi a1, a1, 1
+			j runtime.cmpstring
+			runtime.cmpstring.done:
+			sub a0, a2, a3
+			ret
 			`,
 		},
 	}
@@ -695,532 +923,3483 @@ func TestIfStatement(t *testing.T) {
 	runCompilerTests(t, tests)
 }
 
-func TestForStatement(t *testing.T) {
+func TestLogical(t *testing.T) {
 	tests := []compilerTest{
 		{
-			input: `
-			for var i int = 0; i < 10; i = i + 1 {
-				print i
-			}`,
+			input: "true && false",
 			expected: `
 			.data
 			.text
-			addi sp, sp, -16
-			ld t0, 8(sp)
+			li t0, 1
+			beqz t0, .L1
 			li t1, 0
-			sd t1, 8(sp)
+			mv t0, t1
 			.L1:
-			ld t0, 8(sp)
-			li t1, 10
-			blt t0, t1, .L3
-			li t0, 0
-			b .L4
-			.L3:
-			li t0, 1
-			.L4:
-			beqz t0, .L2
-			addi sp, sp, -0
-			ld t0, 8(sp)
-			mv a0, t0
-			li a7, 1
-			ecall
-			addi sp, sp, 0
-			ld t0, 8(sp)
-			ld t1, 8(sp)
-			li t2, 1
-			add t1, t1, t2
-			sd t1, 8(sp)
-			b .L1
-			.L2:
-			addi sp, sp, 16`,
+			`,
 		},
-	}
-
-	runCompilerTests(t, tests)
-}
-
-func TestTypeStatement(t *testing.T) {
-	tests := []compilerTest{
 		{
-			input: `
-			type human struct{name string}
+			input: "true || false",
+			expected: `
+			.data
+			.text
+			li t0, 1
+			bnez t0, .L1
+			li t1, 0
+			mv t0, t1
+			.L1:
 			`,
+		},
+		{
+			input: "!true",
 			expected: `
 			.data
-			.text`,
+			.text
+			li t0, 1
+			xori t0, t0, 1
+			`,
 		},
 	}
 
 	runCompilerTests(t, tests)
 }
 
-func TestSelectorExpression(t *testing.T) {
+func TestCompoundAssign(t *testing.T) {
 	tests := []compilerTest{
 		{
 			input: `
-			type human struct{name string}
-			var x human
-			print x.name
+			func test() {
+				x := 2
+				x += 3
+				x++
+			}`,
+			expected: `
+			.data
+			.text
+			test:
+			addi sp, sp, -16
+			sd ra, 16(sp)
+			addi sp, sp, -16
+			li t0, 2
+			sd t0, 8(sp)
+			addi t0, sp, 8
+			ld t1, 0(t0)
+			li t2, 3
+			add t1, t1, t2
+			sd t1, 0(t0)
+			addi t0, sp, 8
+			ld t1, 0(t0)
+			li t2, 1
+			add t1, t1, t2
+			sd t1, 0(t0)
+			addi sp, sp, 16
+			test.epilogue:
+			ld ra, 16(sp)
+			addi sp, sp, 16
+			ret
 			`,
+		},
+		{
+			input: `
+			type point struct { x int; y float }
+			var p point
+			p.x -= 1
+			p.y *= 2.0
+			p.y--`,
 			expected: `
 			.data
-			x: .dword 0
+			p: .dword 0
+			.L1: .double 2
+			.L2: .double 1
 			.text
-			li a0, 8
+			li a0, 16
 			li a7, 9
 			ecall
-			la t0, x
+			la t0, p
 			sd a0, 0(t0)
-			la s1, x
+			la s1, p
+			ld s1, 0(s1)
+			addi s1, s1, 0
+			ld t0, 0(s1)
+			li t1, 1
+			sub t0, t0, t1
+			sd t0, 0(s1)
+			la s1, p
 			ld s1, 0(s1)
+			addi s1, s1, 8
+			fld ft0, 0(s1)
+			fld ft1, .L1, t0
+			fmul.d ft0, ft0, ft1
+			fsd ft0, 0(s1)
+			la s1, p
 			ld s1, 0(s1)
-			mv a0, s1
-			li a7, 4
-			ecall`,
+			addi s1, s1, 8
+			fld ft0, 0(s1)
+			fld ft1, .L2, t0
+			fsub.d ft0, ft0, ft1
+			fsd ft0, 0(s1)
+			`,
 		},
 		{
 			input: `
-			type human struct{name string}
-
-			var x human
-
-			x.name = "Mads"
-			`,
+			var s string = "a"
+			s += "b"`,
 			expected: `
 			.data
-			x: .dword 0
-			.L1: .string "Mads"
+			s: .dword 0
+			.L1: .string "a"
+			.L2: .string "b"
 			.text
-			li a0, 8
+			la s1, s
+			la t0, .L1
+			sd t0, 0(s1)
+			la s1, s
+			ld t0, 0(s1)
+			la t1, .L2
+			mv a0, t0
+			mv a1, t1
+			call runtime.concat
+			mv t0, a0
+			sd t0, 0(s1)
+			runtime.concat:
+			mv a2, a0
+			mv a3, a1
+			li a0, 1
+			mv a4, a2
+			beqz a4, runtime.concat.len1
+			runtime.concat.len0:
+			lbu a5, 0(a4)
+			beqz a5, runtime.concat.len1
+			addi a0, a0, 1
+			addi a4, a4, 1
+			j runtime.concat.len0
+			runtime.concat.len1:
+			mv a4, a3
+			beqz a4, runtime.concat.alloc
+			runtime.concat.len1.loop:
+			lbu a5, 0(a4)
+			beqz a5, runtime.concat.alloc
+			addi a0, a0, 1
+			addi a4, a4, 1
+			j runtime.concat.len1.loop
+			runtime.concat.alloc:
+			addi a0, a0, 7
+			andi a0, a0, -8
 			li a7, 9
 			ecall
-			la t0, x
-			sd a0, 0(t0)
-			la s1, x
-			ld s1, 0(s1)
-			la t0, .L1
-			sd t0, 0(s1)`,
+			mv a4, a0
+			beqz a2, runtime.concat.copy1
+			runtime.concat.copy0:
+			lbu a5, 0(a2)
+			beqz a5, runtime.concat.copy1
+			sb a5, 0(a4)
+			addi a2, a2, 1
+			addi a4, a4, 1
+			j runtime.concat.copy0
+			runtime.concat.copy1:
+			sb zero, 0(a4)
+			beqz a3, runtime.concat.done
+			runtime.concat.copy1.loop:
+			lbu a5, 0(a3)
+			sb a5, 0(a4)
+			addi a3, a3, 1
+			addi a4, a4, 1
+			bnez a5, runtime.concat.copy1.loop
+			runtime.concat.done:
+			ret
+			`,
 		},
 	}
 
 	runCompilerTests(t, tests)
 }
 
-func TestFuncStatement(t *testing.T) {
+func TestIfStatement(t *testing.T) {
 	tests := []compilerTest{
 		{
-			input: `func greeter() {
-				print true
+			input: `
+			if 2 < 3 {
+				print 2
 			}`,
 			expected: `
 			.data
 			.text
-			greeter:
-			addi sp, sp, -16
-			sd ra, 16(sp)
-			addi sp, sp, -0
+			li t0, 2
+			li t1, 3
+			blt t0, t1, .L1
+			li t0, 0
+			b .L2
+			.L1:
 			li t0, 1
+			.L2:
+			beqz t0, .L3
+			addi sp, sp, -0
+			li t0, 2
 			mv a0, t0
 			li a7, 1
 			ecall
 			addi sp, sp, 0
-			greeter.epilogue:
-			ld ra, 16(sp)
-			addi sp, sp, 16
-			ret
+			b .L4
+			.L3:
+			.L4:
 			`,
 		},
 		{
-			input: `func greeter(x int) {
-				print x
+			input: `
+			if 2 < 3 {
+				print 2
+			} else {
+				print 3
 			}`,
 			expected: `
 			.data
 			.text
-			greeter:
-			addi sp, sp, -16
-			sd a0, 8(sp)
-			sd ra, 16(sp)
+			li t0, 2
+			li t1, 3
+			blt t0, t1, .L1
+			li t0, 0
+			b .L2
+			.L1:
+			li t0, 1
+			.L2:
+			beqz t0, .L3
 			addi sp, sp, -0
-			ld t0, 8(sp)
+			li t0, 2
 			mv a0, t0
 			li a7, 1
 			ecall
 			addi sp, sp, 0
-			greeter.epilogue:
-			ld ra, 16(sp)
-			addi sp, sp, 16
-			ret
+			b .L4
+			.L3:
+			addi sp, sp, -0
+			li t0, 3
+			mv a0, t0
+			li a7, 1
+			ecall
+			addi sp, sp, 0
+			.L4:
 			`,
 		},
 		{
-			input: `func greeter(x string) {
+			input: `
+			if 2 < 3 {
+				var x int = 2
+				print x
+			} else {
+				var x int = 3
 				print x
 			}`,
 			expected: `
 			.data
 			.text
-			greeter:
+			li t0, 2
+			li t1, 3
+			blt t0, t1, .L1
+			li t0, 0
+			b .L2
+			.L1:
+			li t0, 1
+			.L2:
+			beqz t0, .L3
 			addi sp, sp, -16
-			sd a0, 8(sp)
-			sd ra, 16(sp)
-			addi sp, sp, -0
+			li t0, 2
+			sd t0, 8(sp)
 			ld t0, 8(sp)
 			mv a0, t0
-			li a7, 4
+			li a7, 1
 			ecall
-			addi sp, sp, 0
-			greeter.epilogue:
-			ld ra, 16(sp)
 			addi sp, sp, 16
-			ret
+			b .L4
+			.L3:
+			addi sp, sp, -16
+			li t0, 3
+			sd t0, 8(sp)
+			ld t0, 8(sp)
+			mv a0, t0
+			li a7, 1
+			ecall
+			addi sp, sp, 16
+			.L4:
 			`,
 		},
 		{
-			input: `func greeter(x string) string {
-				return x
+			input: `
+			if 2 + 2 < 3 + 3 {
+				print 2
 			}`,
 			expected: `
 			.data
 			.text
-			greeter:
-			addi sp, sp, -16
-			sd a0, 8(sp)
-			sd ra, 16(sp)
+			li t0, 2
+			li t1, 2
+			add t0, t0, t1
+			li t1, 3
+			li t2, 3
+			add t1, t1, t2
+			blt t0, t1, .L1
+			li t0, 0
+			b .L2
+			.L1:
+			li t0, 1
+			.L2:
+			beqz t0, .L3
 			addi sp, sp, -0
-			ld t0, 8(sp)
+			li t0, 2
 			mv a0, t0
+			li a7, 1
+			ecall
 			addi sp, sp, 0
-			j greeter.epilogue
-			addi sp, sp, 0
-			greeter.epilogue:
-			ld ra, 16(sp)
-			addi sp, sp, 16
-			ret
+			b .L4
+			.L3:
+			.L4:
 			`,
 		},
 		{
 			input: `
-			type human struct{name string; age int}
-			func greeter() human {
-				var x human
-				return x
+			if 2 < 3 {
+				print 2
+			} else if 3 < 4 {
+				print 3
+			} else {
+				print 4
 			}`,
 			expected: `
 			.data
 			.text
-			greeter:
-			addi sp, sp, -16
-			sd ra, 16(sp)
-			addi sp, sp, -16
-			li a0, 16
-			li a7, 9
+			li t0, 2
+			li t1, 3
+			blt t0, t1, .L1
+			li t0, 0
+			b .L2
+			.L1:
+			li t0, 1
+			.L2:
+			beqz t0, .L3
+			addi sp, sp, -0
+			li t0, 2
+			mv a0, t0
+			li a7, 1
 			ecall
-			sd a0, 8(sp)
-			ld t0, 8(sp)
+			addi sp, sp, 0
+			b .L4
+			.L3:
+			li t0, 3
+			li t1, 4
+			blt t0, t1, .L5
+			li t0, 0
+			b .L6
+			.L5:
+			li t0, 1
+			.L6:
+			beqz t0, .L7
+			addi sp, sp, -0
+			li t0, 3
 			mv a0, t0
-			addi sp, sp, 16
-			j greeter.epilogue
-			addi sp, sp, 16
-			greeter.epilogue:
-			ld ra, 16(sp)
-			addi sp, sp, 16
-			ret
+			li a7, 1
+			ecall
+			addi sp, sp, 0
+			b .L8
+			.L7:
+			addi sp, sp, -0
+			li t0, 4
+			mv a0, t0
+			li a7, 1
+			ecall
+			addi sp, sp, 0
+			.L8:
+			.L4:
 			`,
 		},
+	}
+
+	runCompilerTests(t, tests)
+}
+
+func TestForStatement(t *testing.T) {
+	tests := []compilerTest{
 		{
 			input: `
-			type human struct{name string; age int}
-			func greeter(x human) int {
-				return x.age
+			for var i int = 0; i < 10; i = i + 1 {
+				print i
 			}`,
 			expected: `
 			.data
 			.text
-			greeter:
 			addi sp, sp, -16
-			sd a0, 8(sp)
-			sd ra, 16(sp)
+			li t0, 0
+			sd t0, 8(sp)
+			.L1:
+			ld t0, 8(sp)
+			li t1, 10
+			blt t0, t1, .L3
+			li t0, 0
+			b .L4
+			.L3:
+			li t0, 1
+			.L4:
+			beqz t0, .L2
 			addi sp, sp, -0
 			ld t0, 8(sp)
-			ld t0, 8(t0)
 			mv a0, t0
+			li a7, 1
+			ecall
 			addi sp, sp, 0
-			j greeter.epilogue
+			ld t0, 8(sp)
+			li t1, 1
+			add t0, t0, t1
+			sd t0, 8(sp)
+			b .L1
+			.L2:
+			addi sp, sp, 16`,
+		},
+		{
+			input: `
+			var i int = 0
+			for i < 3 {
+				i = i + 1
+			}`,
+			expected: `
+			.data
+			i: .dword 0
+			.text
+			la s1, i
+			li t0, 0
+			sd t0, 0(s1)
+			addi sp, sp, -0
+			.L1:
+			la s1, i
+			ld s1, 0(s1)
+			li t0, 3
+			blt s1, t0, .L3
+			li s1, 0
+			b .L4
+			.L3:
+			li s1, 1
+			.L4:
+			beqz s1, .L2
+			addi sp, sp, -0
+			la s1, i
+			la s10, i
+			ld s10, 0(s10)
+			li t0, 1
+			add s10, s10, t0
+			sd s10, 0(s1)
 			addi sp, sp, 0
-			greeter.epilogue:
-			ld ra, 16(sp)
-			addi sp, sp, 16
-			ret
+			b .L1
+			.L2:
+			addi sp, sp, 0
+			`,
+		},
+		{
+			input: `
+			for {
+				break
+			}`,
+			expected: `
+			.data
+			.text
+			addi sp, sp, -0
+			.L1:
+			addi sp, sp, -0
+			b .L2
+			addi sp, sp, 0
+			b .L1
+			.L2:
+			addi sp, sp, 0
+			`,
+		},
+	}
+
+	runCompilerTests(t, tests)
+}
+
+func TestSwitchStatement(t *testing.T) {
+	tests := []compilerTest{
+		{
+			input: `
+			var x int = 2
+			switch x {
+			case 1, 2:
+				print 1
+			default:
+				print 2
+			}`,
+			expected: `
+			.data
+			x: .dword 0
+			.text
+			la s1, x
+			li t0, 2
+			sd t0, 0(s1)
+			la s1, x
+			ld s1, 0(s1)
+			li t0, 1
+			beq s1, t0, .L2
+			li t0, 2
+			beq s1, t0, .L2
+			b .L3
+			.L2:
+			addi sp, sp, -0
+			li t0, 1
+			mv a0, t0
+			li a7, 1
+			ecall
+			addi sp, sp, 0
+			b .L1
+			.L3:
+			addi sp, sp, -0
+			li t0, 2
+			mv a0, t0
+			li a7, 1
+			ecall
+			addi sp, sp, 0
+			.L1:
+			`,
+		},
+		{
+			input: `
+			var x int = 2
+			switch {
+			case x < 3:
+				break
+			}`,
+			expected: `
+			.data
+			x: .dword 0
+			.text
+			la s1, x
+			li t0, 2
+			sd t0, 0(s1)
+			la s1, x
+			ld s1, 0(s1)
+			li t0, 3
+			blt s1, t0, .L3
+			li s1, 0
+			b .L4
+			.L3:
+			li s1, 1
+			.L4:
+			bnez s1, .L2
+			b .L1
+			.L2:
+			addi sp, sp, -0
+			b .L1
+			addi sp, sp, 0
+			.L1:
+			`,
+		},
+		{
+			input: `
+			var x int = 2
+			switch x {
+			case 1:
+				print 1
+			case 2, 4:
+				print 2
+			case 5:
+				print 5
+			}`,
+			expected: `
+			.data
+			x: .dword 0
+			.L5: .dword .L2, .L3, .L1, .L3, .L4
+			.text
+			la s1, x
+			li t0, 2
+			sd t0, 0(s1)
+			la s1, x
+			ld s1, 0(s1)
+			li t0, 1
+			sub s1, s1, t0
+			li t0, 5
+			bgeu s1, t0, .L1
+			slli s1, s1, 3
+			la t0, .L5
+			add s1, s1, t0
+			ld s1, 0(s1)
+			jr s1
+			.L2:
+			addi sp, sp, -0
+			li t0, 1
+			mv a0, t0
+			li a7, 1
+			ecall
+			addi sp, sp, 0
+			b .L1
+			.L3:
+			addi sp, sp, -0
+			li t0, 2
+			mv a0, t0
+			li a7, 1
+			ecall
+			addi sp, sp, 0
+			b .L1
+			.L4:
+			addi sp, sp, -0
+			li t0, 5
+			mv a0, t0
+			li a7, 1
+			ecall
+			addi sp, sp, 0
+			.L1:
+			`,
+		},
+		{
+			// The span of the values does not fit an int64, so the
+			// values are compared one by one.
+			input: `
+			const lo = -9223372036854775807 - 1
+			var x int = 1
+			switch x {
+			case lo:
+				print 1
+			case 0:
+				print 2
+			case 1:
+				print 3
+			case 9223372036854775807:
+				print 4
+			}`,
+			expected: `
+			.data
+			x: .dword 0
+			.text
+			la s1, x
+			li t0, 1
+			sd t0, 0(s1)
+			la s1, x
+			ld s1, 0(s1)
+			li t0, -9223372036854775808
+			beq s1, t0, .L2
+			li t0, 0
+			beq s1, t0, .L3
+			li t0, 1
+			beq s1, t0, .L4
+			li t0, 9223372036854775807
+			beq s1, t0, .L5
+			b .L1
+			.L2:
+			addi sp, sp, -0
+			li t0, 1
+			mv a0, t0
+			li a7, 1
+			ecall
+			addi sp, sp, 0
+			b .L1
+			.L3:
+			addi sp, sp, -0
+			li t0, 2
+			mv a0, t0
+			li a7, 1
+			ecall
+			addi sp, sp, 0
+			b .L1
+			.L4:
+			addi sp, sp, -0
+			li t0, 3
+			mv a0, t0
+			li a7, 1
+			ecall
+			addi sp, sp, 0
+			b .L1
+			.L5:
+			addi sp, sp, -0
+			li t0, 4
+			mv a0, t0
+			li a7, 1
+			ecall
+			addi sp, sp, 0
+			.L1:
+			`,
+		},
+	}
+
+	runCompilerTests(t, tests)
+}
+
+func TestBranchStatement(t *testing.T) {
+	tests := []compilerTest{
+		{
+			input: `
+			for var i int = 0; i < 10; i = i + 1 {
+				if i == 2 {
+					continue
+				}
+				break
+			}`,
+			expected: `
+			.data
+			.text
+			addi sp, sp, -16
+			li t0, 0
+			sd t0, 8(sp)
+			.L1:
+			ld t0, 8(sp)
+			li t1, 10
+			blt t0, t1, .L3
+			li t0, 0
+			b .L4
+			.L3:
+			li t0, 1
+			.L4:
+			beqz t0, .L2
+			addi sp, sp, -0
+			ld t0, 8(sp)
+			li t1, 2
+			beq t0, t1, .L5
+			li t0, 0
+			b .L6
+			.L5:
+			li t0, 1
+			.L6:
+			beqz t0, .L7
+			addi sp, sp, -0
+			b .L9
+			addi sp, sp, 0
+			b .L8
+			.L7:
+			.L8:
+			b .L2
+			addi sp, sp, 0
+			.L9:
+			ld t0, 8(sp)
+			li t1, 1
+			add t0, t0, t1
+			sd t0, 8(sp)
+			b .L1
+			.L2:
+			addi sp, sp, 16
+			`,
+		},
+		{
+			input: `
+			outer: for var i int = 0; i < 10; i = i + 1 {
+				for var j int = 0; j < 10; j = j + 1 {
+					var k int = j
+					break outer
+				}
+			}`,
+			expected: `
+			.data
+			.text
+			addi sp, sp, -16
+			li t0, 0
+			sd t0, 8(sp)
+			.L1:
+			ld t0, 8(sp)
+			li t1, 10
+			blt t0, t1, .L3
+			li t0, 0
+			b .L4
+			.L3:
+			li t0, 1
+			.L4:
+			beqz t0, .L2
+			addi sp, sp, -0
+			addi sp, sp, -16
+			li t0, 0
+			sd t0, 8(sp)
+			.L5:
+			ld t0, 8(sp)
+			li t1, 10
+			blt t0, t1, .L7
+			li t0, 0
+			b .L8
+			.L7:
+			li t0, 1
+			.L8:
+			beqz t0, .L6
+			addi sp, sp, -16
+			ld t0, 24(sp)
+			sd t0, 8(sp)
+			addi sp, sp, 32
+			b .L2
+			addi sp, sp, 16
+			ld t0, 8(sp)
+			li t1, 1
+			add t0, t0, t1
+			sd t0, 8(sp)
+			b .L5
+			.L6:
+			addi sp, sp, 16
+			addi sp, sp, 0
+			ld t0, 8(sp)
+			li t1, 1
+			add t0, t0, t1
+			sd t0, 8(sp)
+			b .L1
+			.L2:
+			addi sp, sp, 16
+			`,
+		},
+	}
+
+	runCompilerTests(t, tests)
+}
+
+func TestTypeStatement(t *testing.T) {
+	tests := []compilerTest{
+		{
+			input: `
+			type human struct{name string}
+			`,
+			expected: `
+			.data
+			.text`,
+		},
+	}
+
+	runCompilerTests(t, tests)
+}
+
+func TestSelectorExpression(t *testing.T) {
+	tests := []compilerTest{
+		{
+			input: `
+			type human struct{name string}
+			var x human
+			print x.name
+			`,
+			expected: `
+			.data
+			x: .dword 0
+			.text
+			li a0, 8
+			li a7, 9
+			ecall
+			la t0, x
+			sd a0, 0(t0)
+			la s1, x
+			ld s1, 0(s1)
+			ld s1, 0(s1)
+			mv a0, s1
+			li a7, 4
+			ecall`,
+		},
+		{
+			input: `
+			type human struct{name string}
+
+			var x human
+
+			x.name = "Mads"
+			`,
+			expected: `
+			.data
+			x: .dword 0
+			.L1: .string "Mads"
+			.text
+			li a0, 8
+			li a7, 9
+			ecall
+			la t0, x
+			sd a0, 0(t0)
+			la s1, x
+			ld s1, 0(s1)
+			la t0, .L1
+			sd t0, 0(s1)`,
+		},
+		{
+			input: `
+			type point struct{x int; y float}
+			type line struct{a point; b point}
+
+			var l line
+
+			l.b.y = l.a.y
+			`,
+			expected: `
+			.data
+			l: .dword 0
+			.text
+			li a0, 32
+			li a7, 9
+			ecall
+			la t0, l
+			sd a0, 0(t0)
+			la s1, l
+			ld s1, 0(s1)
+			addi s1, s1, 16
+			la s10, l
+			ld s10, 0(s10)
+			addi s10, s10, 0
+			fld ft0, 8(s10)
+			fsd ft0, 8(s1)
+			`,
+		},
+		{
+			input: `
+			type shape struct{name string; area func(int) int}
+
+			var s shape
+
+			print s.area(2)
+			`,
+			expected: `
+			.data
+			s: .dword 0
+			.text
+			li a0, 16
+			li a7, 9
+			ecall
+			la t0, s
+			sd a0, 0(t0)
+			addi sp, sp, -16
+			la s1, s
+			ld s1, 0(s1)
+			ld s1, 8(s1)
+			li t0, 2
+			sd t0, 8(sp)
+			ld a0, 8(sp)
+			mv s11, s1
+			ld s1, 0(s1)
+			jalr s1
+			mv t0, a0
+			addi sp, sp, 16
+			mv a0, t0
+			li a7, 1
+			ecall
+			`,
+		},
+	}
+
+	runCompilerTests(t, tests)
+}
+
+func TestStructValue(t *testing.T) {
+	tests := []compilerTest{
+		{
+			input: `
+			type human struct{age int}
+			var x human
+			var y human
+			x = y
+			`,
+			expected: `
+			.data
+			x: .dword 0
+			y: .dword 0
+			.text
+			li a0, 8
+			li a7, 9
+			ecall
+			la t0, x
+			sd a0, 0(t0)
+			li a0, 8
+			li a7, 9
+			ecall
+			la t0, y
+			sd a0, 0(t0)
+			la s1, x
+			la s10, y
+			ld s10, 0(s10)
+			ld t0, 0(s1)
+			addi a0, t0, 0
+			mv a1, s10
+			li a2, 8
+			call runtime.copy
+			runtime.copy:
+			beqz a2, runtime.copy.done
+			runtime.copy.loop:
+			ld a3, 0(a1)
+			sd a3, 0(a0)
+			addi a0, a0, 8
+			addi a1, a1, 8
+			addi a2, a2, -8
+			bnez a2, runtime.copy.loop
+			runtime.copy.done:
+			ret
+			`,
+		},
+		{
+			input: `
+			type human struct{age int}
+			func grow(h human) {
+				h.age = 1
+			}
+			var x human
+			grow(x)
+			`,
+			expected: `
+			.data
+			x: .dword 0
+			.text
+			li a0, 8
+			li a7, 9
+			ecall
+			la t0, x
+			sd a0, 0(t0)
+			addi sp, sp, -16
+			la s1, x
+			ld s1, 0(s1)
+			li a0, 8
+			li a7, 9
+			ecall
+			mv a1, s1
+			mv s1, a0
+			li a2, 8
+			call runtime.copy
+			sd s1, 8(sp)
+			ld a0, 8(sp)
+			call grow
+			addi sp, sp, 16
+			grow:
+			addi sp, sp, -16
+			sd a0, 8(sp)
+			sd ra, 16(sp)
+			addi sp, sp, -0
+			ld t0, 8(sp)
+			li t1, 1
+			sd t1, 0(t0)
+			addi sp, sp, 0
+			grow.epilogue:
+			ld ra, 16(sp)
+			addi sp, sp, 16
+			ret
+			runtime.copy:
+			beqz a2, runtime.copy.done
+			runtime.copy.loop:
+			ld a3, 0(a1)
+			sd a3, 0(a0)
+			addi a0, a0, 8
+			addi a1, a1, 8
+			addi a2, a2, -8
+			bnez a2, runtime.copy.loop
+			runtime.copy.done:
+			ret
+			`,
+		},
+		{
+			input: `
+			type point struct{x int; f float}
+			var a point
+			var b point
+			print a == b
+			`,
+			expected: `
+			.data
+			a: .dword 0
+			b: .dword 0
+			.text
+			li a0, 16
+			li a7, 9
+			ecall
+			la t0, a
+			sd a0, 0(t0)
+			li a0, 16
+			li a7, 9
+			ecall
+			la t0, b
+			sd a0, 0(t0)
+			la s1, a
+			ld s1, 0(s1)
+			la s10, b
+			ld s10, 0(s10)
+			ld t0, 0(s1)
+			ld t1, 0(s10)
+			bne t0, t1, .L1
+			fld ft0, 8(s1)
+			fld ft1, 8(s10)
+			feq.d t0, ft0, ft1
+			beqz t0, .L1
+			li s1, 1
+			b .L2
+			.L1:
+			li s1, 0
+			.L2:
+			mv a0, s1
+			li a7, 1
+			ecall
+			`,
+		},
+	}
+
+	runCompilerTests(t, tests)
+}
+
+func TestCompositeLiteral(t *testing.T) {
+	tests := []compilerTest{
+		{
+			input: `
+			type point struct{x int; y float}
+			print point{y: 1.5, x: 2}.y
+			`,
+			expected: `
+			.data
+			.L1: .double 1.5
+			.text
+			li a0, 16
+			li a7, 9
+			ecall
+			mv t0, a0
+			fld ft0, .L1, t1
+			fsd ft0, 8(t0)
+			li t1, 2
+			sd t1, 0(t0)
+			fld ft0, 8(t0)
+			fmv.d fa0, ft0
+			li a7, 3
+			ecall
+			`,
+		},
+	}
+
+	runCompilerTests(t, tests)
+}
+
+func TestMethod(t *testing.T) {
+	tests := []compilerTest{
+		{
+			input: `
+			type human struct{age int}
+			func (h *human) grow(years int) {
+				h.age = h.age + years
+			}
+			var bob human
+			bob.grow(2)
+			`,
+			expected: `
+			.data
+			bob: .dword 0
+			.text
+			li a0, 8
+			li a7, 9
+			ecall
+			la t0, bob
+			sd a0, 0(t0)
+			addi sp, sp, -32
+			la s1, bob
+			ld s1, 0(s1)
+			sd s1, 8(sp)
+			li t0, 2
+			sd t0, 16(sp)
+			ld a0, 8(sp)
+			ld a1, 16(sp)
+			call human.grow
+			addi sp, sp, 32
+			human.grow:
+			addi sp, sp, -32
+			sd a0, 8(sp)
+			sd a1, 16(sp)
+			sd ra, 32(sp)
+			addi sp, sp, -0
+			ld t0, 8(sp)
+			ld t1, 8(sp)
+			ld t1, 0(t1)
+			ld t2, 16(sp)
+			add t1, t1, t2
+			sd t1, 0(t0)
+			addi sp, sp, 0
+			human.grow.epilogue:
+			ld ra, 32(sp)
+			addi sp, sp, 32
+			ret
+			`,
+		},
+		{
+			input: `
+			type celsius int
+			func (c *celsius) warm() {
+				*c = *c + 1
+			}
+			var t celsius
+			t.warm()
+			`,
+			expected: `
+			.data
+			t: .dword 0
+			.text
+			addi sp, sp, -16
+			la s1, t
+			sd s1, 8(sp)
+			ld a0, 8(sp)
+			call celsius.warm
+			addi sp, sp, 16
+			celsius.warm:
+			addi sp, sp, -16
+			sd a0, 8(sp)
+			sd ra, 16(sp)
+			addi sp, sp, -0
+			ld t0, 8(sp)
+			ld t1, 8(sp)
+			ld t1, 0(t1)
+			li t2, 1
+			add t1, t1, t2
+			sd t1, 0(t0)
+			addi sp, sp, 0
+			celsius.warm.epilogue:
+			ld ra, 16(sp)
+			addi sp, sp, 16
+			ret
+			`,
+		},
+	}
+
+	runCompilerTests(t, tests)
+}
+
+func TestInterface(t *testing.T) {
+	tests := []compilerTest{
+		{
+			input: `
+			type namer interface{name() int}
+			type human struct{age int}
+			func (h *human) name() int {
+				return h.age
+			}
+			var s namer = new(human)
+			print s.name()
+			`,
+			expected: `
+			.data
+			s: .dword 0
+			itab.human.namer: .dword human.name
+			.text
+			la s1, s
+			li a0, 8
+			li a7, 9
+			ecall
+			mv t0, a0
+			li a0, 16
+			li a7, 9
+			ecall
+			la t1, itab.human.namer
+			sd t1, 0(a0)
+			sd t0, 8(a0)
+			mv t0, a0
+			sd t0, 0(s1)
+			addi sp, sp, -16
+			la s1, s
+			ld s1, 0(s1)
+			ld t0, 0(s1)
+			ld t0, 0(t0)
+			ld s1, 8(s1)
+			sd s1, 8(sp)
+			ld a0, 8(sp)
+			jalr t0
+			mv t0, a0
+			addi sp, sp, 16
+			mv a0, t0
+			li a7, 1
+			ecall
+			human.name:
+			addi sp, sp, -16
+			sd a0, 8(sp)
+			sd ra, 16(sp)
+			addi sp, sp, -0
+			ld t0, 8(sp)
+			ld t0, 0(t0)
+			mv a0, t0
+			addi sp, sp, 0
+			j human.name.epilogue
+			addi sp, sp, 0
+			human.name.epilogue:
+			ld ra, 16(sp)
+			addi sp, sp, 16
+			ret
+			`,
+		},
+		{
+			input: `
+			type shape interface{area() int; name() int}
+			type namer interface{name() int}
+			var s shape
+			var n namer = s
+			`,
+			expected: `
+			.data
+			s: .dword 0
+			n: .dword 0
+			.text
+			la s1, n
+			la s10, s
+			ld s10, 0(s10)
+			beqz s10, .L1
+			ld t0, 0(s10)
+			li a0, 8
+			li a7, 9
+			ecall
+			ld t1, 8(t0)
+			sd t1, 0(a0)
+			mv t0, a0
+			li a0, 16
+			li a7, 9
+			ecall
+			ld t1, 8(s10)
+			sd t0, 0(a0)
+			sd t1, 8(a0)
+			mv s10, a0
+			.L1:
+			sd s10, 0(s1)
+			`,
+		},
+	}
+
+	runCompilerTests(t, tests)
+}
+
+func TestFuncLiteral(t *testing.T) {
+	tests := []compilerTest{
+		{
+			input: `
+			func double() func(int) int {
+				return func(x int) int {
+					return x * 2
+				}
+			}
+			print double()(21)
+			`,
+			expected: `
+			.data
+			.text
+			addi sp, sp, -16
+			call double
+			mv t0, a0
+			li t1, 21
+			sd t1, 8(sp)
+			ld a0, 8(sp)
+			mv s11, t0
+			ld t0, 0(t0)
+			jalr t0
+			mv t0, a0
+			addi sp, sp, 16
+			mv a0, t0
+			li a7, 1
+			ecall
+			double:
+			addi sp, sp, -16
+			sd ra, 16(sp)
+			addi sp, sp, -0
+			li a0, 8
+			li a7, 9
+			ecall
+			mv t0, a0
+			la t1, func.lit1
+			sd t1, 0(t0)
+			mv a0, t0
+			addi sp, sp, 0
+			j double.epilogue
+			addi sp, sp, 0
+			double.epilogue:
+			ld ra, 16(sp)
+			addi sp, sp, 16
+			ret
+			func.lit1:
+			addi sp, sp, -32
+			sd a0, 8(sp)
+			sd ra, 32(sp)
+			sd s11, 16(sp)
+			addi sp, sp, -0
+			ld t0, 8(sp)
+			li t1, 2
+			mul t0, t0, t1
+			mv a0, t0
+			addi sp, sp, 0
+			j func.lit1.epilogue
+			addi sp, sp, 0
+			func.lit1.epilogue:
+			ld ra, 32(sp)
+			addi sp, sp, 32
+			ret
+			`,
+		},
+		{
+			input: `
+			func counter() func() int {
+				var n int
+				return func() int {
+					n = n + 1
+					return n
+				}
+			}
+			`,
+			expected: `
+			.data
+			.text
+			counter:
+			addi sp, sp, -16
+			sd ra, 16(sp)
+			addi sp, sp, -16
+			li a0, 8
+			li a7, 9
+			ecall
+			sd a0, 8(sp)
+			li a0, 16
+			li a7, 9
+			ecall
+			mv t0, a0
+			la t1, func.lit1
+			sd t1, 0(t0)
+			ld t1, 8(sp)
+			sd t1, 8(t0)
+			mv a0, t0
+			addi sp, sp, 16
+			j counter.epilogue
+			addi sp, sp, 16
+			counter.epilogue:
+			ld ra, 16(sp)
+			addi sp, sp, 16
+			ret
+			func.lit1:
+			addi sp, sp, -16
+			sd ra, 16(sp)
+			sd s11, 8(sp)
+			addi sp, sp, -0
+			ld t0, 8(sp)
+			ld t0, 8(t0)
+			ld t1, 8(sp)
+			ld t1, 8(t1)
+			ld t1, 0(t1)
+			li t2, 1
+			add t1, t1, t2
+			sd t1, 0(t0)
+			ld t0, 8(sp)
+			ld t0, 8(t0)
+			ld t0, 0(t0)
+			mv a0, t0
+			addi sp, sp, 0
+			j func.lit1.epilogue
+			addi sp, sp, 0
+			func.lit1.epilogue:
+			ld ra, 16(sp)
+			addi sp, sp, 16
+			ret
+			`,
+		},
+	}
+
+	runCompilerTests(t, tests)
+}
+
+func TestFuncStatement(t *testing.T) {
+	tests := []compilerTest{
+		{
+			input: `func greeter() {
+				print true
+			}`,
+			expected: `
+			.data
+			.text
+			greeter:
+			addi sp, sp, -16
+			sd ra, 16(sp)
+			addi sp, sp, -0
+			li t0, 1
+			mv a0, t0
+			li a7, 1
+			ecall
+			addi sp, sp, 0
+			greeter.epilogue:
+			ld ra, 16(sp)
+			addi sp, sp, 16
+			ret
+			`,
+		},
+		{
+			input: `func greeter(x int) {
+				print x
+			}`,
+			expected: `
+			.data
+			.text
+			greeter:
+			addi sp, sp, -16
+			sd a0, 8(sp)
+			sd ra, 16(sp)
+			addi sp, sp, -0
+			ld t0, 8(sp)
+			mv a0, t0
+			li a7, 1
+			ecall
+			addi sp, sp, 0
+			greeter.epilogue:
+			ld ra, 16(sp)
+			addi sp, sp, 16
+			ret
+			`,
+		},
+		{
+			input: `func greeter(x string) {
+				print x
+			}`,
+			expected: `
+			.data
+			.text
+			greeter:
+			addi sp, sp, -16
+			sd a0, 8(sp)
+			sd ra, 16(sp)
+			addi sp, sp, -0
+			ld t0, 8(sp)
+			mv a0, t0
+			li a7, 4
+			ecall
+			addi sp, sp, 0
+			greeter.epilogue:
+			ld ra, 16(sp)
+			addi sp, sp, 16
+			ret
+			`,
+		},
+		{
+			input: `func greeter(x string) string {
+				return x
+			}`,
+			expected: `
+			.data
+			.text
+			greeter:
+			addi sp, sp, -16
+			sd a0, 8(sp)
+			sd ra, 16(sp)
+			addi sp, sp, -0
+			ld t0, 8(sp)
+			mv a0, t0
+			addi sp, sp, 0
+			j greeter.epilogue
+			addi sp, sp, 0
+			greeter.epilogue:
+			ld ra, 16(sp)
+			addi sp, sp, 16
+			ret
+			`,
+		},
+		{
+			input: `
+			type human struct{name string; age int}
+			func greeter() human {
+				var x human
+				return x
+			}`,
+			expected: `
+			.data
+			.text
+			greeter:
+			addi sp, sp, -16
+			sd ra, 16(sp)
+			addi sp, sp, -16
+			li a0, 16
+			li a7, 9
+			ecall
+			sd a0, 8(sp)
+			ld t0, 8(sp)
+			li a0, 16
+			li a7, 9
+			ecall
+			mv a1, t0
+			mv t0, a0
+			li a2, 16
+			call runtime.copy
+			mv a0, t0
+			addi sp, sp, 16
+			j greeter.epilogue
+			addi sp, sp, 16
+			greeter.epilogue:
+			ld ra, 16(sp)
+			addi sp, sp, 16
+			ret
+			runtime.copy:
+			beqz a2, runtime.copy.done
+			runtime.copy.loop:
+			ld a3, 0(a1)
+			sd a3, 0(a0)
+			addi a0, a0, 8
+			addi a1, a1, 8
+			addi a2, a2, -8
+			bnez a2, runtime.copy.loop
+			runtime.copy.done:
+			ret
+			`,
+		},
+		{
+			input: `
+			type human struct{name string; age int}
+			func greeter(x human) int {
+				return x.age
+			}`,
+			expected: `
+			.data
+			.text
+			greeter:
+			addi sp, sp, -16
+			sd a0, 8(sp)
+			sd ra, 16(sp)
+			addi sp, sp, -0
+			ld t0, 8(sp)
+			ld t0, 8(t0)
+			mv a0, t0
+			addi sp, sp, 0
+			j greeter.epilogue
+			addi sp, sp, 0
+			greeter.epilogue:
+			ld ra, 16(sp)
+			addi sp, sp, 16
+			ret
+			`,
+		},
+		{
+			input: `
+			func incrementer(x int) int {
+				return x + 1
+			}
+
+			func decrementer(x int) int {
+				return x - 1
+			}
+
+			func getFunc(x int) func(int) int {
+				if x == 1 {
+					return incrementer
+				}
+				return decrementer
+			}
+			`,
+			expected: `
+			.data
+			incrementer.closure: .dword incrementer
+			decrementer.closure: .dword decrementer
+			.text
+			incrementer:
+			addi sp, sp, -16
+			sd a0, 8(sp)
+			sd ra, 16(sp)
+			addi sp, sp, -0
+			ld t0, 8(sp)
+			li t1, 1
+			add t0, t0, t1
+			mv a0, t0
+			addi sp, sp, 0
+			j incrementer.epilogue
+			addi sp, sp, 0
+			incrementer.epilogue:
+			ld ra, 16(sp)
+			addi sp, sp, 16
+			ret
+			decrementer:
+			addi sp, sp, -16
+			sd a0, 8(sp)
+			sd ra, 16(sp)
+			addi sp, sp, -0
+			ld t0, 8(sp)
+			li t1, 1
+			sub t0, t0, t1
+			mv a0, t0
+			addi sp, sp, 0
+			j decrementer.epilogue
+			addi sp, sp, 0
+			decrementer.epilogue:
+			ld ra, 16(sp)
+			addi sp, sp, 16
+			ret
+			getFunc:
+			addi sp, sp, -16
+			sd a0, 8(sp)
+			sd ra, 16(sp)
+			addi sp, sp, -0
+			ld t0, 8(sp)
+			li t1, 1
+			beq t0, t1, .L1
+			li t0, 0
+			b .L2
+			.L1:
+			li t0, 1
+			.L2:
+			beqz t0, .L3
+			addi sp, sp, -0
+			la t0, incrementer.closure
+			mv a0, t0
+			addi sp, sp, 0
+			j getFunc.epilogue
+			addi sp, sp, 0
+			b .L4
+			.L3:
+			.L4:
+			la t0, decrementer.closure
+			mv a0, t0
+			addi sp, sp, 0
+			j getFunc.epilogue
+			addi sp, sp, 0
+			getFunc.epilogue:
+			ld ra, 16(sp)
+			addi sp, sp, 16
+			ret`,
+		},
+		{
+			input: `
+			func sum(a, b, c, d, e, f, g, h, i int) int {
+				return i
+			}`,
+			expected: `
+			.data
+			.text
+			sum:
+			addi sp, sp, -80
+			sd a0, 8(sp)
+			sd a1, 16(sp)
+			sd a2, 24(sp)
+			sd a3, 32(sp)
+			sd a4, 40(sp)
+			sd a5, 48(sp)
+			sd a6, 56(sp)
+			sd a7, 64(sp)
+			ld t0, 88(sp)
+			sd t0, 72(sp)
+			sd ra, 80(sp)
+			addi sp, sp, -0
+			ld t0, 72(sp)
+			mv a0, t0
+			addi sp, sp, 0
+			j sum.epilogue
+			addi sp, sp, 0
+			sum.epilogue:
+			ld ra, 80(sp)
+			addi sp, sp, 80
+			ret`,
+		},
+	}
+
+	runCompilerTests(t, tests)
+}
+
+func TestCallExpression(t *testing.T) {
+	tests := []compilerTest{
+		{
+			input: `
+			func greeter(x string) {
+				print x
+			}
+
+			greeter("Hello Compiler World")`,
+			expected: `
+			.data
+			.L1: .string "Hello Compiler World"
+			.text
+			addi sp, sp, -16
+			la t0, .L1
+			sd t0, 8(sp)
+			ld a0, 8(sp)
+			call greeter
+			addi sp, sp, 16
+			greeter:
+			addi sp, sp, -16
+			sd a0, 8(sp)
+			sd ra, 16(sp)
+			addi sp, sp, -0
+			ld t0, 8(sp)
+			mv a0, t0
+			li a7, 4
+			ecall
+			addi sp, sp, 0
+			greeter.epilogue:
+			ld ra, 16(sp)
+			addi sp, sp, 16
+			ret
+			`,
+		},
+		{
+			input: `
+			type human struct{name string; age int}
+			func greeter(x human) {
+				print x.age
+			}
+
+			var h human
+			h.name = "Didac"
+			h.age = 0
+
+			greeter(h)`,
+			expected: `
+			.data
+			h: .dword 0
+			.L1: .string "Didac"
+			.text
+			li a0, 16
+			li a7, 9
+			ecall
+			la t0, h
+			sd a0, 0(t0)
+			la s1, h
+			ld s1, 0(s1)
+			la t0, .L1
+			sd t0, 0(s1)
+			la s1, h
+			ld s1, 0(s1)
+			li t0, 0
+			sd t0, 8(s1)
+			addi sp, sp, -16
+			la s1, h
+			ld s1, 0(s1)
+			li a0, 16
+			li a7, 9
+			ecall
+			mv a1, s1
+			mv s1, a0
+			li a2, 16
+			call runtime.copy
+			sd s1, 8(sp)
+			ld a0, 8(sp)
+			call greeter
+			addi sp, sp, 16
+			greeter:
+			addi sp, sp, -16
+			sd a0, 8(sp)
+			sd ra, 16(sp)
+			addi sp, sp, -0
+			ld t0, 8(sp)
+			ld t0, 8(t0)
+			mv a0, t0
+			li a7, 1
+			ecall
+			addi sp, sp, 0
+			greeter.epilogue:
+			ld ra, 16(sp)
+			addi sp, sp, 16
+			ret
+			runtime.copy:
+			beqz a2, runtime.copy.done
+			runtime.copy.loop:
+			ld a3, 0(a1)
+			sd a3, 0(a0)
+			addi a0, a0, 8
+			addi a1, a1, 8
+			addi a2, a2, -8
+			bnez a2, runtime.copy.loop
+			runtime.copy.done:
+			ret
+			`,
+		},
+		{
+			input: `
+			func incrementer(x int) int {
+				return x + 1
+			}
+
+			var x func(int) int
+			x = incrementer
+			`,
+			expected: `
+			.data
+			x: .dword 0
+			incrementer.closure: .dword incrementer
+			.text
+			la s1, x
+			la s10, incrementer.closure
+			sd s10, 0(s1)
+			incrementer:
+			addi sp, sp, -16
+			sd a0, 8(sp)
+			sd ra, 16(sp)
+			addi sp, sp, -0
+			ld t0, 8(sp)
+			li t1, 1
+			add t0, t0, t1
+			mv a0, t0
+			addi sp, sp, 0
+			j incrementer.epilogue
+			addi sp, sp, 0
+			incrementer.epilogue:
+			ld ra, 16(sp)
+			addi sp, sp, 16
+			ret`,
+		},
+		{
+			input: `
+			var x func() func(int)
+			var y func(int)
+			func test() func(int)
+			func test2(int)
+
+			func test() func(int) {
+				return test2
+			}
+
+			func test2(x int) {
+				print x
+			}
+
+			x = test
+			y = x()
+			y(10)
+			`,
+			expected: `
+			.data
+			x: .dword 0
+			y: .dword 0
+			test2.closure: .dword test2
+			test.closure: .dword test
+			.text
+			la s1, x
+			la s10, test.closure
+			sd s10, 0(s1)
+			la s1, y
+			la s10, x
+			ld s10, 0(s10)
+			mv s11, s10
+			ld s10, 0(s10)
+			jalr s10
+			mv t0, a0
+			sd t0, 0(s1)
+			addi sp, sp, -16
+			la s1, y
+			ld s1, 0(s1)
+			li t0, 10
+			sd t0, 8(sp)
+			ld a0, 8(sp)
+			mv s11, s1
+			ld s1, 0(s1)
+			jalr s1
+			addi sp, sp, 16
+			test:
+			addi sp, sp, -16
+			sd ra, 16(sp)
+			addi sp, sp, -0
+			la t0, test2.closure
+			mv a0, t0
+			addi sp, sp, 0
+			j test.epilogue
+			addi sp, sp, 0
+			test.epilogue:
+			ld ra, 16(sp)
+			addi sp, sp, 16
+			ret
+			test2:
+			addi sp, sp, -16
+			sd a0, 8(sp)
+			sd ra, 16(sp)
+			addi sp, sp, -0
+			ld t0, 8(sp)
+			mv a0, t0
+			li a7, 1
+			ecall
+			addi sp, sp, 0
+			test2.epilogue:
+			ld ra, 16(sp)
+			addi sp, sp, 16
+			ret
+			`,
+		}, {
+			input: `
+			func add(x int, y float, z int) float {
+				return y
+			}
+
+			var r float
+			r = 1.0 + add(1, 2.0, 3)
+			`,
+			expected: `
+			.data
+			r: .double 0
+			.L1: .double 1
+			.L2: .double 2
+			.text
+			la s1, r
+			fld ft0, .L1, t0
+			addi sp, sp, -16
+			fsd ft0, 8(sp)
+			addi sp, sp, -32
+			li t0, 1
+			sd t0, 8(sp)
+			fld ft1, .L2, t0
+			fsd ft1, 16(sp)
+			li t0, 3
+			sd t0, 24(sp)
+			ld a0, 8(sp)
+			fld fa0, 16(sp)
+			ld a1, 24(sp)
+			call add
+			fmv.d ft1, fa0
+			addi sp, sp, 32
+			fld ft0, 8(sp)
+			addi sp, sp, 16
+			fadd.d ft0, ft0, ft1
+			fsd ft0, 0(s1)
+			add:
+			addi sp, sp, -32
+			sd a0, 8(sp)
+			fsd fa0, 16(sp)
+			sd a1, 24(sp)
+			sd ra, 32(sp)
+			addi sp, sp, -0
+			fld ft0, 16(sp)
+			fmv.d fa0, ft0
+			addi sp, sp, 0
+			j add.epilogue
+			addi sp, sp, 0
+			add.epilogue:
+			ld ra, 32(sp)
+			addi sp, sp, 32
+			ret
+			`,
+		},
+	}
+
+	runCompilerTests(t, tests)
+}
+
+func TestMultipleResults(t *testing.T) {
+	tests := []compilerTest{
+		{
+			input: `
+			func divmod(a int, b int) (int, int) {
+				return a / b, a - a / b * b
+			}
+
+			var q, r int = divmod(7, 2)
+			`,
+			expected: `
+			.data
+			q: .dword 0
+			r: .dword 0
+			.text
+			la s1, q
+			la s10, r
+			addi sp, sp, -32
+			li t0, 7
+			sd t0, 8(sp)
+			li t0, 2
+			sd t0, 16(sp)
+			ld a0, 8(sp)
+			ld a1, 16(sp)
+			call divmod
+			mv t0, a0
+			mv t1, a1
+			addi sp, sp, 32
+			sd t0, 0(s1)
+			sd t1, 0(s10)
+			divmod:
+			addi sp, sp, -32
+			sd a0, 8(sp)
+			sd a1, 16(sp)
+			sd ra, 32(sp)
+			addi sp, sp, -0
+			ld t0, 8(sp)
+			ld t1, 16(sp)
+			div t0, t0, t1
+			ld t1, 8(sp)
+			ld t2, 8(sp)
+			ld t3, 16(sp)
+			div t2, t2, t3
+			ld t3, 16(sp)
+			mul t2, t2, t3
+			sub t1, t1, t2
+			mv a0, t0
+			mv a1, t1
+			addi sp, sp, 0
+			j divmod.epilogue
+			addi sp, sp, 0
+			divmod.epilogue:
+			ld ra, 32(sp)
+			addi sp, sp, 32
+			ret
+			`,
+		},
+		{
+			input: `
+			func three() (int, int, int) {
+				return 1, 2, 3
+			}
+
+			var x, y, z int
+			x, y, z = three()
+			`,
+			expected: `
+			.data
+			x: .dword 0
+			y: .dword 0
+			z: .dword 0
+			.text
+			la s1, x
+			la s10, y
+			la s2, z
+			addi sp, sp, -16
+			addi sp, sp, -16
+			call three
+			mv t0, a0
+			mv t1, a1
+			ld t2, 8(sp)
+			sd t2, 24(sp)
+			addi sp, sp, 16
+			sd t0, 0(s1)
+			sd t1, 0(s10)
+			ld t0, 8(sp)
+			sd t0, 0(s2)
+			addi sp, sp, 16
+			three:
+			addi sp, sp, -16
+			sd ra, 16(sp)
+			addi sp, sp, -0
+			li t0, 1
+			li t1, 2
+			li t2, 3
+			sd t2, 24(sp)
+			mv a0, t0
+			mv a1, t1
+			addi sp, sp, 0
+			j three.epilogue
+			addi sp, sp, 0
+			three.epilogue:
+			ld ra, 16(sp)
+			addi sp, sp, 16
+			ret
+			`,
+		},
+		{
+			// Only the results in the result registers are held in
+			// registers, the rest are spilled to the stack.
+			input: `
+			func ints() (int, int, int, int, int, int, int, int) {
+				return 1, 2, 3, 4, 5, 6, 7, 8
+			}
+			func floats() (float, float, float, float, float, float, float, float) {
+				return 1.0, 2.0, 3.0, 4.0, 5.0, 6.0, 7.0, 8.0
+			}
+			func relay() (float, float, float, float, float, float, float, float) {
+				return floats()
+			}
+			var a, b, c, d, e, f, g, h int = ints()
+			var p, q, r, s, t, u, v, w float = relay()
+			`,
+			expected: `
+			.data
+			.L1: .double 1
+			.L2: .double 2
+			.L3: .double 3
+			.L4: .double 4
+			.L5: .double 5
+			.L6: .double 6
+			.L7: .double 7
+			.L8: .double 8
+			a: .dword 0
+			b: .dword 0
+			c: .dword 0
+			d: .dword 0
+			e: .dword 0
+			f: .dword 0
+			g: .dword 0
+			h: .dword 0
+			p: .double 0
+			q: .double 0
+			r: .double 0
+			s: .double 0
+			t: .double 0
+			u: .double 0
+			v: .double 0
+			w: .double 0
+			.text
+			la s1, a
+			la s10, b
+			la s2, c
+			la s3, d
+			la s4, e
+			la s5, f
+			la s6, g
+			la s7, h
+			addi sp, sp, -64
+			addi sp, sp, -64
+			call ints
+			mv t0, a0
+			mv t1, a1
+			ld t2, 8(sp)
+			sd t2, 72(sp)
+			ld t2, 16(sp)
+			sd t2, 80(sp)
+			ld t2, 24(sp)
+			sd t2, 88(sp)
+			ld t2, 32(sp)
+			sd t2, 96(sp)
+			ld t2, 40(sp)
+			sd t2, 104(sp)
+			ld t2, 48(sp)
+			sd t2, 112(sp)
+			addi sp, sp, 64
+			sd t0, 0(s1)
+			sd t1, 0(s10)
+			ld t0, 8(sp)
+			sd t0, 0(s2)
+			ld t0, 16(sp)
+			sd t0, 0(s3)
+			ld t0, 24(sp)
+			sd t0, 0(s4)
+			ld t0, 32(sp)
+			sd t0, 0(s5)
+			ld t0, 40(sp)
+			sd t0, 0(s6)
+			ld t0, 48(sp)
+			sd t0, 0(s7)
+			addi sp, sp, 64
+			la s1, p
+			la s10, q
+			la s2, r
+			la s3, s
+			la s4, t
+			la s5, u
+			la s6, v
+			la s7, w
+			addi sp, sp, -64
+			addi sp, sp, -64
+			call relay
+			fmv.d ft0, fa0
+			fmv.d ft1, fa1
+			fld ft10, 8(sp)
+			fsd ft10, 72(sp)
+			fld ft10, 16(sp)
+			fsd ft10, 80(sp)
+			fld ft10, 24(sp)
+			fsd ft10, 88(sp)
+			fld ft10, 32(sp)
+			fsd ft10, 96(sp)
+			fld ft10, 40(sp)
+			fsd ft10, 104(sp)
+			fld ft10, 48(sp)
+			fsd ft10, 112(sp)
+			addi sp, sp, 64
+			fsd ft0, 0(s1)
+			fsd ft1, 0(s10)
+			fld ft0, 8(sp)
+			fsd ft0, 0(s2)
+			fld ft0, 16(sp)
+			fsd ft0, 0(s3)
+			fld ft0, 24(sp)
+			fsd ft0, 0(s4)
+			fld ft0, 32(sp)
+			fsd ft0, 0(s5)
+			fld ft0, 40(sp)
+			fsd ft0, 0(s6)
+			fld ft0, 48(sp)
+			fsd ft0, 0(s7)
+			addi sp, sp, 64
+			ints:
+			addi sp, sp, -16
+			sd ra, 16(sp)
+			addi sp, sp, -0
+			li t0, 1
+			li t1, 2
+			li t2, 3
+			sd t2, 24(sp)
+			li t2, 4
+			sd t2, 32(sp)
+			li t2, 5
+			sd t2, 40(sp)
+			li t2, 6
+			sd t2, 48(sp)
+			li t2, 7
+			sd t2, 56(sp)
+			li t2, 8
+			sd t2, 64(sp)
+			mv a0, t0
+			mv a1, t1
+			addi sp, sp, 0
+			j ints.epilogue
+			addi sp, sp, 0
+			ints.epilogue:
+			ld ra, 16(sp)
+			addi sp, sp, 16
+			ret
+			floats:
+			addi sp, sp, -16
+			sd ra, 16(sp)
+			addi sp, sp, -0
+			fld ft0, .L1, t0
+			fld ft1, .L2, t0
+			fld ft10, .L3, t0
+			fsd ft10, 24(sp)
+			fld ft10, .L4, t0
+			fsd ft10, 32(sp)
+			fld ft10, .L5, t0
+			fsd ft10, 40(sp)
+			fld ft10, .L6, t0
+			fsd ft10, 48(sp)
+			fld ft10, .L7, t0
+			fsd ft10, 56(sp)
+			fld ft10, .L8, t0
+			fsd ft10, 64(sp)
+			fmv.d fa0, ft0
+			fmv.d fa1, ft1
+			addi sp, sp, 0
+			j floats.epilogue
+			addi sp, sp, 0
+			floats.epilogue:
+			ld ra, 16(sp)
+			addi sp, sp, 16
+			ret
+			relay:
+			addi sp, sp, -16
+			sd ra, 16(sp)
+			addi sp, sp, -0
+			addi sp, sp, -64
+			addi sp, sp, -64
+			call floats
+			fmv.d ft0, fa0
+			fmv.d ft1, fa1
+			fld ft10, 8(sp)
+			fsd ft10, 72(sp)
+			fld ft10, 16(sp)
+			fsd ft10, 80(sp)
+			fld ft10, 24(sp)
+			fsd ft10, 88(sp)
+			fld ft10, 32(sp)
+			fsd ft10, 96(sp)
+			fld ft10, 40(sp)
+			fsd ft10, 104(sp)
+			fld ft10, 48(sp)
+			fsd ft10, 112(sp)
+			addi sp, sp, 64
+			fld ft10, 8(sp)
+			fsd ft10, 88(sp)
+			fld ft10, 16(sp)
+			fsd ft10, 96(sp)
+			fld ft10, 24(sp)
+			fsd ft10, 104(sp)
+			fld ft10, 32(sp)
+			fsd ft10, 112(sp)
+			fld ft10, 40(sp)
+			fsd ft10, 120(sp)
+			fld ft10, 48(sp)
+			fsd ft10, 128(sp)
+			fmv.d fa0, ft0
+			fmv.d fa1, ft1
+			addi sp, sp, 64
+			addi sp, sp, 0
+			j relay.epilogue
+			addi sp, sp, 0
+			relay.epilogue:
+			ld ra, 16(sp)
+			addi sp, sp, 16
+			ret
+			`,
+		},
+	}
+
+	runCompilerTests(t, tests)
+}
+
+func TestArray(t *testing.T) {
+	tests := []compilerTest{
+		{
+			input: `
+			var a [3]int
+			var i int
+			a[i] = a[2]
+			{
+				var b [2]float
+				b[1] = 2.5
+				print b[i]
+			}
+			`,
+			expected: `
+			.data
+			.align 3
+			a: .space 24
+			i: .dword 0
+			runtime.panicindex.msg0: .string "panic: runtime error: index out of range ["
+			runtime.panicindex.msg1: .string "] with length "
+			runtime.panicindex.msg2: .string "\n"
+			.L2: .double 2.5
+			.text
+			la s1, a
+			la s10, i
+			ld s10, 0(s10)
+			li t0, 3
+			bltu s10, t0, .L1
+			mv a0, s10
+			mv a1, t0
+			j runtime.panicindex
+			.L1:
+			li t0, 8
+			mul s10, s10, t0
+			add s1, s1, s10
+			la s10, a
+			addi s10, s10, 16
+			ld s10, 0(s10)
+			sd s10, 0(s1)
+			addi sp, sp, -16
+			addi t0, sp, 8
+			addi t0, t0, 8
+			fld ft0, .L2, t1
+			fsd ft0, 0(t0)
+			addi t0, sp, 8
+			la s1, i
+			ld s1, 0(s1)
+			li t1, 2
+			bltu s1, t1, .L3
+			mv a0, s1
+			mv a1, t1
+			j runtime.panicindex
+			.L3:
+			li t1, 8
+			mul s1, s1, t1
+			add t0, t0, s1
+			fld ft0, 0(t0)
+			fmv.d fa0, ft0
+			li a7, 3
+			ecall
+			addi sp, sp, 16
+			runtime.panicindex:
+			mv t0, a0
+			mv t1, a1
+			la a0, runtime.panicindex.msg0
+			li a7, 4
+			ecall
+			mv a0, t0
+			li a7, 1
+			ecall
+			la a0, runtime.panicindex.msg1
+			li a7, 4
+			ecall
+			mv a0, t1
+			li a7, 1
+			ecall
+			la a0, runtime.panicindex.msg2
+			li a7, 4
+			ecall
+			li a0, 2
+			li a7, 93
+			ecall
+			`,
+		},
+		{
+			input: `
+			var a, b [2]int
+			a = b
+			`,
+			expected: `
+			.data
+			.align 3
+			a: .space 16
+			.align 3
+			b: .space 16
+			.text
+			la s1, a
+			la s10, b
+			addi a0, s1, 0
+			mv a1, s10
+			li a2, 16
+			call runtime.copy
+			runtime.copy:
+			beqz a2, runtime.copy.done
+			runtime.copy.loop:
+			ld a3, 0(a1)
+			sd a3, 0(a0)
+			addi a0, a0, 8
+			addi a1, a1, 8
+			addi a2, a2, -8
+			bnez a2, runtime.copy.loop
+			runtime.copy.done:
+			ret
+			`,
+		},
+	}
+
+	runCompilerTests(t, tests)
+}
+
+func TestSlice(t *testing.T) {
+	tests := []compilerTest{
+		{
+			input: `
+			var s []int = make([]int, 2)
+			s = append(s, 3)
+			print len(s)
+			`,
+			expected: `
+			.data
+			s: .dword 0
+			runtime.makeslice.msg0: .string "panic: runtime error: makeslice: len out of range\n"
+			runtime.makeslice.msg1: .string "panic: runtime error: makeslice: cap out of range\n"
+			.text
+			li a0, 24
+			li a7, 9
+			ecall
+			la t0, s
+			sd a0, 0(t0)
+			la s1, s
+			li t0, 2
+			mv a0, t0
+			mv a1, t0
+			li a2, 8
+			call runtime.makeslice
+			mv t0, a0
+			sd t0, 0(s1)
+			la s1, s
+			la s10, s
+			ld s10, 0(s10)
+			li t0, 3
+			mv a0, s10
+			li a1, 1
+			li a2, 8
+			call runtime.append
+			mv s10, a0
+			ld t1, 8(s10)
+			addi t1, t1, -1
+			li t2, 8
+			mul t1, t1, t2
+			ld t2, 0(s10)
+			add t1, t1, t2
+			sd t0, 0(t1)
+			sd s10, 0(s1)
+			la s1, s
+			ld s1, 0(s1)
+			ld s1, 8(s1)
+			mv a0, s1
+			li a7, 1
+			ecall
+			runtime.append:
+			ld a3, 8(a0)
+			ld a4, 16(a0)
+			ld a5, 0(a0)
+			add a3, a3, a1
+			ble a3, a4, runtime.append.header
+			slli a4, a4, 1
+			bge a4, a3, runtime.append.grow
+			mv a4, a3
+			runtime.append.grow:
+			mul a0, a4, a2
+			li a7, 9
+			ecall
+			sub a6, a3, a1
+			mul a6, a6, a2
+			mv a1, a0
+			runtime.append.copy:
+			beqz a6, runtime.append.copied
+			ld a7, 0(a5)
+			sd a7, 0(a1)
+			addi a5, a5, 8
+			addi a1, a1, 8
+			addi a6, a6, -8
+			j runtime.append.copy
+			runtime.append.copied:
+			mv a5, a0
+			runtime.append.header:
+			li a0, 24
+			li a7, 9
+			ecall
+			sd a5, 0(a0)
+			sd a3, 8(a0)
+			sd a4, 16(a0)
+			ret
+			runtime.makeslice:
+			bltz a0, runtime.makeslice.len
+			blt a1, a0, runtime.makeslice.cap
+			mv a3, a0
+			mv a4, a1
+			mul a0, a1, a2
+			li a7, 9
+			ecall
+			mv a5, a0
+			li a0, 24
+			li a7, 9
+			ecall
+			sd a5, 0(a0)
+			sd a3, 8(a0)
+			sd a4, 16(a0)
+			ret
+			runtime.makeslice.len:
+			la a0, runtime.makeslice.msg0
+			j runtime.makeslice.panic
+			runtime.makeslice.cap:
+			la a0, runtime.makeslice.msg1
+			runtime.makeslice.panic:
+			li a7, 4
+			ecall
+			li a0, 2
+			li a7, 93
+			ecall
+			`,
+		},
+		{
+			input: `
+			{
+				var s []float
+				var t []float = s[1:]
+				print t[0]
+			}
+			`,
+			expected: `
+			.data
+			runtime.panicslice.msg0: .string "panic: runtime error: slice bounds out of range ["
+			runtime.panicslice.msg1: .string ":"
+			runtime.panicslice.msg2: .string "]"
+			runtime.panicslice.msg3: .string "\n"
+			runtime.panicslice.cap: .string " with capacity "
+			runtime.panicslice.len: .string " with length "
+			runtime.panicindex.msg0: .string "panic: runtime error: index out of range ["
+			runtime.panicindex.msg1: .string "] with length "
+			runtime.panicindex.msg2: .string "\n"
+			.text
+			addi sp, sp, -16
+			li a0, 24
+			li a7, 9
+			ecall
+			sd a0, 8(sp)
+			li a0, 24
+			li a7, 9
+			ecall
+			sd a0, 16(sp)
+			ld t0, 8(sp)
+			li t1, 1
+			mv a0, t0
+			mv a1, t1
+			ld a2, 8(t0)
+			li a3, 8
+			call runtime.slice
+			mv t0, a0
+			sd t0, 16(sp)
+			ld t0, 16(sp)
+			li t1, 0
+			ld t2, 8(t0)
+			ld t0, 0(t0)
+			bltu t1, t2, .L1
+			mv a0, t1
+			mv a1, t2
+			j runtime.panicindex
+			.L1:
+			li t2, 8
+			mul t1, t1, t2
+			add t0, t0, t1
+			fld ft0, 0(t0)
+			fmv.d fa0, ft0
+			li a7, 3
+			ecall
+			addi sp, sp, 16
+			runtime.panicindex:
+			mv t0, a0
+			mv t1, a1
+			la a0, runtime.panicindex.msg0
+			li a7, 4
+			ecall
+			mv a0, t0
+			li a7, 1
+			ecall
+			la a0, runtime.panicindex.msg1
+			li a7, 4
+			ecall
+			mv a0, t1
+			li a7, 1
+			ecall
+			la a0, runtime.panicindex.msg2
+			li a7, 4
+			ecall
+			li a0, 2
+			li a7, 93
+			ecall
+			runtime.panicslice:
+			mv t0, a0
+			mv t1, a1
+			mv t2, a2
+			mv t3, a3
+			la a0, runtime.panicslice.msg0
+			li a7, 4
+			ecall
+			bgeu t2, t1, runtime.panicslice.low
+			la a0, runtime.panicslice.msg1
+			li a7, 4
+			ecall
+			mv a0, t1
+			li a7, 1
+			ecall
+			la a0, runtime.panicslice.msg2
+			li a7, 4
+			ecall
+			bltz t1, runtime.panicslice.exit
+			mv a0, t3
+			li a7, 4
+			ecall
+			mv a0, t2
+			li a7, 1
+			ecall
+			j runtime.panicslice.exit
+			runtime.panicslice.low:
+			mv a0, t0
+			li a7, 1
+			ecall
+			la a0, runtime.panicslice.msg1
+			li a7, 4
+			ecall
+			bltz t0, runtime.panicslice.close
+			mv a0, t1
+			li a7, 1
+			ecall
+			runtime.panicslice.close:
+			la a0, runtime.panicslice.msg2
+			li a7, 4
+			ecall
+			runtime.panicslice.exit:
+			la a0, runtime.panicslice.msg3
+			li a7, 4
+			ecall
+			li a0, 2
+			li a7, 93
+			ecall
+			runtime.slice:
+			ld a4, 16(a0)
+			bltu a4, a2, runtime.slice.panic
+			bltu a2, a1, runtime.slice.panic
+			ld a5, 0(a0)
+			mul a6, a1, a3
+			add a5, a5, a6
+			sub a2, a2, a1
+			sub a4, a4, a1
+			li a0, 24
+			li a7, 9
+			ecall
+			sd a5, 0(a0)
+			sd a2, 8(a0)
+			sd a4, 16(a0)
+			ret
+			runtime.slice.panic:
+			mv a0, a1
+			mv a1, a2
+			mv a2, a4
+			la a3, runtime.panicslice.cap
+			j runtime.panicslice
+			`,
+		},
+		{
+			// Every element of a made slice is given its own struct.
+			input: `
+			type pt struct{x int}
+			var ps []pt = make([]pt, 1)
+			`,
+			expected: `
+			.data
+			ps: .dword 0
+			runtime.makeslice.msg0: .string "panic: runtime error: makeslice: len out of range\n"
+			runtime.makeslice.msg1: .string "panic: runtime error: makeslice: cap out of range\n"
+			.text
+			li a0, 24
+			li a7, 9
+			ecall
+			la t0, ps
+			sd a0, 0(t0)
+			la s1, ps
+			li t0, 1
+			mv a0, t0
+			mv a1, t0
+			li a2, 8
+			call runtime.makeslice
+			mv t0, a0
+			li t1, 8
+			ld t2, 16(t0)
+			mul t2, t2, t1
+			mul t1, t1, zero
+			ld a0, 0(t0)
+			add t1, t1, a0
+			add t2, t2, a0
+			.L1:
+			bgeu t1, t2, .L2
+			li a0, 8
+			li a7, 9
+			ecall
+			sd a0, 0(t1)
+			addi t1, t1, 8
+			j .L1
+			.L2:
+			sd t0, 0(s1)
+			runtime.makeslice:
+			bltz a0, runtime.makeslice.len
+			blt a1, a0, runtime.makeslice.cap
+			mv a3, a0
+			mv a4, a1
+			mul a0, a1, a2
+			li a7, 9
+			ecall
+			mv a5, a0
+			li a0, 24
+			li a7, 9
+			ecall
+			sd a5, 0(a0)
+			sd a3, 8(a0)
+			sd a4, 16(a0)
+			ret
+			runtime.makeslice.len:
+			la a0, runtime.makeslice.msg0
+			j runtime.makeslice.panic
+			runtime.makeslice.cap:
+			la a0, runtime.makeslice.msg1
+			runtime.makeslice.panic:
+			li a7, 4
+			ecall
+			li a0, 2
+			li a7, 93
+			ecall
+			`,
+		},
+		{
+			// Slicing a string creates a new string of the bytes.
+			input: `
+			print "abc"[1:]
+			`,
+			expected: `
+			.data
+			.L1: .string "abc"
+			runtime.panicslice.msg0: .string "panic: runtime error: slice bounds out of range ["
+			runtime.panicslice.msg1: .string ":"
+			runtime.panicslice.msg2: .string "]"
+			runtime.panicslice.msg3: .string "\n"
+			runtime.panicslice.cap: .string " with capacity "
+			runtime.panicslice.len: .string " with length "
+			.text
+			la t0, .L1
+			li t1, 1
+			mv a0, t0
+			call runtime.strlen
+			mv t2, a0
+			mv a0, t0
+			mv a1, t1
+			mv a2, t2
+			call runtime.slicestring
+			mv t0, a0
+			mv a0, t0
+			li a7, 4
+			ecall
+			runtime.panicslice:
+			mv t0, a0
+			mv t1, a1
+			mv t2, a2
+			mv t3, a3
+			la a0, runtime.panicslice.msg0
+			li a7, 4
+			ecall
+			bgeu t2, t1, runtime.panicslice.low
+			la a0, runtime.panicslice.msg1
+			li a7, 4
+			ecall
+			mv a0, t1
+			li a7, 1
+			ecall
+			la a0, runtime.panicslice.msg2
+			li a7, 4
+			ecall
+			bltz t1, runtime.panicslice.exit
+			mv a0, t3
+			li a7, 4
+			ecall
+			mv a0, t2
+			li a7, 1
+			ecall
+			j runtime.panicslice.exit
+			runtime.panicslice.low:
+			mv a0, t0
+			li a7, 1
+			ecall
+			la a0, runtime.panicslice.msg1
+			li a7, 4
+			ecall
+			bltz t0, runtime.panicslice.close
+			mv a0, t1
+			li a7, 1
+			ecall
+			runtime.panicslice.close:
+			la a0, runtime.panicslice.msg2
+			li a7, 4
+			ecall
+			runtime.panicslice.exit:
+			la a0, runtime.panicslice.msg3
+			li a7, 4
+			ecall
+			li a0, 2
+			li a7, 93
+			ecall
+			runtime.slicestring:
+			mv a4, a0
+			li a5, 0
+			beqz a4, runtime.slicestring.check
+			runtime.slicestring.len:
+			add a6, a4, a5
+			lbu a6, 0(a6)
+			beqz a6, runtime.slicestring.check
+			addi a5, a5, 1
+			j runtime.slicestring.len
+			runtime.slicestring.check:
+			bltu a5, a2, runtime.slicestring.panic
+			bltu a2, a1, runtime.slicestring.panic
+			sub a3, a2, a1
+			addi a0, a3, 8
+			andi a0, a0, -8
+			li a7, 9
+			ecall
+			add a4, a4, a1
+			mv a6, a0
+			runtime.slicestring.copy:
+			beqz a3, runtime.slicestring.done
+			lbu a7, 0(a4)
+			sb a7, 0(a6)
+			addi a4, a4, 1
+			addi a6, a6, 1
+			addi a3, a3, -1
+			j runtime.slicestring.copy
+			runtime.slicestring.done:
+			sb zero, 0(a6)
+			ret
+			runtime.slicestring.panic:
+			mv a0, a1
+			mv a1, a2
+			mv a2, a5
+			la a3, runtime.panicslice.len
+			j runtime.panicslice
+			runtime.strlen:
+			mv a1, a0
+			li a0, 0
+			beqz a1, runtime.strlen.done
+			runtime.strlen.loop:
+			lbu a2, 0(a1)
+			beqz a2, runtime.strlen.done
+			addi a0, a0, 1
+			addi a1, a1, 1
+			j runtime.strlen.loop
+			runtime.strlen.done:
+			ret
+			`,
+		},
+	}
+
+	runCompilerTests(t, tests)
+}
+
+func TestString(t *testing.T) {
+	tests := []compilerTest{
+		{
+			input: `var s string
+			print len(s)`,
+			expected: `
+			.data
+			s: .dword 0
+			.text
+			la s1, s
+			ld s1, 0(s1)
+			mv a0, s1
+			call runtime.strlen
+			mv s1, a0
+			mv a0, s1
+			li a7, 1
+			ecall
+			runtime.strlen:
+			mv a1, a0
+			li a0, 0
+			beqz a1, runtime.strlen.done
+			runtime.strlen.loop:
+			lbu a2, 0(a1)
+			beqz a2, runtime.strlen.done
+			addi a0, a0, 1
+			addi a1, a1, 1
+			j runtime.strlen.loop
+			runtime.strlen.done:
+			ret
+			`,
+		},
+		{
+			input: `var s string
+			print s[1]`,
+			expected: `
+			.data
+			s: .dword 0
+			runtime.panicindex.msg0: .string "panic: runtime error: index out of range ["
+			runtime.panicindex.msg1: .string "] with length "
+			runtime.panicindex.msg2: .string "\n"
+			.text
+			la s1, s
+			ld s1, 0(s1)
+			li t0, 1
+			mv a0, s1
+			call runtime.strlen
+			mv t1, a0
+			bltu t0, t1, .L1
+			mv a0, t0
+			mv a1, t1
+			j runtime.panicindex
+			.L1:
+			li t1, 1
+			mul t0, t0, t1
+			add s1, s1, t0
+			lbu s1, 0(s1)
+			mv a0, s1
+			li a7, 11
+			ecall
+			runtime.panicindex:
+			mv t0, a0
+			mv t1, a1
+			la a0, runtime.panicindex.msg0
+			li a7, 4
+			ecall
+			mv a0, t0
+			li a7, 1
+			ecall
+			la a0, runtime.panicindex.msg1
+			li a7, 4
+			ecall
+			mv a0, t1
+			li a7, 1
+			ecall
+			la a0, runtime.panicindex.msg2
+			li a7, 4
+			ecall
+			li a0, 2
+			li a7, 93
+			ecall
+			runtime.strlen:
+			mv a1, a0
+			li a0, 0
+			beqz a1, runtime.strlen.done
+			runtime.strlen.loop:
+			lbu a2, 0(a1)
+			beqz a2, runtime.strlen.done
+			addi a0, a0, 1
+			addi a1, a1, 1
+			j runtime.strlen.loop
+			runtime.strlen.done:
+			ret
+			`,
+		},
+	}
+
+	runCompilerTests(t, tests)
+}
+
+func TestConversion(t *testing.T) {
+	tests := []compilerTest{
+		{
+			input: "float(2)",
+			expected: `
+			.data
+			.L1: .double 2
+			.text
+			fld ft0, .L1, t0
+			`,
+		},
+		{
+			input: `
+			var i int = 2
+			float(i)`,
+			expected: `
+			.data
+			i: .dword 0
+			.text
+			la s1, i
+			li t0, 2
+			sd t0, 0(s1)
+			la s1, i
+			ld s1, 0(s1)
+			fcvt.d.l ft0, s1
+			`,
+		},
+		{
+			input: `
+			var f float = 2.5
+			int(f)`,
+			expected: `
+			.data
+			f: .double 0
+			.L1: .double 2.5
+			.text
+			la s1, f
+			fld ft0, .L1, t0
+			fsd ft0, 0(s1)
+			la s1, f
+			fld ft0, 0(s1)
+			fcvt.l.d t0, ft0, rtz
+			`,
+		},
+		{
+			input: `
+			type celsius float
+			var c celsius = 1.5
+			float(c) + 1`,
+			expected: `
+			.data
+			c: .double 0
+			.L1: .double 1.5
+			.L2: .double 1
+			.text
+			la s1, c
+			fld ft0, .L1, t0
+			fsd ft0, 0(s1)
+			la s1, c
+			fld ft0, 0(s1)
+			fld ft1, .L2, t0
+			fadd.d ft0, ft0, ft1
+			`,
+		},
+		{
+			input: `
+			var i int = 321
+			byte(i)`,
+			expected: `
+			.data
+			i: .dword 0
+			.text
+			la s1, i
+			li t0, 321
+			sd t0, 0(s1)
+			la s1, i
+			ld s1, 0(s1)
+			andi s1, s1, 255
+			`,
+		},
+		{
+			input: `string("a"[0])`,
+			expected: `
+			.data
+			.L1: .string "a"
+			runtime.panicindex.msg0: .string "panic: runtime error: index out of range ["
+			runtime.panicindex.msg1: .string "] with length "
+			runtime.panicindex.msg2: .string "\n"
+			.text
+			la t0, .L1
+			li t1, 0
+			mv a0, t0
+			call runtime.strlen
+			mv t2, a0
+			bltu t1, t2, .L2
+			mv a0, t1
+			mv a1, t2
+			j runtime.panicindex
+			.L2:
+			li t2, 1
+			mul t1, t1, t2
+			add t0, t0, t1
+			lbu t0, 0(t0)
+			li a0, 8
+			li a7, 9
+			ecall
+			sd t0, 0(a0)
+			mv t0, a0
+			runtime.panicindex:
+			mv t0, a0
+			mv t1, a1
+			la a0, runtime.panicindex.msg0
+			li a7, 4
+			ecall
+			mv a0, t0
+			li a7, 1
+			ecall
+			la a0, runtime.panicindex.msg1
+			li a7, 4
+			ecall
+			mv a0, t1
+			li a7, 1
+			ecall
+			la a0, runtime.panicindex.msg2
+			li a7, 4
+			ecall
+			li a0, 2
+			li a7, 93
+			ecall
+			runtime.strlen:
+			mv a1, a0
+			li a0, 0
+			beqz a1, runtime.strlen.done
+			runtime.strlen.loop:
+			lbu a2, 0(a1)
+			beqz a2, runtime.strlen.done
+			addi a0, a0, 1
+			addi a1, a1, 1
+			j runtime.strlen.loop
+			runtime.strlen.done:
+			ret
+			`,
+		},
+	}
+
+	runCompilerTests(t, tests)
+}
+
+func TestConst(t *testing.T) {
+	tests := []compilerTest{
+		{
+			input: `
+			const ( a = iota; b; c )
+			print c
+			`,
+			expected: `
+			.data
+			.text
+			li t0, 2
+			mv a0, t0
+			li a7, 1
+			ecall
+			`,
+		},
+		{
+			input: `
+			const pi float = 3.14
+			print pi * pi
+			`,
+			expected: `
+			.data
+			.L1: .double 3.14
+			.text
+			fld ft0, .L1, t0
+			fld ft1, .L1, t0
+			fmul.d ft0, ft0, ft1
+			fmv.d fa0, ft0
+			li a7, 3
+			ecall
+			`,
+		},
+		{
+			input: `
+			const two = 2
+			var f float = two
+			var b byte = two
+			`,
+			expected: `
+			.data
+			f: .double 0
+			.L1: .double 2
+			b: .dword 0
+			.text
+			la s1, f
+			fld ft0, .L1, t0
+			fsd ft0, 0(s1)
+			la s1, b
+			li t0, 2
+			sd t0, 0(s1)
+			`,
+		},
+		{
+			input: `
+			const greeting = "hello, " + "world"
+			print greeting
+			print greeting
+			`,
+			expected: `
+			.data
+			.L1: .string "hello, world"
+			.text
+			la t0, .L1
+			mv a0, t0
+			li a7, 4
+			ecall
+			la t0, .L1
+			mv a0, t0
+			li a7, 4
+			ecall
 			`,
 		},
 		{
+			// Untyped literals and constant expressions get the type of
+			// their use.
 			input: `
-			func incrementer(x int) int {
-				return x + 1
-			}
-
-			func decrementer(x int) int {
-				return x - 1
-			}
-
-			func getFunc(x int) func(int) int {
-				if x == 1 {
-					return incrementer
-				}
-				return decrementer
-			}
-			`,
+			var f float = 2
+			var i int = 2.0
+			var h float = 7 / 2`,
 			expected: `
 			.data
+			f: .double 0
+			.L1: .double 2
+			i: .dword 0
+			h: .double 0
+			.L2: .double 3
 			.text
-			incrementer:
-			addi sp, sp, -16
-			sd a0, 8(sp)
-			sd ra, 16(sp)
-			addi sp, sp, -0
-			ld t0, 8(sp)
-			li t1, 1
-			add t0, t0, t1
-			mv a0, t0
-			addi sp, sp, 0
-			j incrementer.epilogue
-			addi sp, sp, 0
-			incrementer.epilogue:
-			ld ra, 16(sp)
-			addi sp, sp, 16
-			ret
-			decrementer:
-			addi sp, sp, -16
-			sd a0, 8(sp)
-			sd ra, 16(sp)
-			addi sp, sp, -0
-			ld t0, 8(sp)
-			li t1, 1
-			sub t0, t0, t1
-			mv a0, t0
-			addi sp, sp, 0
-			j decrementer.epilogue
-			addi sp, sp, 0
-			decrementer.epilogue:
-			ld ra, 16(sp)
-			addi sp, sp, 16
-			ret
-			getFunc:
-			addi sp, sp, -16
-			sd a0, 8(sp)
-			sd ra, 16(sp)
-			addi sp, sp, -0
-			ld t0, 8(sp)
-			li t1, 1
-			beq t0, t1, .L1
-			li t0, 0
-			b .L2
-			.L1:
-			li t0, 1
-			.L2:
-			beqz t0, .L3
-			addi sp, sp, -0
-			la t0, incrementer
-			mv a0, t0
-			addi sp, sp, 0
-			j getFunc.epilogue
-			addi sp, sp, 0
-			b .L4
-			.L3:
-			.L4:
-			la t0, decrementer
-			mv a0, t0
-			addi sp, sp, 0
-			j getFunc.epilogue
-			addi sp, sp, 0
-			getFunc.epilogue:
-			ld ra, 16(sp)
-			addi sp, sp, 16
-			ret`,
+			la s1, f
+			fld ft0, .L1, t0
+			fsd ft0, 0(s1)
+			la s1, i
+			li t0, 2
+			sd t0, 0(s1)
+			la s1, h
+			fld ft0, .L2, t0
+			fsd ft0, 0(s1)
+			`,
 		},
 	}
 
 	runCompilerTests(t, tests)
 }
 
-func TestCallExpression(t *testing.T) {
+func TestPointer(t *testing.T) {
 	tests := []compilerTest{
 		{
 			input: `
-			func greeter(x string) {
-				print x
+			var x int
+			var p *int = &x
+			*p = *p + 1
+			`,
+			expected: `
+			.data
+			x: .dword 0
+			p: .dword 0
+			.text
+			la s1, p
+			la s10, x
+			sd s10, 0(s1)
+			la s1, p
+			ld s1, 0(s1)
+			la s10, p
+			ld s10, 0(s10)
+			ld s10, 0(s10)
+			li t0, 1
+			add s10, s10, t0
+			sd s10, 0(s1)
+			`,
+		},
+		{
+			input: `
+			{
+				var x float
+				var p *float = &x
+				print *p
 			}
-
-			greeter("Hello Compiler World")`,
+			`,
 			expected: `
 			.data
-			.L1: .string "Hello Compiler World"
 			.text
-			la t0, .L1
-			mv a0, t0
-			call greeter
-			greeter:
 			addi sp, sp, -16
+			li a0, 8
+			li a7, 9
+			ecall
 			sd a0, 8(sp)
-			sd ra, 16(sp)
-			addi sp, sp, -0
 			ld t0, 8(sp)
-			mv a0, t0
-			li a7, 4
+			sd t0, 16(sp)
+			ld t0, 16(sp)
+			fld ft0, 0(t0)
+			fmv.d fa0, ft0
+			li a7, 3
 			ecall
-			addi sp, sp, 0
-			greeter.epilogue:
-			ld ra, 16(sp)
 			addi sp, sp, 16
-			ret
 			`,
 		},
 		{
 			input: `
-			type human struct{name string; age int}
-			func greeter(x human) {
-				print x.age
+			type node struct {
+				val int
+				next *node
 			}
 
-			var h human
-			h.name = "Didac"
-			h.age = 0
-
-			greeter(h)`,
+			var n *node = new(node)
+			n.next = nil
+			print n.val
+			`,
 			expected: `
 			.data
-			h: .dword 0
-			.L1: .string "Didac"
+			n: .dword 0
 			.text
+			la s1, n
 			li a0, 16
 			li a7, 9
 			ecall
-			la t0, h
-			sd a0, 0(t0)
-			la s1, h
-			ld s1, 0(s1)
-			la t0, .L1
+			mv t0, a0
 			sd t0, 0(s1)
-			la s1, h
+			la s1, n
 			ld s1, 0(s1)
 			li t0, 0
 			sd t0, 8(s1)
-			la s1, h
+			la s1, n
+			ld s1, 0(s1)
 			ld s1, 0(s1)
 			mv a0, s1
-			call greeter
-			greeter:
-			addi sp, sp, -16
-			sd a0, 8(sp)
-			sd ra, 16(sp)
-			addi sp, sp, -0
-			ld t0, 8(sp)
-			ld t0, 8(t0)
-			mv a0, t0
 			li a7, 1
 			ecall
-			addi sp, sp, 0
-			greeter.epilogue:
-			ld ra, 16(sp)
-			addi sp, sp, 16
-			ret
 			`,
 		},
 		{
+			// The variable whose address is taken lives on the heap, so the
+			// pointer is valid after the function returns and another call
+			// has reused the stack.
 			input: `
-			func incrementer(x int) int {
-				return x + 1
+			func mk() *int {
+				var x int = 42
+				return &x
 			}
-
-			var x func(int) int
-			x = incrementer
+			func zero() int {
+				var y int = 0
+				return y
+			}
+			var p *int = mk()
+			zero()
+			print *p
 			`,
 			expected: `
 			.data
-			x: .dword 0
+			p: .dword 0
 			.text
-			la s1, x
-			la s10, incrementer
-			sd s10, 0(s1)
-			incrementer:
+			la s1, p
+			call mk
+			mv t0, a0
+			sd t0, 0(s1)
+			call zero
+			mv t0, a0
+			la s1, p
+			ld s1, 0(s1)
+			ld s1, 0(s1)
+			mv a0, s1
+			li a7, 1
+			ecall
+			mk:
+			addi sp, sp, -16
+			sd ra, 16(sp)
 			addi sp, sp, -16
+			li a0, 8
+			li a7, 9
+			ecall
 			sd a0, 8(sp)
+			ld t0, 8(sp)
+			li t1, 42
+			sd t1, 0(t0)
+			ld t0, 8(sp)
+			mv a0, t0
+			addi sp, sp, 16
+			j mk.epilogue
+			addi sp, sp, 16
+			mk.epilogue:
+			ld ra, 16(sp)
+			addi sp, sp, 16
+			ret
+			zero:
+			addi sp, sp, -16
 			sd ra, 16(sp)
-			addi sp, sp, -0
+			addi sp, sp, -16
+			li t0, 0
+			sd t0, 8(sp)
 			ld t0, 8(sp)
-			li t1, 1
-			add t0, t0, t1
 			mv a0, t0
-			addi sp, sp, 0
-			j incrementer.epilogue
-			addi sp, sp, 0
-			incrementer.epilogue:
+			addi sp, sp, 16
+			j zero.epilogue
+			addi sp, sp, 16
+			zero.epilogue:
 			ld ra, 16(sp)
 			addi sp, sp, 16
-			ret`,
+			ret
+			`,
 		},
 		{
+			// The array holding the element whose address is taken lives on
+			// the heap.
 			input: `
-			var x func() func(int)
-			var y func(int)
-			func test() func(int)
-			func test2(int)
-
-			func test() func(int) {
-				return test2
-			}
-
-			func test2(x int) {
-				print x
+			func esc() *int {
+				var a [2]int
+				a[1] = 42
+				return &a[1]
 			}
-
-			x = test
-			y = x()
-			y(10)
+			print *esc()
 			`,
 			expected: `
 			.data
-			x: .dword 0
-			y: .dword 0
 			.text
-			la s1, x
-			la s10, test
-			sd s10, 0(s1)
-			la s1, y
-			la t0, x
+			call esc
+			mv t0, a0
 			ld t0, 0(t0)
-			jalr t0
-			sd a0, 0(s1)
-			li t0, 10
 			mv a0, t0
-			la t0, y
-			ld t0, 0(t0)
-			jalr t0
-			test:
+			li a7, 1
+			ecall
+			esc:
 			addi sp, sp, -16
 			sd ra, 16(sp)
-			addi sp, sp, -0
-			la t0, test2
-			mv a0, t0
-			addi sp, sp, 0
-			j test.epilogue
-			addi sp, sp, 0
-			test.epilogue:
-			ld ra, 16(sp)
-			addi sp, sp, 16
-			ret
-			test2:
 			addi sp, sp, -16
+			li a0, 16
+			li a7, 9
+			ecall
 			sd a0, 8(sp)
-			sd ra, 16(sp)
-			addi sp, sp, -0
 			ld t0, 8(sp)
+			addi t0, t0, 8
+			li t1, 42
+			sd t1, 0(t0)
+			ld t0, 8(sp)
+			addi t0, t0, 8
 			mv a0, t0
-			li a7, 1
-			ecall
-			addi sp, sp, 0
-			test2.epilogue:
+			addi sp, sp, 16
+			j esc.epilogue
+			addi sp, sp, 16
+			esc.epilogue:
 			ld ra, 16(sp)
 			addi sp, sp, 16
 			ret
@@ -0,0 +1,150 @@
+package compiler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Glorforidor/didactic_compiler/ast"
+	"github.com/Glorforidor/didactic_compiler/types"
+)
+
+// An interface variable holds the address of an interface value on the heap,
+// or 0 if it is nil. Like a slice header, the value is never changed after it
+// has been created.
+const (
+	interfaceItab = 0 // The address of the itab of the dynamic type.
+	interfaceData = 8 // The address of the struct.
+	interfaceSize = 16
+)
+
+// convert emits the instructions which convert the value of type v in the
+// register reg into a value of type t. Only a struct, a pointer to a struct or
+// an interface value assigned to an interface is converted, where the
+// register is updated to hold the address of the new interface value.
+func (c *Compiler) convert(v, t types.Type, reg string) error {
+	iface, ok := t.(*types.Interface)
+	if !ok || v.Kind() == types.UntypedNil {
+		return nil
+	}
+
+	if from, ok := v.(*types.Interface); ok {
+		return c.convertInterface(from, iface, reg)
+	}
+
+	s, ok := v.(*types.Struct)
+	if ok {
+		// The interface value holds its own copy of a struct value.
+		c.copyStruct(s, reg)
+	} else {
+		s = v.(*types.Pointer).Elem.(*types.Struct)
+	}
+
+	tmp, err := c.registerTable.allocGeneral()
+	if err != nil {
+		return err
+	}
+
+	c.heapAllocate(interfaceSize)
+	c.emitf("la %s, %s", tmp, c.itab(s, iface))
+	c.emitf("sd %s, %d(a0)", tmp, interfaceItab)
+	c.emitf("sd %s, %d(a0)", reg, interfaceData)
+	c.emitf("mv %s, a0", reg)
+
+	c.registerTable.dealloc(tmp)
+
+	return nil
+}
+
+// convertInterface emits the instructions which convert the interface value in
+// the register reg into a value of the interface to, which has a subset of the
+// methods of the interface from. The dynamic type is only known at run time,
+// so the itab of the new value is built from the itab of the value, with the
+// methods in the order of the interface to. A nil value stays nil.
+func (c *Compiler) convertInterface(from, to *types.Interface, reg string) error {
+	// If the methods of to are the first methods of from, then the itab of
+	// the value is also an itab of to, and the value can be used as it is.
+	prefix := true
+	for i, m := range to.Methods {
+		if methodIndex(from, m) != i {
+			prefix = false
+		}
+	}
+	if prefix {
+		return nil
+	}
+
+	nilLabel := c.label.create()
+	c.emitf("beqz %s, %s", reg, nilLabel)
+
+	itab, err := c.registerTable.allocGeneral()
+	if err != nil {
+		return err
+	}
+	tmp, err := c.registerTable.allocGeneral()
+	if err != nil {
+		return err
+	}
+
+	c.emitf("ld %s, %d(%s)", itab, interfaceItab, reg)
+	c.heapAllocate(8 * len(to.Methods))
+	for i, m := range to.Methods {
+		c.emitf("ld %s, %d(%s)", tmp, 8*methodIndex(from, m), itab)
+		c.emitf("sd %s, %d(a0)", tmp, 8*i)
+	}
+	c.emitf("mv %s, a0", itab)
+
+	c.heapAllocate(interfaceSize)
+	c.emitf("ld %s, %d(%s)", tmp, interfaceData, reg)
+	c.emitf("sd %s, %d(a0)", itab, interfaceItab)
+	c.emitf("sd %s, %d(a0)", tmp, interfaceData)
+	c.emitf("mv %s, a0", reg)
+	c.emitf("%s:", nilLabel)
+
+	c.registerTable.dealloc(itab)
+	c.registerTable.dealloc(tmp)
+
+	return nil
+}
+
+// itab returns the label of the itab of the struct for the interface, and
+// emits the itab the first time it is used. The itab holds the addresses of
+// the methods of the struct in the order of the methods of the interface, so
+// a method is called through an interface value without knowing its dynamic
+// type.
+func (c *Compiler) itab(s *types.Struct, iface *types.Interface) string {
+	label := fmt.Sprintf("itab.%s.%s", s.Name, iface.Name)
+
+	if c.itabs == nil {
+		c.itabs = make(map[string]bool)
+	}
+
+	if c.itabs[label] {
+		return label
+	}
+	c.itabs[label] = true
+
+	if len(iface.Methods) == 0 {
+		c.addConstantf("%s: .dword 0", label)
+		return label
+	}
+
+	var methods []string
+	for _, m := range iface.Methods {
+		methods = append(methods, ast.MethodLabel(s.Name, m.Name))
+	}
+	c.addConstantf("%s: .dword %s", label, strings.Join(methods, ", "))
+
+	return label
+}
+
+// methodIndex returns the index of the method in the interface, which is also
+// the index of the address of the method in an itab.
+func methodIndex(iface *types.Interface, m *types.Method) int {
+	for i, im := range iface.Methods {
+		if im.Name == m.Name {
+			return i
+		}
+	}
+
+	panic(fmt.Sprintf("compiler error: method: %s is not in interface: %s", m.Name, iface))
+}
@@ -3,6 +3,7 @@ package compiler
 import (
 	"fmt"
 	"sort"
+	"strings"
 )
 
 type registerTable struct {
@@ -21,7 +22,8 @@ func riscvTable() *registerTable {
 			"t6": false,
 		},
 		// s0 or also fp is the frame pointer register and therefore not
-		// includeded if it was to be used later on.
+		// includeded if it was to be used later on. s11 is the context
+		// register, which passes the closure of a function value.
 		generalSaved: map[string]bool{
 			"s1":  false,
 			"s2":  false,
@@ -33,7 +35,6 @@ func riscvTable() *registerTable {
 			"s8":  false,
 			"s9":  false,
 			"s10": false,
-			"s11": false,
 		},
 		floating: map[string]bool{
 			"ft0":  false,
@@ -114,3 +115,28 @@ func (rt *registerTable) dealloc(reg string) {
 		rt.generalSaved[reg] = false
 	}
 }
+
+// temporaries returns the allocated temporary registers, that is the general
+// purpose temporary registers and the floating registers. These are the
+// registers a callee is free to overwrite.
+func (rt *registerTable) temporaries() []string {
+	var regs []string
+	for k, v := range rt.general {
+		if v {
+			regs = append(regs, k)
+		}
+	}
+	for k, v := range rt.floating {
+		if v {
+			regs = append(regs, k)
+		}
+	}
+	sort.Strings(regs)
+
+	return regs
+}
+
+// isFloating reports whether reg is a floating register.
+func isFloating(reg string) bool {
+	return strings.HasPrefix(reg, "f")
+}
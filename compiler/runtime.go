@@ -0,0 +1,434 @@
+package compiler
+
+import (
+	"sort"
+	"strings"
+)
+
+// runtimeRoutine is a routine of assembly code which the compiled program may
+// call. It is only emitted when it is used.
+type runtimeRoutine struct {
+	// constants contains the data used by the routine.
+	constants []string
+	// code contains the instructions of the routine including its label.
+	code []string
+}
+
+var runtimeRoutines = map[string]runtimeRoutine{
+	// runtime.panicindex prints that the index in a0 is out of range of the
+	// length in a1 and exits the program.
+	"runtime.panicindex": {
+		constants: []string{
+			`runtime.panicindex.msg0: .string "panic: runtime error: index out of range ["`,
+			`runtime.panicindex.msg1: .string "] with length "`,
+			`runtime.panicindex.msg2: .string "\n"`,
+		},
+		code: []string{
+			"runtime.panicindex:",
+			"mv t0, a0",
+			"mv t1, a1",
+			"la a0, runtime.panicindex.msg0",
+			"li a7, 4",
+			"ecall",
+			"mv a0, t0",
+			"li a7, 1",
+			"ecall",
+			"la a0, runtime.panicindex.msg1",
+			"li a7, 4",
+			"ecall",
+			"mv a0, t1",
+			"li a7, 1",
+			"ecall",
+			"la a0, runtime.panicindex.msg2",
+			"li a7, 4",
+			"ecall",
+			"li a0, 2",
+			"li a7, 93",
+			"ecall",
+		},
+	},
+	// runtime.panicshift prints that a shift count is negative and exits the
+	// program.
+	"runtime.panicshift": {
+		constants: []string{
+			`runtime.panicshift.msg0: .string "panic: runtime error: negative shift amount\n"`,
+		},
+		code: []string{
+			"runtime.panicshift:",
+			"la a0, runtime.panicshift.msg0",
+			"li a7, 4",
+			"ecall",
+			"li a0, 2",
+			"li a7, 93",
+			"ecall",
+		},
+	},
+	// runtime.copy copies a2 bytes from the address in a1 to the address in
+	// a0. The number of bytes must be a multiple of 8. Only the registers
+	// a0-a3 are used.
+	"runtime.copy": {
+		code: []string{
+			"runtime.copy:",
+			"beqz a2, runtime.copy.done",
+			"runtime.copy.loop:",
+			"ld a3, 0(a1)",
+			"sd a3, 0(a0)",
+			"addi a0, a0, 8",
+			"addi a1, a1, 8",
+			"addi a2, a2, -8",
+			"bnez a2, runtime.copy.loop",
+			"runtime.copy.done:",
+			"ret",
+		},
+	},
+	// runtime.cmpstring compares the string in a0 with the string in a1 and
+	// returns in a0 a negative number, zero or a positive number if the first
+	// string is less than, equal to or greater than the second string. The
+	// address 0 is the empty string. Only the registers a0-a3 are used.
+	"runtime.cmpstring": {
+		code: []string{
+			"runtime.cmpstring:",
+			"li a2, 0",
+			"beqz a0, runtime.cmpstring.right",
+			"lbu a2, 0(a0)",
+			"runtime.cmpstring.right:",
+			"li a3, 0",
+			"beqz a1, runtime.cmpstring.test",
+			"lbu a3, 0(a1)",
+			"runtime.cmpstring.test:",
+			"bne a2, a3, runtime.cmpstring.done",
+			"beqz a2, runtime.cmpstring.done",
+			"addi a0, a0, 1",
+			"addi a1, a1, 1",
+			"j runtime.cmpstring",
+			"runtime.cmpstring.done:",
+			"sub a0, a2, a3",
+			"ret",
+		},
+	},
+	// runtime.concat returns in a0 the address of a new string on the heap,
+	// which is the string in a0 followed by the string in a1. The address 0
+	// is the empty string. The space of the string is rounded up to a
+	// multiple of 8 bytes, so the heap stays aligned.
+	"runtime.concat": {
+		code: []string{
+			"runtime.concat:",
+			"mv a2, a0",
+			"mv a3, a1",
+			"li a0, 1",
+			"mv a4, a2",
+			"beqz a4, runtime.concat.len1",
+			"runtime.concat.len0:",
+			"lbu a5, 0(a4)",
+			"beqz a5, runtime.concat.len1",
+			"addi a0, a0, 1",
+			"addi a4, a4, 1",
+			"j runtime.concat.len0",
+			"runtime.concat.len1:",
+			"mv a4, a3",
+			"beqz a4, runtime.concat.alloc",
+			"runtime.concat.len1.loop:",
+			"lbu a5, 0(a4)",
+			"beqz a5, runtime.concat.alloc",
+			"addi a0, a0, 1",
+			"addi a4, a4, 1",
+			"j runtime.concat.len1.loop",
+			"runtime.concat.alloc:",
+			"addi a0, a0, 7",
+			"andi a0, a0, -8",
+			"li a7, 9",
+			"ecall",
+			"mv a4, a0",
+			"beqz a2, runtime.concat.copy1",
+			"runtime.concat.copy0:",
+			"lbu a5, 0(a2)",
+			"beqz a5, runtime.concat.copy1",
+			"sb a5, 0(a4)",
+			"addi a2, a2, 1",
+			"addi a4, a4, 1",
+			"j runtime.concat.copy0",
+			"runtime.concat.copy1:",
+			"sb zero, 0(a4)",
+			"beqz a3, runtime.concat.done",
+			"runtime.concat.copy1.loop:",
+			"lbu a5, 0(a3)",
+			"sb a5, 0(a4)",
+			"addi a3, a3, 1",
+			"addi a4, a4, 1",
+			"bnez a5, runtime.concat.copy1.loop",
+			"runtime.concat.done:",
+			"ret",
+		},
+	},
+	// runtime.strlen returns in a0 the number of bytes of the string in a0.
+	// The address 0 is the empty string. Only the registers a0-a2 are used.
+	"runtime.strlen": {
+		code: []string{
+			"runtime.strlen:",
+			"mv a1, a0",
+			"li a0, 0",
+			"beqz a1, runtime.strlen.done",
+			"runtime.strlen.loop:",
+			"lbu a2, 0(a1)",
+			"beqz a2, runtime.strlen.done",
+			"addi a0, a0, 1",
+			"addi a1, a1, 1",
+			"j runtime.strlen.loop",
+			"runtime.strlen.done:",
+			"ret",
+		},
+	},
+	// runtime.makeslice returns in a0 the address of a new slice header with
+	// the length in a0 and the capacity in a1, where each element is a2
+	// bytes.
+	"runtime.makeslice": {
+		constants: []string{
+			`runtime.makeslice.msg0: .string "panic: runtime error: makeslice: len out of range\n"`,
+			`runtime.makeslice.msg1: .string "panic: runtime error: makeslice: cap out of range\n"`,
+		},
+		code: []string{
+			"runtime.makeslice:",
+			"bltz a0, runtime.makeslice.len",
+			"blt a1, a0, runtime.makeslice.cap",
+			"mv a3, a0",
+			"mv a4, a1",
+			"mul a0, a1, a2",
+			"li a7, 9",
+			"ecall",
+			"mv a5, a0",
+			"li a0, 24",
+			"li a7, 9",
+			"ecall",
+			"sd a5, 0(a0)",
+			"sd a3, 8(a0)",
+			"sd a4, 16(a0)",
+			"ret",
+			"runtime.makeslice.len:",
+			"la a0, runtime.makeslice.msg0",
+			"j runtime.makeslice.panic",
+			"runtime.makeslice.cap:",
+			"la a0, runtime.makeslice.msg1",
+			"runtime.makeslice.panic:",
+			"li a7, 4",
+			"ecall",
+			"li a0, 2",
+			"li a7, 93",
+			"ecall",
+		},
+	},
+	// runtime.append returns in a0 the address of a new slice header, which
+	// has room for a1 more elements than the slice header in a0, where each
+	// element is a2 bytes. If the capacity is too small, then the elements
+	// are copied to a new space twice as big.
+	"runtime.append": {
+		code: []string{
+			"runtime.append:",
+			"ld a3, 8(a0)",
+			"ld a4, 16(a0)",
+			"ld a5, 0(a0)",
+			"add a3, a3, a1",
+			"ble a3, a4, runtime.append.header",
+			"slli a4, a4, 1",
+			"bge a4, a3, runtime.append.grow",
+			"mv a4, a3",
+			"runtime.append.grow:",
+			"mul a0, a4, a2",
+			"li a7, 9",
+			"ecall",
+			"sub a6, a3, a1",
+			"mul a6, a6, a2",
+			"mv a1, a0",
+			"runtime.append.copy:",
+			"beqz a6, runtime.append.copied",
+			"ld a7, 0(a5)",
+			"sd a7, 0(a1)",
+			"addi a5, a5, 8",
+			"addi a1, a1, 8",
+			"addi a6, a6, -8",
+			"j runtime.append.copy",
+			"runtime.append.copied:",
+			"mv a5, a0",
+			"runtime.append.header:",
+			"li a0, 24",
+			"li a7, 9",
+			"ecall",
+			"sd a5, 0(a0)",
+			"sd a3, 8(a0)",
+			"sd a4, 16(a0)",
+			"ret",
+		},
+	},
+	// runtime.slice returns in a0 the address of a new slice header of the
+	// elements a1 up to a2 of the slice header in a0, where each element is
+	// a3 bytes. If the bounds are out of range of the capacity, then the
+	// program panics.
+	"runtime.slice": {
+		code: []string{
+			"runtime.slice:",
+			"ld a4, 16(a0)",
+			"bltu a4, a2, runtime.slice.panic",
+			"bltu a2, a1, runtime.slice.panic",
+			"ld a5, 0(a0)",
+			"mul a6, a1, a3",
+			"add a5, a5, a6",
+			"sub a2, a2, a1",
+			"sub a4, a4, a1",
+			"li a0, 24",
+			"li a7, 9",
+			"ecall",
+			"sd a5, 0(a0)",
+			"sd a2, 8(a0)",
+			"sd a4, 16(a0)",
+			"ret",
+			"runtime.slice.panic:",
+			"mv a0, a1",
+			"mv a1, a2",
+			"mv a2, a4",
+			"la a3, runtime.panicslice.cap",
+			"j runtime.panicslice",
+		},
+	},
+	// runtime.slicestring returns in a0 the address of a new string on the
+	// heap, which is the bytes a1 up to a2 of the string in a0. The address
+	// 0 is the empty string. If the bounds are out of range of the length,
+	// then the program panics. The space of the string is rounded up to a
+	// multiple of 8 bytes, so the heap stays aligned.
+	"runtime.slicestring": {
+		code: []string{
+			"runtime.slicestring:",
+			"mv a4, a0",
+			"li a5, 0",
+			"beqz a4, runtime.slicestring.check",
+			"runtime.slicestring.len:",
+			"add a6, a4, a5",
+			"lbu a6, 0(a6)",
+			"beqz a6, runtime.slicestring.check",
+			"addi a5, a5, 1",
+			"j runtime.slicestring.len",
+			"runtime.slicestring.check:",
+			"bltu a5, a2, runtime.slicestring.panic",
+			"bltu a2, a1, runtime.slicestring.panic",
+			"sub a3, a2, a1",
+			"addi a0, a3, 8",
+			"andi a0, a0, -8",
+			"li a7, 9",
+			"ecall",
+			"add a4, a4, a1",
+			"mv a6, a0",
+			"runtime.slicestring.copy:",
+			"beqz a3, runtime.slicestring.done",
+			"lbu a7, 0(a4)",
+			"sb a7, 0(a6)",
+			"addi a4, a4, 1",
+			"addi a6, a6, 1",
+			"addi a3, a3, -1",
+			"j runtime.slicestring.copy",
+			"runtime.slicestring.done:",
+			"sb zero, 0(a6)",
+			"ret",
+			"runtime.slicestring.panic:",
+			"mv a0, a1",
+			"mv a1, a2",
+			"mv a2, a5",
+			"la a3, runtime.panicslice.len",
+			"j runtime.panicslice",
+		},
+	},
+	// runtime.panicslice prints that the slice bounds a0 up to a1 are out of
+	// range and exits the program. If the high bound is out of range of the
+	// capacity or length in a2, then the string in a3 names which it is.
+	"runtime.panicslice": {
+		constants: []string{
+			`runtime.panicslice.msg0: .string "panic: runtime error: slice bounds out of range ["`,
+			`runtime.panicslice.msg1: .string ":"`,
+			`runtime.panicslice.msg2: .string "]"`,
+			`runtime.panicslice.msg3: .string "\n"`,
+			`runtime.panicslice.cap: .string " with capacity "`,
+			`runtime.panicslice.len: .string " with length "`,
+		},
+		code: []string{
+			"runtime.panicslice:",
+			"mv t0, a0",
+			"mv t1, a1",
+			"mv t2, a2",
+			"mv t3, a3",
+			"la a0, runtime.panicslice.msg0",
+			"li a7, 4",
+			"ecall",
+			"bgeu t2, t1, runtime.panicslice.low",
+			"la a0, runtime.panicslice.msg1",
+			"li a7, 4",
+			"ecall",
+			"mv a0, t1",
+			"li a7, 1",
+			"ecall",
+			"la a0, runtime.panicslice.msg2",
+			"li a7, 4",
+			"ecall",
+			"bltz t1, runtime.panicslice.exit",
+			"mv a0, t3",
+			"li a7, 4",
+			"ecall",
+			"mv a0, t2",
+			"li a7, 1",
+			"ecall",
+			"j runtime.panicslice.exit",
+			"runtime.panicslice.low:",
+			"mv a0, t0",
+			"li a7, 1",
+			"ecall",
+			"la a0, runtime.panicslice.msg1",
+			"li a7, 4",
+			"ecall",
+			"bltz t0, runtime.panicslice.close",
+			"mv a0, t1",
+			"li a7, 1",
+			"ecall",
+			"runtime.panicslice.close:",
+			"la a0, runtime.panicslice.msg2",
+			"li a7, 4",
+			"ecall",
+			"runtime.panicslice.exit:",
+			"la a0, runtime.panicslice.msg3",
+			"li a7, 4",
+			"ecall",
+			"li a0, 2",
+			"li a7, 93",
+			"ecall",
+		},
+	},
+}
+
+// useRuntime marks the runtime routine as used, so it is emitted with the
+// rest of the program.
+func (c *Compiler) useRuntime(name string) {
+	if c.runtime == nil {
+		c.runtime = make(map[string]bool)
+	}
+
+	if c.runtime[name] {
+		return
+	}
+
+	c.runtime[name] = true
+	c.addConstant(runtimeRoutines[name].constants...)
+}
+
+// runtimeAsm returns the instructions of the used runtime routines.
+func (c *Compiler) runtimeAsm() string {
+	var names []string
+	for name := range c.runtime {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for _, name := range names {
+		for _, code := range runtimeRoutines[name].code {
+			sb.WriteString("\n")
+			sb.WriteString(code)
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,266 @@
+package compiler
+
+import (
+	"strings"
+
+	"github.com/Glorforidor/didactic_compiler/ast"
+	"github.com/Glorforidor/didactic_compiler/symbol"
+	"github.com/Glorforidor/didactic_compiler/types"
+)
+
+// A switch on an integer whose cases are dense constants jumps to its case
+// through a jump table, which holds the address of the case of each value from
+// the smallest to the largest constant. The cases are dense if the table holds
+// at most twice as many values as there are constants.
+const (
+	jumpTableCases   = 4
+	jumpTableDensity = 2
+)
+
+// switchStatement emits the instructions of the switch statement. The tag is
+// compared to the values of the cases in order, and the body of the first case
+// with an equal value is run, or the body of the default case if there is none.
+func (c *Compiler) switchStatement(node *ast.SwitchStatement) error {
+	doneLabel := c.label.create()
+
+	labels := make([]string, len(node.Cases))
+	defaultLabel := doneLabel
+	for i, cc := range node.Cases {
+		labels[i] = c.label.create()
+
+		if len(cc.Values) == 0 {
+			defaultLabel = labels[i]
+		}
+	}
+
+	var err error
+	if values, ok := c.denseCases(node); ok {
+		err = c.jumpTable(node, values, labels, defaultLabel)
+	} else {
+		err = c.compareCases(node, labels, defaultLabel)
+	}
+	if err != nil {
+		return err
+	}
+
+	t := &target{node: node, breakLabel: doneLabel, stackSpace: c.stackSpace}
+	c.targets = append(c.targets, t)
+	defer func() { c.targets = c.targets[:len(c.targets)-1] }()
+
+	for i, cc := range node.Cases {
+		c.emitf("%s:", labels[i])
+
+		if err := c.Compile(cc.Body); err != nil {
+			return err
+		}
+
+		// A case does not continue into the next case.
+		if i != len(node.Cases)-1 {
+			c.emitf("b %s", doneLabel)
+		}
+	}
+
+	c.emitf("%s:", doneLabel)
+
+	return nil
+}
+
+// compareCases emits the instructions which compare the tag to the value of
+// each case, and jump to the label of the first case with an equal value. A
+// switch without a tag jumps to the first case with a true value.
+func (c *Compiler) compareCases(node *ast.SwitchStatement, labels []string, defaultLabel string) error {
+	var tag string
+	if node.Tag != nil {
+		if err := c.Compile(node.Tag); err != nil {
+			return err
+		}
+		c.loadGlobalOrPtrValue(node.Tag)
+
+		tag = node.Tag.Register()
+	}
+
+	for i, cc := range node.Cases {
+		for _, v := range cc.Values {
+			if err := c.Compile(v); err != nil {
+				return err
+			}
+			c.loadGlobalOrPtrValue(v)
+
+			reg := v.Register()
+
+			switch {
+			case node.Tag == nil:
+				c.emitf("bnez %s, %s", reg, labels[i])
+			case v.Type().Kind() == types.Float:
+				tmp, err := c.registerTable.allocGeneral()
+				if err != nil {
+					return err
+				}
+
+				c.emitf("feq.d %s, %s, %s", tmp, tag, reg)
+				c.emitf("bnez %s, %s", tmp, labels[i])
+				c.registerTable.dealloc(tmp)
+			case v.Type().Kind() == types.String:
+				c.emitf("mv a0, %s", tag)
+				c.emitf("mv a1, %s", reg)
+				c.emitf("call runtime.cmpstring")
+				c.emitf("beqz a0, %s", labels[i])
+
+				c.useRuntime("runtime.cmpstring")
+			default:
+				c.emitf("beq %s, %s, %s", tag, reg, labels[i])
+			}
+
+			c.registerTable.dealloc(reg)
+		}
+	}
+
+	if node.Tag != nil {
+		c.registerTable.dealloc(tag)
+	}
+
+	c.emitf("b %s", defaultLabel)
+
+	return nil
+}
+
+// jumpTable emits the jump table of the dense cases, and the instructions
+// which jump through it to the label of the case of the tag. A tag outside of
+// the table jumps to the default label.
+func (c *Compiler) jumpTable(node *ast.SwitchStatement, values [][]int64, labels []string, defaultLabel string) error {
+	low, high := values[0][0], values[0][0]
+	for _, vs := range values {
+		for _, v := range vs {
+			low, high = min(low, v), max(high, v)
+		}
+	}
+
+	table := make([]string, high-low+1)
+	for i := range table {
+		table[i] = defaultLabel
+	}
+
+	// The first case of a value is the one which is run.
+	for i := len(values) - 1; i >= 0; i-- {
+		for _, v := range values[i] {
+			table[v-low] = labels[i]
+		}
+	}
+
+	tableLabel := c.label.create()
+	c.addConstantf("%s: .dword %s", tableLabel, strings.Join(table, ", "))
+
+	if err := c.Compile(node.Tag); err != nil {
+		return err
+	}
+	c.loadGlobalOrPtrValue(node.Tag)
+
+	tag := node.Tag.Register()
+
+	tmp, err := c.registerTable.allocGeneral()
+	if err != nil {
+		return err
+	}
+
+	if low != 0 {
+		c.emitf("li %s, %d", tmp, low)
+		c.emitf("sub %s, %s, %s", tag, tag, tmp)
+	}
+
+	// A tag below the smallest value wraps around to a large unsigned
+	// number, so a single unsigned comparison finds every tag outside.
+	c.emitf("li %s, %d", tmp, len(table))
+	c.emitf("bgeu %s, %s, %s", tag, tmp, defaultLabel)
+	c.emitf("slli %s, %s, 3", tag, tag)
+	c.emitf("la %s, %s", tmp, tableLabel)
+	c.emitf("add %s, %s, %s", tag, tag, tmp)
+	c.emitf("ld %s, 0(%s)", tag, tag)
+	c.emitf("jr %s", tag)
+
+	c.registerTable.dealloc(tmp)
+	c.registerTable.dealloc(tag)
+
+	return nil
+}
+
+// denseCases returns the values of each case of the switch, if the switch is
+// on an integer and the values are dense constants.
+func (c *Compiler) denseCases(node *ast.SwitchStatement) ([][]int64, bool) {
+	if node.Tag == nil {
+		return nil, false
+	}
+
+	if k := node.Tag.Type().Kind(); k != types.Int && k != types.Byte {
+		return nil, false
+	}
+
+	var low, high int64
+	var n int
+	values := make([][]int64, len(node.Cases))
+
+	for i, cc := range node.Cases {
+		for _, v := range cc.Values {
+			value, ok := c.intConstant(v)
+			if !ok {
+				return nil, false
+			}
+
+			if n == 0 {
+				low, high = value, value
+			}
+			low, high = min(low, value), max(high, value)
+			n++
+
+			values[i] = append(values[i], value)
+		}
+	}
+
+	if n < jumpTableCases {
+		return nil, false
+	}
+
+	// The span of the values is found as an unsigned number, since it does
+	// not fit an int64 when the values are far apart.
+	if uint64(high-low) >= uint64(jumpTableDensity*n) {
+		return nil, false
+	}
+
+	return values, true
+}
+
+// intConstant returns the value of the expression, if it is an integer
+// literal, a constant or the sign of one.
+func (c *Compiler) intConstant(expr ast.Expression) (int64, bool) {
+	switch e := expr.(type) {
+	case *ast.IntegerLiteral:
+		return e.Value, true
+	case *ast.PrefixExpression:
+		v, ok := c.intConstant(e.Right)
+		if !ok {
+			return 0, false
+		}
+
+		switch e.Operator {
+		case "-":
+			return -v, true
+		case "+":
+			return v, true
+		}
+	case *ast.Identifier:
+		s, ok := c.symbolTable.Resolve(e.Value)
+		if !ok || s.Scope != symbol.ConstScope {
+			return 0, false
+		}
+
+		switch v := s.Value.(type) {
+		case int64:
+			return v, true
+		case float64:
+			// An untyped float constant used as an integer has no
+			// fraction.
+			return int64(v), true
+		}
+	}
+
+	return 0, false
+}
@@ -74,6 +74,17 @@ func (l *Lexer) makeTwoCharToken(t token.TokenType) token.Token {
 	return token.Token{Type: t, Literal: literal, Position: pos}
 }
 
+func (l *Lexer) makeThreeCharToken(t token.TokenType) token.Token {
+	pos := token.Position{Row: l.line, Col: l.column}
+	ch := l.ch
+	l.readChar()
+	ch2 := l.ch
+	l.readChar()
+	literal := string(ch) + string(ch2) + string(l.ch)
+
+	return token.Token{Type: t, Literal: literal, Position: pos}
+}
+
 func (l *Lexer) NextToken() token.Token {
 	l.skipWhiteSpace()
 
@@ -92,16 +103,78 @@ func (l *Lexer) NextToken() token.Token {
 		l.insertSemi = false
 		tok = newToken(token.Semicolon, l.ch, position)
 	case '+':
-		tok = newToken(token.Plus, l.ch, position)
+		switch l.peek() {
+		case '+':
+			tok = l.makeTwoCharToken(token.Increment)
+			insertSemi = true
+		case '=':
+			tok = l.makeTwoCharToken(token.PlusAssign)
+		default:
+			tok = newToken(token.Plus, l.ch, position)
+		}
 	case '-':
-		tok = newToken(token.Minus, l.ch, position)
+		switch l.peek() {
+		case '-':
+			tok = l.makeTwoCharToken(token.Decrement)
+			insertSemi = true
+		case '=':
+			tok = l.makeTwoCharToken(token.MinusAssign)
+		default:
+			tok = newToken(token.Minus, l.ch, position)
+		}
 	case '*':
-		tok = newToken(token.Asterisk, l.ch, position)
+		if l.peek() == '=' {
+			tok = l.makeTwoCharToken(token.AsteriskAssign)
+		} else {
+			tok = newToken(token.Asterisk, l.ch, position)
+		}
 	case '/':
-		tok = newToken(token.Slash, l.ch, position)
+		if l.peek() == '=' {
+			tok = l.makeTwoCharToken(token.SlashAssign)
+		} else {
+			tok = newToken(token.Slash, l.ch, position)
+		}
+	case '%':
+		if l.peek() == '=' {
+			tok = l.makeTwoCharToken(token.PercentAssign)
+		} else {
+			tok = newToken(token.Percent, l.ch, position)
+		}
+	case '^':
+		if l.peek() == '=' {
+			tok = l.makeTwoCharToken(token.CaretAssign)
+		} else {
+			tok = newToken(token.Caret, l.ch, position)
+		}
+	case '&':
+		switch l.peek() {
+		case '&':
+			tok = l.makeTwoCharToken(token.And)
+		case '^':
+			if l.peekN(2) == '=' {
+				tok = l.makeThreeCharToken(token.AndNotAssign)
+			} else {
+				tok = l.makeTwoCharToken(token.AndNot)
+			}
+		case '=':
+			tok = l.makeTwoCharToken(token.AmpersandAssign)
+		default:
+			tok = newToken(token.Ampersand, l.ch, position)
+		}
+	case '|':
+		switch l.peek() {
+		case '|':
+			tok = l.makeTwoCharToken(token.Or)
+		case '=':
+			tok = l.makeTwoCharToken(token.PipeAssign)
+		default:
+			tok = newToken(token.Pipe, l.ch, position)
+		}
 	case '!':
 		if l.peek() == '=' {
 			tok = l.makeTwoCharToken(token.NotEqual)
+		} else {
+			tok = newToken(token.Bang, l.ch, position)
 		}
 	case '=':
 		if l.peek() == '=' {
@@ -110,7 +183,31 @@ func (l *Lexer) NextToken() token.Token {
 			tok = newToken(token.Assign, l.ch, position)
 		}
 	case '<':
-		tok = newToken(token.LessThan, l.ch, position)
+		switch l.peek() {
+		case '=':
+			tok = l.makeTwoCharToken(token.LessEqual)
+		case '<':
+			if l.peekN(2) == '=' {
+				tok = l.makeThreeCharToken(token.ShiftLeftAssign)
+			} else {
+				tok = l.makeTwoCharToken(token.ShiftLeft)
+			}
+		default:
+			tok = newToken(token.LessThan, l.ch, position)
+		}
+	case '>':
+		switch l.peek() {
+		case '=':
+			tok = l.makeTwoCharToken(token.GreaterEqual)
+		case '>':
+			if l.peekN(2) == '=' {
+				tok = l.makeThreeCharToken(token.ShiftRightAssign)
+			} else {
+				tok = l.makeTwoCharToken(token.ShiftRight)
+			}
+		default:
+			tok = newToken(token.GreaterThan, l.ch, position)
+		}
 	case '(':
 		tok = newToken(token.Lparen, l.ch, position)
 	case ')':
@@ -121,8 +218,21 @@ func (l *Lexer) NextToken() token.Token {
 	case '}':
 		tok = newToken(token.Rbrace, l.ch, position)
 		insertSemi = true
+	case '[':
+		tok = newToken(token.Lbracket, l.ch, position)
+	case ']':
+		tok = newToken(token.Rbracket, l.ch, position)
+		insertSemi = true
 	case ';':
 		tok = newToken(token.Semicolon, l.ch, position)
+	case ',':
+		tok = newToken(token.Comma, l.ch, position)
+	case ':':
+		if l.peek() == '=' {
+			tok = l.makeTwoCharToken(token.Define)
+		} else {
+			tok = newToken(token.Colon, l.ch, position)
+		}
 	case '"':
 		tok.Type = token.String
 		tok.Literal = l.readString()
@@ -189,6 +299,15 @@ func (l *Lexer) peek() byte {
 	return l.input[l.readPosition]
 }
 
+// peekN returns the character n characters ahead of the current character.
+func (l *Lexer) peekN(n int) byte {
+	if l.position+n >= len(l.input) {
+		return eof
+	}
+
+	return l.input[l.position+n]
+}
+
 // isLetter check whether ch is a letter. It includes "_" as a letter.
 func isLetter(ch byte) bool {
 	// Checking whether ch is a letter is done by checking if the byte is
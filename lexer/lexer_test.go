@@ -26,10 +26,28 @@ func TestNextToken(t *testing.T) {
 	var x bool
 	for var i int = 0; i < 1; i = i + 1 { }
 	func greet(x int) { }
+	func add(x int, y int) int { }
 	type human struct{name string}
 	return 2
 	x.name
 	greet(2)
+	var a [3]int
+	a[0]
+	s = append(s[1:], len(s), cap(s))
+	make([]int, 2)
+	p = &x
+	p = new(int)
+	p = nil
+	type shape interface{area() float}
+	!a && b || c
+	<= > >=
+	% & | ^ &^ << >>
+	var b byte
+	const (a = iota)
+	outer: break outer; continue
+	switch x { case 1: default: }
+	x := 1
+	+= -= *= /= %= &= |= ^= &^= <<= >>= ++ --
 `
 
 	tests := []struct {
@@ -110,6 +128,18 @@ func TestNextToken(t *testing.T) {
 		{token.Rparen, ")"},
 		{token.Lbrace, "{"},
 		{token.Rbrace, "}"},
+		{token.Func, "func"},
+		{token.Ident, "add"},
+		{token.Lparen, "("},
+		{token.Ident, "x"},
+		{token.IntType, "int"},
+		{token.Comma, ","},
+		{token.Ident, "y"},
+		{token.IntType, "int"},
+		{token.Rparen, ")"},
+		{token.IntType, "int"},
+		{token.Lbrace, "{"},
+		{token.Rbrace, "}"},
 		{token.Type, "type"},
 		{token.Ident, "human"},
 		{token.Struct, "struct"},
@@ -126,6 +156,122 @@ func TestNextToken(t *testing.T) {
 		{token.Lparen, "("},
 		{token.Int, "2"},
 		{token.Rparen, ")"},
+		{token.Var, "var"},
+		{token.Ident, "a"},
+		{token.Lbracket, "["},
+		{token.Int, "3"},
+		{token.Rbracket, "]"},
+		{token.IntType, "int"},
+		{token.Ident, "a"},
+		{token.Lbracket, "["},
+		{token.Int, "0"},
+		{token.Rbracket, "]"},
+		{token.Ident, "s"},
+		{token.Assign, "="},
+		{token.Append, "append"},
+		{token.Lparen, "("},
+		{token.Ident, "s"},
+		{token.Lbracket, "["},
+		{token.Int, "1"},
+		{token.Colon, ":"},
+		{token.Rbracket, "]"},
+		{token.Comma, ","},
+		{token.Len, "len"},
+		{token.Lparen, "("},
+		{token.Ident, "s"},
+		{token.Rparen, ")"},
+		{token.Comma, ","},
+		{token.Cap, "cap"},
+		{token.Lparen, "("},
+		{token.Ident, "s"},
+		{token.Rparen, ")"},
+		{token.Rparen, ")"},
+		{token.Make, "make"},
+		{token.Lparen, "("},
+		{token.Lbracket, "["},
+		{token.Rbracket, "]"},
+		{token.IntType, "int"},
+		{token.Comma, ","},
+		{token.Int, "2"},
+		{token.Rparen, ")"},
+		{token.Ident, "p"},
+		{token.Assign, "="},
+		{token.Ampersand, "&"},
+		{token.Ident, "x"},
+		{token.Ident, "p"},
+		{token.Assign, "="},
+		{token.New, "new"},
+		{token.Lparen, "("},
+		{token.IntType, "int"},
+		{token.Rparen, ")"},
+		{token.Ident, "p"},
+		{token.Assign, "="},
+		{token.Nil, "nil"},
+		{token.Type, "type"},
+		{token.Ident, "shape"},
+		{token.Interface, "interface"},
+		{token.Lbrace, "{"},
+		{token.Ident, "area"},
+		{token.Lparen, "("},
+		{token.Rparen, ")"},
+		{token.FloatType, "float"},
+		{token.Rbrace, "}"},
+		{token.Bang, "!"},
+		{token.Ident, "a"},
+		{token.And, "&&"},
+		{token.Ident, "b"},
+		{token.Or, "||"},
+		{token.Ident, "c"},
+		{token.LessEqual, "<="},
+		{token.GreaterThan, ">"},
+		{token.GreaterEqual, ">="},
+		{token.Percent, "%"},
+		{token.Ampersand, "&"},
+		{token.Pipe, "|"},
+		{token.Caret, "^"},
+		{token.AndNot, "&^"},
+		{token.ShiftLeft, "<<"},
+		{token.ShiftRight, ">>"},
+		{token.Var, "var"},
+		{token.Ident, "b"},
+		{token.ByteType, "byte"},
+		{token.Const, "const"},
+		{token.Lparen, "("},
+		{token.Ident, "a"},
+		{token.Assign, "="},
+		{token.Iota, "iota"},
+		{token.Rparen, ")"},
+		{token.Ident, "outer"},
+		{token.Colon, ":"},
+		{token.Break, "break"},
+		{token.Ident, "outer"},
+		{token.Semicolon, ";"},
+		{token.Continue, "continue"},
+		{token.Switch, "switch"},
+		{token.Ident, "x"},
+		{token.Lbrace, "{"},
+		{token.Case, "case"},
+		{token.Int, "1"},
+		{token.Colon, ":"},
+		{token.Default, "default"},
+		{token.Colon, ":"},
+		{token.Rbrace, "}"},
+		{token.Ident, "x"},
+		{token.Define, ":="},
+		{token.Int, "1"},
+		{token.PlusAssign, "+="},
+		{token.MinusAssign, "-="},
+		{token.AsteriskAssign, "*="},
+		{token.SlashAssign, "/="},
+		{token.PercentAssign, "%="},
+		{token.AmpersandAssign, "&="},
+		{token.PipeAssign, "|="},
+		{token.CaretAssign, "^="},
+		{token.AndNotAssign, "&^="},
+		{token.ShiftLeftAssign, "<<="},
+		{token.ShiftRightAssign, ">>="},
+		{token.Increment, "++"},
+		{token.Decrement, "--"},
 		{token.Eof, ""},
 	}
 
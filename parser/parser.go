@@ -28,6 +28,11 @@ type Parser struct {
 	// Pratt parsing maps token types with parsing functions.
 	prefixParseFuncs map[token.TokenType]prefixParseFunc
 	infixParseFuncs  map[token.TokenType]infixParseFunc
+
+	// noCompositeLit is set while parsing the header of an if or for
+	// statement, where a '{' after an identifier starts the body and not a
+	// composite literal.
+	noCompositeLit bool
 }
 
 func New(l *lexer.Lexer) *Parser {
@@ -43,6 +48,9 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerPrefixFunc(token.String, p.parseStringLiteral)
 	p.registerPrefixFunc(token.True, p.parseBoolLiteral)
 	p.registerPrefixFunc(token.False, p.parseBoolLiteral)
+	p.registerPrefixFunc(token.Nil, p.parseNilLiteral)
+	p.registerPrefixFunc(token.Iota, p.parseIota)
+	p.registerPrefixFunc(token.Func, p.parseFuncLiteral)
 
 	// register identifier
 	p.registerPrefixFunc(token.Ident, p.parseIdentifier)
@@ -50,18 +58,54 @@ func New(l *lexer.Lexer) *Parser {
 	// register grouping
 	p.registerPrefixFunc(token.Lparen, p.parseGroupedExpression)
 
+	// register prefix operators
+	p.registerPrefixFunc(token.Ampersand, p.parsePrefixExpression)
+	p.registerPrefixFunc(token.Asterisk, p.parsePrefixExpression)
+	p.registerPrefixFunc(token.Bang, p.parsePrefixExpression)
+	p.registerPrefixFunc(token.Minus, p.parsePrefixExpression)
+	p.registerPrefixFunc(token.Plus, p.parsePrefixExpression)
+
+	// register conversions
+	p.registerPrefixFunc(token.IntType, p.parseConversionExpression)
+	p.registerPrefixFunc(token.FloatType, p.parseConversionExpression)
+	p.registerPrefixFunc(token.StringType, p.parseConversionExpression)
+	p.registerPrefixFunc(token.BoolType, p.parseConversionExpression)
+	p.registerPrefixFunc(token.ByteType, p.parseConversionExpression)
+
+	// register builtin functions
+	p.registerPrefixFunc(token.Make, p.parseBuiltinExpression)
+	p.registerPrefixFunc(token.Append, p.parseBuiltinExpression)
+	p.registerPrefixFunc(token.Len, p.parseBuiltinExpression)
+	p.registerPrefixFunc(token.Cap, p.parseBuiltinExpression)
+	p.registerPrefixFunc(token.New, p.parseBuiltinExpression)
+
 	// register operators
 	p.registerInfixFunc(token.Plus, p.parseInfixExpression)
 	p.registerInfixFunc(token.Minus, p.parseInfixExpression)
 	p.registerInfixFunc(token.Asterisk, p.parseInfixExpression)
 	p.registerInfixFunc(token.Slash, p.parseInfixExpression)
+	p.registerInfixFunc(token.Percent, p.parseInfixExpression)
+	p.registerInfixFunc(token.Ampersand, p.parseInfixExpression)
+	p.registerInfixFunc(token.Pipe, p.parseInfixExpression)
+	p.registerInfixFunc(token.Caret, p.parseInfixExpression)
+	p.registerInfixFunc(token.AndNot, p.parseInfixExpression)
+	p.registerInfixFunc(token.ShiftLeft, p.parseInfixExpression)
+	p.registerInfixFunc(token.ShiftRight, p.parseInfixExpression)
 	p.registerInfixFunc(token.Equal, p.parseInfixExpression)
 	p.registerInfixFunc(token.NotEqual, p.parseInfixExpression)
 	p.registerInfixFunc(token.LessThan, p.parseInfixExpression)
+	p.registerInfixFunc(token.LessEqual, p.parseInfixExpression)
+	p.registerInfixFunc(token.GreaterThan, p.parseInfixExpression)
+	p.registerInfixFunc(token.GreaterEqual, p.parseInfixExpression)
+	p.registerInfixFunc(token.And, p.parseInfixExpression)
+	p.registerInfixFunc(token.Or, p.parseInfixExpression)
 
 	// register call
 	p.registerInfixFunc(token.Lparen, p.parseCallExpression)
 
+	// register index
+	p.registerInfixFunc(token.Lbracket, p.parseIndexExpression)
+
 	// register selector
 	p.registerInfixFunc(token.Period, p.parseSelectorExpression)
 
@@ -175,6 +219,11 @@ func (p *Parser) ParseProgram() *ast.Program {
 }
 
 func (p *Parser) parseStatement() ast.Statement {
+	// Only a for statement or a switch statement can be labelled.
+	if p.curTokenIs(token.Ident) && p.peekTokenIs(token.Colon) {
+		return p.parseLabeledStatement()
+	}
+
 	switch p.curToken.Type {
 	case token.Print:
 		return p.parsePrintStatement()
@@ -185,6 +234,13 @@ func (p *Parser) parseStatement() ast.Statement {
 		}
 
 		return v
+	case token.Const:
+		c := p.parseConstStatement()
+		if c != nil && !p.expectSemi() {
+			return nil
+		}
+
+		return c
 	case token.Type:
 		return p.parseTypeStatement()
 	case token.Lbrace:
@@ -201,6 +257,10 @@ func (p *Parser) parseStatement() ast.Statement {
 		return p.parseFuncStatement()
 	case token.Return:
 		return p.parseReturnStatement()
+	case token.Break, token.Continue:
+		return p.parseBranchStatement()
+	case token.Switch:
+		return p.parseSwitchStatement()
 	default:
 		return p.parseExpressionOrAssignStatement()
 	}
@@ -228,41 +288,124 @@ func (p *Parser) parsePrintStatement() *ast.PrintStatement {
 func (p *Parser) parseVarStatement() *ast.VarStatement {
 	stmt := &ast.VarStatement{Token: p.curToken}
 
-	if !p.expectPeek(token.Ident) {
-		return nil
+	for {
+		if !p.expectPeek(token.Ident) {
+			return nil
+		}
+
+		id := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		stmt.Names = append(stmt.Names, id)
+
+		if !p.peekTokenIs(token.Comma) {
+			break
+		}
+		p.nextToken() // ","
 	}
 
-	id := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	// Without a type the names are given the types of the values e.g.
+	// "var x = 2".
+	if !p.peekTokenIs(token.Assign) {
+		p.nextToken() // advance to type
 
-	if !p.expectPeek(token.IntType, token.FloatType, token.StringType, token.BoolType, token.Ident, token.Func) {
-		return nil
+		tnode := p.parseType()
+		if tnode == nil {
+			return nil
+		}
+
+		// All the names share the same type.
+		for _, id := range stmt.Names {
+			id.Tnode = tnode
+		}
 	}
-	// p.nextToken() // advance to type
 
-	if p.curTokenIs(token.IntType, token.FloatType, token.StringType, token.BoolType, token.Ident) {
-		id.Tnode = &ast.BasicType{Token: p.curToken}
-	} else if p.curTokenIs(token.Func) {
-		if !p.expectPeek(token.Lparen) {
+	if p.peekTokenIs(token.Assign) {
+		p.nextToken() // "="
+		p.nextToken() // the expression
+
+		stmt.Values = p.parseExpressionList()
+	}
+
+	return stmt
+}
+
+// parseConstStatement parses a constant declaration, which is either a single
+// spec e.g. "const pi float = 3.14" or a group of specs e.g.
+// "const ( a = iota; b; c )".
+func (p *Parser) parseConstStatement() *ast.ConstStatement {
+	stmt := &ast.ConstStatement{Token: p.curToken}
+
+	if !p.peekTokenIs(token.Lparen) {
+		spec := p.parseConstSpec(0)
+		if spec == nil {
 			return nil
 		}
 
-		ft := p.parseFuncType()
-		id.Tnode = ft
-	} else {
-		p.error("expected a type, got: " + "'" + string(p.curToken.Type) + "'")
-		return nil
+		stmt.Specs = append(stmt.Specs, spec)
+
+		return stmt
 	}
+	p.nextToken() // "("
 
-	stmt.Name = id
+	for !p.peekTokenIs(token.Rparen) {
+		spec := p.parseConstSpec(len(stmt.Specs))
+		if spec == nil {
+			return nil
+		}
+
+		stmt.Specs = append(stmt.Specs, spec)
+
+		if !p.expectSemi() {
+			return nil
+		}
+	}
+	p.nextToken() // ")"
+
+	return stmt
+}
+
+// parseConstSpec parses a line of a constant declaration, which is the spec
+// with the given iota.
+func (p *Parser) parseConstSpec(iota int) *ast.ConstSpec {
+	spec := &ast.ConstSpec{Iota: iota}
+
+	for {
+		if !p.expectPeek(token.Ident) {
+			return nil
+		}
+
+		id := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		spec.Names = append(spec.Names, id)
+
+		if !p.peekTokenIs(token.Comma) {
+			break
+		}
+		p.nextToken() // ","
+	}
+
+	if !p.peekTokenIs(token.Assign) && !p.peekTokenIs(token.Semicolon) && !p.peekTokenIs(token.Rparen) {
+		p.nextToken() // advance to type
+
+		spec.Tnode = p.parseType()
+		if spec.Tnode == nil {
+			return nil
+		}
+	}
 
 	if p.peekTokenIs(token.Assign) {
 		p.nextToken() // "="
 		p.nextToken() // the expression
 
-		stmt.Value = p.parseExpression(Lowest)
+		spec.Values = p.parseExpressionList()
 	}
 
-	return stmt
+	// Only a spec in a group may leave out the values, which repeats the
+	// previous values of the group.
+	if len(spec.Values) == 0 && (iota == 0 || spec.Tnode != nil) {
+		p.error("missing init expr for const declaration")
+		return nil
+	}
+
+	return spec
 }
 
 func (p *Parser) parseTypeStatement() *ast.TypeStatement {
@@ -274,13 +417,35 @@ func (p *Parser) parseTypeStatement() *ast.TypeStatement {
 
 	id := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 
-	if !p.expectPeek(token.Struct) {
+	stmt.Name = id
+
+	if !p.expectPeek(
+		token.Struct,
+		token.Interface,
+		token.IntType,
+		token.FloatType,
+		token.StringType,
+		token.BoolType,
+		token.ByteType,
+		token.Ident,
+	) {
 		return nil
 	}
 
-	stmt.Name = id
+	switch {
+	case p.curTokenIs(token.Interface):
+		stmt.Type = p.parseInterfaceType()
+	case p.curTokenIs(token.Struct):
+		stmt.Type = p.parseStructType()
+	default:
+		// A basic type, or another named type, gives a new type of the
+		// same kind.
+		stmt.Type = p.parseType()
+	}
 
-	stmt.Type = p.parseStructType()
+	if stmt.Type == nil {
+		return nil
+	}
 
 	if !p.expectSemi() {
 		return nil
@@ -308,12 +473,13 @@ func (p *Parser) parseStructType() ast.TypeNode {
 
 	id := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 
-	if !p.expectPeek(token.IntType, token.FloatType, token.StringType, token.BoolType, token.Ident) {
+	p.nextToken() // advance to the type
+
+	id.Tnode = p.parseType()
+	if id.Tnode == nil {
 		return nil
 	}
 
-	id.Tnode = &ast.BasicType{Token: p.curToken}
-
 	st.Fields = append(st.Fields, id)
 	for p.peekTokenIs(token.Semicolon, token.Ident) {
 		if p.peekTokenIs(token.Semicolon) {
@@ -327,12 +493,13 @@ func (p *Parser) parseStructType() ast.TypeNode {
 
 		id := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 
-		if !p.expectPeek(token.IntType, token.FloatType, token.StringType, token.BoolType, token.Ident) {
+		p.nextToken() // advance to the type
+
+		id.Tnode = p.parseType()
+		if id.Tnode == nil {
 			return nil
 		}
 
-		id.Tnode = &ast.BasicType{Token: p.curToken}
-
 		st.Fields = append(st.Fields, id)
 	}
 
@@ -343,16 +510,54 @@ func (p *Parser) parseStructType() ast.TypeNode {
 	return st
 }
 
+// parseInterfaceType parses an interface type e.g.
+// "interface{area() float; scale(float)}".
+func (p *Parser) parseInterfaceType() ast.TypeNode {
+	it := &ast.InterfaceType{Token: p.curToken}
+
+	if !p.expectPeek(token.Lbrace) {
+		return nil
+	}
+
+	for !p.peekTokenIs(token.Rbrace) {
+		if !p.expectPeek(token.Ident) {
+			return nil
+		}
+
+		m := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+		if !p.expectPeek(token.Lparen) {
+			return nil
+		}
+
+		ft := p.parseFuncType()
+		if ft == nil {
+			return nil
+		}
+		m.Tnode = ft
+
+		it.Methods = append(it.Methods, m)
+
+		if !p.peekTokenIs(token.Rbrace) && !p.expectPeek(token.Semicolon) {
+			return nil
+		}
+	}
+
+	p.nextToken() // advance to '}'
+
+	return it
+}
+
 func (p *Parser) parseAssignStatement() *ast.AssignStatement {
 	var stmt ast.AssignStatement
 	// current token is on the identifier
-	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	stmt.Names = []ast.Expression{&ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}}
 
 	p.nextToken() // advance to the "="
 	stmt.Token = p.curToken
 
 	p.nextToken() // advance to the value
-	stmt.Value = p.parseExpression(Lowest)
+	stmt.Values = p.parseExpressionList()
 
 	return &stmt
 }
@@ -380,11 +585,28 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 }
 
 func (p *Parser) parseIfStatement() *ast.IfStatement {
+	stmt := p.parseIf()
+	if stmt == nil {
+		return nil
+	}
+
+	if !p.expectSemi() {
+		return nil
+	}
+
+	return stmt
+}
+
+// parseIf parses an if statement without the semicolon after it, as an "else
+// if" is followed by the semicolon of the first if.
+func (p *Parser) parseIf() *ast.IfStatement {
 	stmt := &ast.IfStatement{Token: p.curToken}
 
 	p.nextToken()
 
+	p.noCompositeLit = true
 	stmt.Condition = p.parseExpression(Lowest)
+	p.noCompositeLit = false
 
 	if !p.expectPeek(token.Lbrace) {
 		return nil
@@ -395,15 +617,21 @@ func (p *Parser) parseIfStatement() *ast.IfStatement {
 	if p.peekTokenIs(token.Else) {
 		p.nextToken()
 
-		if !p.expectPeek(token.Lbrace) {
+		if !p.expectPeek(token.Lbrace, token.If) {
 			return nil
 		}
 
-		stmt.Alternative = p.parseBlockStatement()
-	}
+		if p.curTokenIs(token.If) {
+			alternative := p.parseIf()
+			if alternative == nil {
+				return nil
+			}
+			stmt.Alternative = alternative
 
-	if !p.expectSemi() {
-		return nil
+			return stmt
+		}
+
+		stmt.Alternative = p.parseBlockStatement()
 	}
 
 	return stmt
@@ -414,169 +642,207 @@ func (p *Parser) parseForStatement() *ast.ForStatement {
 
 	p.nextToken()
 
-	if p.curTokenIs(token.Var) {
-		stmt.Init = p.parseVarStatement()
-	} else if p.curTokenIs(token.Ident) && p.peekTokenIs(token.Assign) {
-		stmt.Init = p.parseAssignStatement()
-	} else {
-		// TODO: maybe allow that the init phase can be omitted.
-		p.errorf("The initialisation in a for statement can either be a var statement or an assign statement.")
-		return nil
+	p.noCompositeLit = true
+
+	switch {
+	case p.curTokenIs(token.Lbrace):
+		// A loop without a condition loops forever e.g. "for { ... }".
+	case p.curTokenIs(token.Var), p.curTokenIs(token.Semicolon),
+		p.curTokenIs(token.Ident) && p.peekTokenIs(token.Assign),
+		p.curTokenIs(token.Ident) && p.peekTokenIs(token.Define):
+		if !p.parseForClauses(stmt) {
+			return nil
+		}
+	default:
+		// A loop with only a condition e.g. "for x < 10 { ... }".
+		stmt.Condition = p.parseExpression(Lowest)
+
+		if !p.expectPeek(token.Lbrace) {
+			return nil
+		}
 	}
 
+	p.noCompositeLit = false
+
+	stmt.Body = p.parseBlockStatement()
+
 	if !p.expectSemi() {
 		return nil
 	}
 
-	p.nextToken() // advance beyond ;
+	return stmt
+}
 
-	stmt.Condition = p.parseExpression(Lowest)
+// parseForClauses parses the init, the condition and the next of a for
+// statement e.g. "var i int = 0; i < 10; i = i + 1", where each of them can be
+// omitted. It stops at the "{" of the body.
+func (p *Parser) parseForClauses(stmt *ast.ForStatement) bool {
+	if !p.curTokenIs(token.Semicolon) {
+		switch {
+		case p.curTokenIs(token.Var):
+			init := p.parseVarStatement()
+			if init == nil {
+				return false
+			}
+			stmt.Init = init
+		case p.peekTokenIs(token.Define):
+			id := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 
-	if !p.expectSemi() {
-		return nil
+			init := p.parseShortVarStatement([]ast.Expression{id})
+			if init == nil {
+				return false
+			}
+			stmt.Init = init
+		default:
+			stmt.Init = p.parseAssignStatement()
+		}
+
+		if !p.expectPeek(token.Semicolon) {
+			return false
+		}
 	}
 
 	p.nextToken() // advance beyond ;
 
-	if p.curTokenIs(token.Ident) && p.peekTokenIs(token.Assign) {
-		stmt.Next = p.parseAssignStatement()
-	} else {
-		// TODO: maybe allow that the next phase can be omitted.
-		p.errorf("The next in a for statement can only be an assign statement.")
-		return nil
+	if !p.curTokenIs(token.Semicolon) {
+		stmt.Condition = p.parseExpression(Lowest)
+
+		if !p.expectPeek(token.Semicolon) {
+			return false
+		}
 	}
 
-	if !p.expectPeek(token.Lbrace) {
-		return nil
+	p.nextToken() // advance beyond ;
+
+	if p.curTokenIs(token.Lbrace) {
+		return true
 	}
 
-	stmt.Body = p.parseBlockStatement()
+	next := p.parseSimpleStatement()
+	if next == nil {
+		return false
+	}
 
-	if !p.expectSemi() {
-		return nil
+	if _, ok := next.(*ast.VarStatement); ok {
+		p.errorf("cannot declare in the next of a for statement")
+		return false
 	}
+	stmt.Next = next
 
-	return stmt
+	return p.expectPeek(token.Lbrace)
 }
 
-func (p *Parser) parseFuncStatement() *ast.FuncStatement {
-	stmt := &ast.FuncStatement{Token: p.curToken}
-
-	if !p.expectPeek(token.Ident) {
-		return nil
-	}
+// parseLabeledStatement parses a for statement or a switch statement with a
+// label e.g. "outer: for ...".
+func (p *Parser) parseLabeledStatement() ast.Statement {
+	label := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 
-	stmt.Name = &ast.Identifier{
-		Token: p.curToken,
-		Value: p.curToken.Literal,
-	}
+	p.nextToken() // ":"
 
-	if !p.expectPeek(token.Lparen) {
+	if !p.expectPeek(token.For, token.Switch) {
 		return nil
 	}
 
-	stmt.Signature = p.parseFuncType()
+	if p.curTokenIs(token.Switch) {
+		stmt := p.parseSwitchStatement()
+		if stmt == nil {
+			return nil
+		}
+		stmt.Label = label
 
-	if p.peekTokenIs(token.Semicolon) {
-		p.nextToken()
 		return stmt
 	}
 
-	if !p.expectPeek(token.Lbrace) {
-		return nil
-	}
-
-	stmt.Body = p.parseBlockStatement()
-
-	if !p.expectSemi() {
+	stmt := p.parseForStatement()
+	if stmt == nil {
 		return nil
 	}
+	stmt.Label = label
 
 	return stmt
 }
 
-func (p *Parser) parseFuncType() *ast.FuncType {
-	ft := &ast.FuncType{Token: p.curToken}
-	ft.Parameter = p.parseFuncParameter()
-	if p.peekTokenIs(token.IntType, token.FloatType, token.StringType, token.BoolType, token.Ident) {
-		p.nextToken() // advance to type
+func (p *Parser) parseSwitchStatement() *ast.SwitchStatement {
+	stmt := &ast.SwitchStatement{Token: p.curToken}
+
+	p.nextToken()
+
+	if !p.curTokenIs(token.Lbrace) {
+		p.noCompositeLit = true
+		stmt.Tag = p.parseExpression(Lowest)
+		p.noCompositeLit = false
 
-		ft.Result = &ast.BasicType{Token: p.curToken}
+		if !p.expectPeek(token.Lbrace) {
+			return nil
+		}
 	}
 
-	if p.peekTokenIs(token.Func) {
-		p.nextToken() // func
-		p.nextToken() // "("
-		ft.Result = p.parseFuncType()
+	p.nextToken() // advance beyond "{"
+
+	for p.curTokenIs(token.Case) || p.curTokenIs(token.Default) {
+		clause := p.parseCaseClause()
+		if clause == nil {
+			return nil
+		}
+
+		stmt.Cases = append(stmt.Cases, clause)
 	}
 
-	return ft
-}
+	if !p.curTokenIs(token.Rbrace) {
+		p.error("expected: 'case', 'default' or '}', got: '" + p.curToken.Literal + "'")
+		return nil
+	}
 
-func (p *Parser) parseFuncParameter() *ast.Identifier {
-	// Early return if there is no parameter.
-	if p.peekTokenIs(token.Rparen) {
-		p.nextToken()
+	if !p.expectSemi() {
 		return nil
 	}
 
-	p.nextToken()
+	return stmt
+}
 
-	var id ast.Identifier
+// parseCaseClause parses a case of a switch statement, whose statements end at
+// the next case or at the end of the switch statement.
+func (p *Parser) parseCaseClause() *ast.CaseClause {
+	clause := &ast.CaseClause{Token: p.curToken}
 
-	if p.curTokenIs(token.Ident) {
-		id.Token = p.curToken
-		id.Value = p.curToken.Literal
+	if p.curTokenIs(token.Case) {
 		p.nextToken()
 
-		// leave early in case where the function protoype is:
-		// type human struct {}
-		// func testHuman(human)
-		//					^
-		//					|
-		//				identifier without
-		//				type as the identifier is a struct type.
-		if p.curTokenIs(token.Rparen) {
-			id.Tnode = &ast.BasicType{Token: id.Token}
-			return &id
-		}
-	} else {
-		// If the function prototype does not give the parameter a name e.g.
-		// "func test(int)" then put in the blank '_' name for it.
-		id.Token = token.Token{
-			Type:    token.Blank,
-			Literal: string(token.Blank),
+		clause.Values = p.parseExpressionList()
+		if clause.Values == nil {
+			return nil
 		}
-		id.Value = string(token.Blank)
 	}
 
-	if p.curTokenIs(token.IntType, token.FloatType, token.StringType, token.BoolType, token.Ident) {
-		id.Tnode = &ast.BasicType{Token: p.curToken}
-	} else if p.curTokenIs(token.Func) {
-		p.nextToken() // advance to '('
-		id.Tnode = p.parseFuncType()
-	} else {
+	if !p.expectPeek(token.Colon) {
 		return nil
 	}
 
-	if !p.expectPeek(token.Rparen) {
-		return nil
+	clause.Body = &ast.BlockStatement{Token: p.curToken}
+
+	p.nextToken() // advance beyond ":"
+
+	for !p.curTokenIs(token.Case) && !p.curTokenIs(token.Default) &&
+		!p.curTokenIs(token.Rbrace) && !p.curTokenIs(token.Eof) {
+		stmt := p.parseStatement()
+		if stmt != nil {
+			clause.Body.Statements = append(clause.Body.Statements, stmt)
+		}
+
+		p.nextToken()
 	}
 
-	return &id
+	return clause
 }
 
-func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
-	stmt := &ast.ReturnStatement{Token: p.curToken}
-
-	p.nextToken()
+func (p *Parser) parseBranchStatement() *ast.BranchStatement {
+	stmt := &ast.BranchStatement{Token: p.curToken}
 
-	if p.curTokenIs(token.Semicolon) {
-		return stmt
+	if p.peekTokenIs(token.Ident) {
+		p.nextToken()
+		stmt.Label = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 	}
 
-	stmt.Value = p.parseExpression(Lowest)
-
 	if !p.expectSemi() {
 		return nil
 	}
@@ -584,32 +850,365 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	return stmt
 }
 
-func (p *Parser) parseExpressionOrAssignStatement() ast.Statement {
-	// save this token for expression statement.
-	tok := p.curToken
-
-	expr := p.parseExpression(Lowest)
-	if expr == nil {
-		return nil
-	}
-
-	if p.peekTokenIs(token.Assign) {
-		stmt := &ast.AssignStatement{Name: expr}
-
-		p.nextToken() // advance to the "="
-		stmt.Token = p.curToken
+func (p *Parser) parseFuncStatement() *ast.FuncStatement {
+	stmt := &ast.FuncStatement{Token: p.curToken}
 
-		p.nextToken() // advance to the value
-		stmt.Value = p.parseExpression(Lowest)
+	if p.peekTokenIs(token.Lparen) {
+		p.nextToken() // advance to '('
 
-		if !p.expectSemi() {
+		stmt.Receiver = p.parseReceiver()
+		if stmt.Receiver == nil {
 			return nil
 		}
+	}
 
-		return stmt
+	if !p.expectPeek(token.Ident) {
+		return nil
 	}
 
-	stmt := &ast.ExpressionStatement{Token: tok, Expression: expr}
+	stmt.Name = &ast.Identifier{
+		Token: p.curToken,
+		Value: p.curToken.Literal,
+	}
+
+	if !p.expectPeek(token.Lparen) {
+		return nil
+	}
+
+	stmt.Signature = p.parseFuncType()
+
+	if p.peekTokenIs(token.Semicolon) {
+		p.nextToken()
+		return stmt
+	}
+
+	if !p.expectPeek(token.Lbrace) {
+		return nil
+	}
+
+	stmt.Body = p.parseBlockStatement()
+
+	if !p.expectSemi() {
+		return nil
+	}
+
+	return stmt
+}
+
+// parseReceiver parses the receiver of a method e.g. "(h human)" or
+// "(h *human)".
+func (p *Parser) parseReceiver() *ast.Identifier {
+	if !p.expectPeek(token.Ident) {
+		return nil
+	}
+
+	recv := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	p.nextToken() // advance to the type
+
+	recv.Tnode = p.parseType()
+	if recv.Tnode == nil {
+		return nil
+	}
+
+	if !p.expectPeek(token.Rparen) {
+		return nil
+	}
+
+	return recv
+}
+
+// parseType parses the type starting at the current token.
+func (p *Parser) parseType() ast.TypeNode {
+	switch {
+	case p.curTokenIs(token.IntType, token.FloatType, token.StringType, token.BoolType, token.ByteType, token.Ident):
+		return &ast.BasicType{Token: p.curToken}
+	case p.curTokenIs(token.Func):
+		if !p.expectPeek(token.Lparen) {
+			return nil
+		}
+
+		ft := p.parseFuncType()
+		if ft == nil {
+			return nil
+		}
+
+		return ft
+	case p.curTokenIs(token.Asterisk):
+		return p.parsePointerType()
+	case p.curTokenIs(token.Lbracket):
+		if p.peekTokenIs(token.Rbracket) {
+			return p.parseSliceType()
+		}
+
+		return p.parseArrayType()
+	default:
+		p.error("expected a type, got: " + "'" + string(p.curToken.Type) + "'")
+		return nil
+	}
+}
+
+// peekTypeStart checks whether the next token can start a type.
+func (p *Parser) peekTypeStart() bool {
+	return p.peekTokenIs(
+		token.IntType,
+		token.FloatType,
+		token.StringType,
+		token.BoolType,
+		token.ByteType,
+		token.Ident,
+		token.Func,
+		token.Lbracket,
+		token.Asterisk,
+	)
+}
+
+// parsePointerType parses a pointer type e.g. "*int".
+func (p *Parser) parsePointerType() ast.TypeNode {
+	pt := &ast.PointerType{Token: p.curToken}
+
+	p.nextToken() // advance to the element type
+
+	pt.Elem = p.parseType()
+	if pt.Elem == nil {
+		return nil
+	}
+
+	return pt
+}
+
+// parseSliceType parses a slice type e.g. "[]int".
+func (p *Parser) parseSliceType() ast.TypeNode {
+	st := &ast.SliceType{Token: p.curToken}
+
+	p.nextToken() // advance to "]"
+	p.nextToken() // advance to the element type
+
+	st.Elem = p.parseType()
+	if st.Elem == nil {
+		return nil
+	}
+
+	return st
+}
+
+// parseArrayType parses an array type e.g. "[3]int".
+func (p *Parser) parseArrayType() ast.TypeNode {
+	at := &ast.ArrayType{Token: p.curToken}
+
+	if !p.expectPeek(token.Int) {
+		return nil
+	}
+
+	at.Len = p.parseIntegerLiteral()
+	if at.Len == nil {
+		return nil
+	}
+
+	if !p.expectPeek(token.Rbracket) {
+		return nil
+	}
+
+	p.nextToken() // advance to the element type
+
+	at.Elem = p.parseType()
+	if at.Elem == nil {
+		return nil
+	}
+
+	return at
+}
+
+func (p *Parser) parseFuncType() *ast.FuncType {
+	ft := &ast.FuncType{Token: p.curToken}
+
+	parameters, ok := p.parseFuncParameters()
+	if !ok {
+		return nil
+	}
+	ft.Parameters = parameters
+
+	switch {
+	case p.peekTokenIs(token.Lparen):
+		p.nextToken() // advance to "("
+
+		ft.Result = p.parseFuncResults()
+		if ft.Result == nil {
+			return nil
+		}
+	case p.peekTypeStart():
+		p.nextToken() // advance to type
+
+		ft.Result = p.parseType()
+		if ft.Result == nil {
+			return nil
+		}
+	}
+
+	return ft
+}
+
+// parseFuncResults parses a parenthesized list of result types e.g.
+// "(int, float)". A list of a single type is just that type.
+func (p *Parser) parseFuncResults() ast.TypeNode {
+	tt := &ast.TupleType{Token: p.curToken}
+
+	for {
+		p.nextToken() // advance to type
+
+		t := p.parseType()
+		if t == nil {
+			return nil
+		}
+		tt.Types = append(tt.Types, t)
+
+		if !p.peekTokenIs(token.Comma) {
+			break
+		}
+		p.nextToken() // ","
+	}
+
+	if !p.expectPeek(token.Rparen) {
+		return nil
+	}
+
+	if len(tt.Types) == 1 {
+		return tt.Types[0]
+	}
+
+	return tt
+}
+
+// parseFuncParameters parses the parameter list of a function. Like Go, the
+// parameters are either all named or all unnamed, and consecutive named
+// parameters may share a type:
+//
+//	func test(int, float)
+//	func test(x int, y float)
+//	func test(x, y int)
+func (p *Parser) parseFuncParameters() ([]*ast.Identifier, bool) {
+	// Early return if there is no parameter.
+	if p.peekTokenIs(token.Rparen) {
+		p.nextToken()
+		return nil, true
+	}
+
+	// Each entry of the list is either a type, a name followed by a type,
+	// or a lone identifier, which is a type if the parameters are unnamed
+	// and a name otherwise.
+	type entry struct {
+		name  *token.Token
+		tnode ast.TypeNode
+	}
+
+	var entries []entry
+	named := false
+
+	for {
+		p.nextToken()
+
+		var e entry
+		if p.curTokenIs(token.Ident) && !p.peekTokenIs(token.Comma, token.Rparen) {
+			name := p.curToken
+			e.name = &name
+			named = true
+
+			p.nextToken() // advance to type
+		}
+
+		e.tnode = p.parseType()
+		if e.tnode == nil {
+			return nil, false
+		}
+
+		entries = append(entries, e)
+
+		if !p.peekTokenIs(token.Comma) {
+			break
+		}
+
+		p.nextToken() // the comma
+	}
+
+	if !p.expectPeek(token.Rparen) {
+		return nil, false
+	}
+
+	var parameters []*ast.Identifier
+
+	if !named {
+		// If the function prototype does not give the parameters a name e.g.
+		// "func test(int)" then put in the blank '_' name for them.
+		for _, e := range entries {
+			parameters = append(parameters, &ast.Identifier{
+				Token: token.Token{
+					Type:    token.Blank,
+					Literal: string(token.Blank),
+				},
+				Value: string(token.Blank),
+				Tnode: e.tnode,
+			})
+		}
+
+		return parameters, true
+	}
+
+	// Lone identifiers are names sharing the type of the next named entry.
+	var names []token.Token
+	for _, e := range entries {
+		if e.name == nil {
+			bt, ok := e.tnode.(*ast.BasicType)
+			if !ok || bt.Token.Type != token.Ident {
+				p.error("mixed named and unnamed parameters")
+				return nil, false
+			}
+
+			names = append(names, bt.Token)
+			continue
+		}
+
+		names = append(names, *e.name)
+		for _, name := range names {
+			parameters = append(parameters, &ast.Identifier{
+				Token: name,
+				Value: name.Literal,
+				Tnode: e.tnode,
+			})
+		}
+		names = nil
+	}
+
+	if len(names) != 0 {
+		p.error("mixed named and unnamed parameters")
+		return nil, false
+	}
+
+	return parameters, true
+}
+
+func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
+	stmt := &ast.ReturnStatement{Token: p.curToken}
+
+	p.nextToken()
+
+	if p.curTokenIs(token.Semicolon) {
+		return stmt
+	}
+
+	stmt.Values = p.parseExpressionList()
+
+	if !p.expectSemi() {
+		return nil
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseExpressionOrAssignStatement() ast.Statement {
+	stmt := p.parseSimpleStatement()
+	if stmt == nil {
+		return nil
+	}
 
 	if !p.expectSemi() {
 		return nil
@@ -618,6 +1217,108 @@ func (p *Parser) parseExpressionOrAssignStatement() ast.Statement {
 	return stmt
 }
 
+// assignOperators maps the token of an assignment operator to the operator of
+// the compound assignment.
+var assignOperators = map[token.TokenType]string{
+	token.PlusAssign:       "+",
+	token.MinusAssign:      "-",
+	token.AsteriskAssign:   "*",
+	token.SlashAssign:      "/",
+	token.PercentAssign:    "%",
+	token.AmpersandAssign:  "&",
+	token.PipeAssign:       "|",
+	token.CaretAssign:      "^",
+	token.AndNotAssign:     "&^",
+	token.ShiftLeftAssign:  "<<",
+	token.ShiftRightAssign: ">>",
+}
+
+// parseSimpleStatement parses an expression statement, an assignment, a short
+// variable declaration or an increment or decrement statement, but not the
+// semicolon after it.
+func (p *Parser) parseSimpleStatement() ast.Statement {
+	// save this token for expression statement.
+	tok := p.curToken
+
+	exprs := p.parseExpressionList()
+	if exprs == nil {
+		return nil
+	}
+
+	if p.peekTokenIs(token.Define) {
+		stmt := p.parseShortVarStatement(exprs)
+		if stmt == nil {
+			return nil
+		}
+
+		return stmt
+	}
+
+	if p.peekTokenIs(token.Assign) {
+		stmt := &ast.AssignStatement{Names: exprs}
+
+		p.nextToken() // advance to the "="
+		stmt.Token = p.curToken
+
+		p.nextToken() // advance to the value
+		stmt.Values = p.parseExpressionList()
+
+		return stmt
+	}
+
+	if len(exprs) != 1 {
+		p.errorf("expected 1 expression, got: %d", len(exprs))
+		return nil
+	}
+
+	if operator, ok := assignOperators[p.peekToken.Type]; ok {
+		stmt := &ast.AssignStatement{Names: exprs, Operator: operator}
+
+		p.nextToken() // advance to the assignment operator
+		stmt.Token = p.curToken
+
+		p.nextToken() // advance to the value
+		stmt.Values = p.parseExpressionList()
+
+		return stmt
+	}
+
+	if p.peekTokenIs(token.Increment) || p.peekTokenIs(token.Decrement) {
+		p.nextToken()
+
+		return &ast.IncDecStatement{Token: p.curToken, Name: exprs[0]}
+	}
+
+	return &ast.ExpressionStatement{Token: tok, Expression: exprs[0]}
+}
+
+// parseShortVarStatement parses a short variable declaration e.g. "x := 2",
+// where the current token is the last of the names.
+func (p *Parser) parseShortVarStatement(exprs []ast.Expression) *ast.VarStatement {
+	stmt := &ast.VarStatement{}
+
+	for _, e := range exprs {
+		id, ok := e.(*ast.Identifier)
+		if !ok || id.Token.Type != token.Ident {
+			p.errorf("non-name %s on left side of :=", e)
+			return nil
+		}
+
+		stmt.Names = append(stmt.Names, id)
+	}
+
+	p.nextToken() // advance to the ":="
+	stmt.Token = p.curToken
+
+	p.nextToken() // advance to the value
+	stmt.Values = p.parseExpressionList()
+	if stmt.Values == nil {
+		return nil
+	}
+
+	return stmt
+}
+
 func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 	stmt := &ast.ExpressionStatement{Token: p.curToken}
 	stmt.Expression = p.parseExpression(Lowest)
@@ -629,6 +1330,26 @@ func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 	return stmt
 }
 
+// parseExpressionList parses one or more expressions separated by commas.
+func (p *Parser) parseExpressionList() []ast.Expression {
+	var exprs []ast.Expression
+
+	for {
+		expr := p.parseExpression(Lowest)
+		if expr == nil {
+			return nil
+		}
+		exprs = append(exprs, expr)
+
+		if !p.peekTokenIs(token.Comma) {
+			return exprs
+		}
+
+		p.nextToken() // ","
+		p.nextToken() // advance to the next expression
+	}
+}
+
 // parseExpression is the heart of the Pratt parsing.
 func (p *Parser) parseExpression(precedence int) ast.Expression {
 	prefix := p.prefixParseFuncs[p.curToken.Type]
@@ -662,24 +1383,40 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 const (
 	_ int = iota
 	Lowest
+	Or      // ||
+	And     // &&
 	Equals  // ==
-	Less    // <
-	Sum     // +
-	Product // *
-	Call    // (
+	Less    // < <= > >=
+	Sum     // + - | ^
+	Product // * / % << >> & &^
+	Prefix  // &X, *X, !X, -X or +X
+	Call    // ( or [
 	Period  // .
 )
 
 var precedences = map[token.TokenType]int{
-	token.Equal:    Equals,
-	token.NotEqual: Equals,
-	token.LessThan: Less,
-	token.Plus:     Sum,
-	token.Minus:    Sum,
-	token.Asterisk: Product,
-	token.Slash:    Product,
-	token.Lparen:   Call,
-	token.Period:   Period,
+	token.Or:           Or,
+	token.And:          And,
+	token.Equal:        Equals,
+	token.NotEqual:     Equals,
+	token.LessThan:     Less,
+	token.LessEqual:    Less,
+	token.GreaterThan:  Less,
+	token.GreaterEqual: Less,
+	token.Plus:         Sum,
+	token.Minus:        Sum,
+	token.Pipe:         Sum,
+	token.Caret:        Sum,
+	token.Asterisk:     Product,
+	token.Slash:        Product,
+	token.Percent:      Product,
+	token.ShiftLeft:    Product,
+	token.ShiftRight:   Product,
+	token.Ampersand:    Product,
+	token.AndNot:       Product,
+	token.Lparen:       Call,
+	token.Lbracket:     Call,
+	token.Period:       Period,
 }
 
 func (p *Parser) curPrecedence() int {
@@ -727,7 +1464,109 @@ func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
 	}
 
 	p.nextToken() // advance to the first argument.
-	expression.Argument = p.parseExpression(Lowest)
+
+	// A composite literal is allowed as an argument even in the header of an
+	// if or for statement.
+	noCompositeLit := p.noCompositeLit
+	p.noCompositeLit = false
+	expression.Arguments = p.parseExpressionList()
+	p.noCompositeLit = noCompositeLit
+
+	if !p.expectPeek(token.Rparen) {
+		return nil
+	}
+
+	return expression
+}
+
+// parseIndexExpression parses either an index expression e.g. "a[i]" or a
+// slice expression e.g. "s[lo:hi]".
+func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
+	tok := p.curToken
+
+	var index ast.Expression
+	if !p.peekTokenIs(token.Colon) {
+		p.nextToken() // advance to the index
+		index = p.parseExpression(Lowest)
+		if index == nil {
+			return nil
+		}
+	}
+
+	if p.peekTokenIs(token.Colon) {
+		return p.parseSliceExpression(tok, left, index)
+	}
+
+	if !p.expectPeek(token.Rbracket) {
+		return nil
+	}
+
+	return &ast.IndexExpression{Token: tok, Left: left, Index: index}
+}
+
+// parseSliceExpression parses the rest of a slice expression starting at
+// the ":".
+func (p *Parser) parseSliceExpression(tok token.Token, left, low ast.Expression) ast.Expression {
+	expression := &ast.SliceExpression{Token: tok, Left: left, Low: low}
+
+	p.nextToken() // advance to ":"
+
+	if !p.peekTokenIs(token.Rbracket) {
+		p.nextToken() // advance to the high bound
+		expression.High = p.parseExpression(Lowest)
+		if expression.High == nil {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.Rbracket) {
+		return nil
+	}
+
+	return expression
+}
+
+// parseBuiltinExpression parses a call to a builtin function. The first
+// argument of make and new is a type.
+func (p *Parser) parseBuiltinExpression() ast.Expression {
+	expression := &ast.BuiltinExpression{Token: p.curToken}
+
+	if !p.expectPeek(token.Lparen) {
+		return nil
+	}
+
+	if expression.Token.Type == token.Make || expression.Token.Type == token.New {
+		p.nextToken() // advance to the type
+
+		expression.Tnode = p.parseType()
+		if expression.Tnode == nil {
+			return nil
+		}
+
+		if !p.peekTokenIs(token.Comma) {
+			if !p.expectPeek(token.Rparen) {
+				return nil
+			}
+
+			return expression
+		}
+
+		p.nextToken() // advance to ","
+	}
+
+	if p.peekTokenIs(token.Rparen) {
+		p.nextToken()
+		return expression
+	}
+
+	p.nextToken() // advance to the first argument.
+
+	// A composite literal is allowed as an argument even in the header of an
+	// if or for statement.
+	noCompositeLit := p.noCompositeLit
+	p.noCompositeLit = false
+	expression.Arguments = p.parseExpressionList()
+	p.noCompositeLit = noCompositeLit
 
 	if !p.expectPeek(token.Rparen) {
 		return nil
@@ -736,6 +1575,50 @@ func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
 	return expression
 }
 
+// parseConversionExpression parses a conversion to a basic type e.g. int(x).
+func (p *Parser) parseConversionExpression() ast.Expression {
+	expression := &ast.ConversionExpression{
+		Token: p.curToken,
+		Tnode: &ast.BasicType{Token: p.curToken},
+	}
+
+	if !p.expectPeek(token.Lparen) {
+		return nil
+	}
+
+	p.nextToken() // advance to the value
+
+	noCompositeLit := p.noCompositeLit
+	p.noCompositeLit = false
+	expression.Value = p.parseExpression(Lowest)
+	p.noCompositeLit = noCompositeLit
+
+	if expression.Value == nil {
+		return nil
+	}
+
+	if !p.expectPeek(token.Rparen) {
+		return nil
+	}
+
+	return expression
+}
+
+func (p *Parser) parsePrefixExpression() ast.Expression {
+	expression := &ast.PrefixExpression{
+		Token:    p.curToken,
+		Operator: p.curToken.Literal,
+	}
+
+	p.nextToken()
+	expression.Right = p.parseExpression(Prefix)
+	if expression.Right == nil {
+		return nil
+	}
+
+	return expression
+}
+
 func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	expression := &ast.InfixExpression{
 		Token:    p.curToken,
@@ -753,8 +1636,15 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 func (p *Parser) parseGroupedExpression() ast.Expression {
 	p.nextToken() // advance beyound the "("
 
+	// A composite literal is allowed inside the parentheses even in the
+	// header of an if or for statement.
+	noCompositeLit := p.noCompositeLit
+	p.noCompositeLit = false
+
 	exp := p.parseExpression(Lowest)
 
+	p.noCompositeLit = noCompositeLit
+
 	if !p.expectPeek(token.Rparen) {
 		return nil
 	}
@@ -799,6 +1689,95 @@ func (p *Parser) parseBoolLiteral() ast.Expression {
 	return &ast.BoolLiteral{Token: p.curToken, Value: p.curTokenIs(token.True)}
 }
 
+func (p *Parser) parseNilLiteral() ast.Expression {
+	return &ast.NilLiteral{Token: p.curToken}
+}
+
+func (p *Parser) parseIota() ast.Expression {
+	return &ast.Iota{Token: p.curToken}
+}
+
 func (p *Parser) parseIdentifier() ast.Expression {
+	if p.peekTokenIs(token.Lbrace) && !p.noCompositeLit {
+		return p.parseCompositeLiteral()
+	}
+
 	return &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 }
+
+// parseCompositeLiteral parses a struct literal of the named type, where the
+// values are either all keyed or all positional e.g. "human{age: 3}".
+func (p *Parser) parseCompositeLiteral() ast.Expression {
+	lit := &ast.CompositeLiteral{Tnode: &ast.BasicType{Token: p.curToken}}
+
+	p.nextToken() // advance to '{'
+	lit.Token = p.curToken
+
+	// Composite literals are allowed again inside the braces.
+	noCompositeLit := p.noCompositeLit
+	p.noCompositeLit = false
+	defer func() { p.noCompositeLit = noCompositeLit }()
+
+	for !p.peekTokenIs(token.Rbrace) {
+		p.nextToken() // advance to the key or the value
+
+		var key *ast.Identifier
+		if p.curTokenIs(token.Ident) && p.peekTokenIs(token.Colon) {
+			key = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+			p.nextToken() // advance to ':'
+			p.nextToken() // advance to the value
+		}
+
+		value := p.parseExpression(Lowest)
+		if value == nil {
+			return nil
+		}
+
+		lit.Keys = append(lit.Keys, key)
+		lit.Values = append(lit.Values, value)
+
+		if !p.peekTokenIs(token.Comma) {
+			break
+		}
+
+		p.nextToken() // advance to ','
+	}
+
+	if !p.expectPeek(token.Rbrace) {
+		return nil
+	}
+
+	return lit
+}
+
+// parseFuncLiteral parses a function literal e.g.
+// "func(x int) int { return x + n }".
+func (p *Parser) parseFuncLiteral() ast.Expression {
+	lit := &ast.FuncLiteral{Token: p.curToken}
+
+	if !p.expectPeek(token.Lparen) {
+		return nil
+	}
+
+	lit.Signature = p.parseFuncType()
+	if lit.Signature == nil {
+		return nil
+	}
+
+	if !p.expectPeek(token.Lbrace) {
+		return nil
+	}
+
+	// Composite literals are allowed again inside the body.
+	noCompositeLit := p.noCompositeLit
+	p.noCompositeLit = false
+	defer func() { p.noCompositeLit = noCompositeLit }()
+
+	lit.Body = p.parseBlockStatement()
+	if lit.Body == nil {
+		return nil
+	}
+
+	return lit
+}
@@ -86,12 +86,15 @@ func TestVarStatement(t *testing.T) {
 		{"var x float", "x", "float", nil},
 		{"var x string", "x", "string", nil},
 		{"var x bool", "x", "bool", nil},
+		{"var x byte", "x", "byte", nil},
 		{"var x int = 1", "x", "int", 1},
 		{"var x float = 1.0", "x", "float", 1.0},
 		{`var x string = "Hello World"`, "x", "string", "Hello World"},
 		{"var x bool = true", "x", "bool", true},
 		{"var x bool = false", "x", "bool", false},
 		{"var x func(y int)", "x", "(", nil},
+		{"var x = 1", "x", "", 1},
+		{`var x = "Hello World"`, "x", "", "Hello World"},
 	}
 
 	for _, tt := range tests {
@@ -112,6 +115,125 @@ func TestVarStatement(t *testing.T) {
 	}
 }
 
+func TestCompoundAssignStatement(t *testing.T) {
+	tests := []struct {
+		input            string
+		expected         string
+		expectedOperator string
+	}{
+		{"x += 1", "x += 1", "+"},
+		{"x -= y * 2", "x -= (y * 2)", "-"},
+		{"p.x *= 2", "p.x *= 2", "*"},
+		{"xs[i] /= 2", "xs[i] /= 2", "/"},
+		{"x %= 3", "x %= 3", "%"},
+		{"x &= 3", "x &= 3", "&"},
+		{"x |= 3", "x |= 3", "|"},
+		{"x ^= 3", "x ^= 3", "^"},
+		{"x &^= 3", "x &^= 3", "&^"},
+		{"x <<= 3", "x <<= 3", "<<"},
+		{"x >>= 3", "x >>= 3", ">>"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+
+		program := p.ParseProgram()
+		checkParserError(t, p)
+
+		checkProgramLength(t, program)
+
+		stmt, ok := program.Statements[0].(*ast.AssignStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] not *ast.AssignStatement. got=%T", program.Statements[0])
+		}
+
+		if stmt.Operator != tt.expectedOperator {
+			t.Fatalf("stmt.Operator not %q. got=%q", tt.expectedOperator, stmt.Operator)
+		}
+
+		if stmt.String() != tt.expected {
+			t.Fatalf("expected=%q, got=%q", tt.expected, stmt.String())
+		}
+	}
+}
+
+func TestIncDecStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"i++", "i++"},
+		{"i--", "i--"},
+		{"p.x++", "p.x++"},
+		{"xs[0]--", "xs[0]--"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+
+		program := p.ParseProgram()
+		checkParserError(t, p)
+
+		checkProgramLength(t, program)
+
+		stmt, ok := program.Statements[0].(*ast.IncDecStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] not *ast.IncDecStatement. got=%T", program.Statements[0])
+		}
+
+		if stmt.String() != tt.expected {
+			t.Fatalf("expected=%q, got=%q", tt.expected, stmt.String())
+		}
+	}
+}
+
+func TestShortVarStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		names    int
+	}{
+		{"x := 1", "x := 1", 1},
+		{`x, y := 1, "a"`, `x, y := 1, "a"`, 2},
+		{"q, r := divmod(7, 2)", "q, r := divmod(7, 2)", 2},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+
+		program := p.ParseProgram()
+		checkParserError(t, p)
+
+		checkProgramLength(t, program)
+
+		stmt, ok := program.Statements[0].(*ast.VarStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] not *ast.VarStatement. got=%T", program.Statements[0])
+		}
+
+		if stmt.TokenLiteral() != ":=" {
+			t.Fatalf("stmt.TokenLiteral not %q. got=%q", ":=", stmt.TokenLiteral())
+		}
+
+		if len(stmt.Names) != tt.names {
+			t.Fatalf("stmt.Names does not contain %d names. got=%d", tt.names, len(stmt.Names))
+		}
+
+		for _, n := range stmt.Names {
+			if n.Tnode != nil {
+				t.Fatalf("name %s has a type. got=%s", n.Value, n.Tnode.TokenLiteral())
+			}
+		}
+
+		if stmt.String() != tt.expected {
+			t.Fatalf("expected=%q, got=%q", tt.expected, stmt.String())
+		}
+	}
+}
+
 func testVarStatement(t *testing.T, stmt ast.Statement, id string, varType string, value interface{}) {
 	t.Helper()
 
@@ -120,16 +242,24 @@ func testVarStatement(t *testing.T, stmt ast.Statement, id string, varType strin
 		t.Fatalf("stmt not *ast.VarStatement. got=%T", stmt)
 	}
 
-	if varStmt.Name.Value != id {
-		t.Fatalf("varStmt.Name.Value not %q, got=%q", id, varStmt.Name.Value)
+	if len(varStmt.Names) != 1 {
+		t.Fatalf("varStmt.Names does not contain 1 name. got=%d", len(varStmt.Names))
+	}
+
+	if varStmt.Names[0].Value != id {
+		t.Fatalf("varStmt.Names[0].Value not %q, got=%q", id, varStmt.Names[0].Value)
 	}
 
-	if varStmt.Name.Tnode.TokenLiteral() != varType {
-		t.Fatalf("varStmt.Name.Tnode is not %s, got=%s", varType, varStmt.Name.Tnode.TokenLiteral())
+	if varType == "" {
+		if varStmt.Names[0].Tnode != nil {
+			t.Fatalf("varStmt.Names[0].Tnode is not nil, got=%s", varStmt.Names[0].Tnode.TokenLiteral())
+		}
+	} else if varStmt.Names[0].Tnode.TokenLiteral() != varType {
+		t.Fatalf("varStmt.Names[0].Tnode is not %s, got=%s", varType, varStmt.Names[0].Tnode.TokenLiteral())
 	}
 
 	if value != nil {
-		val := varStmt.Value
+		val := varStmt.Values[0]
 		testLiteralExpression(t, val, value)
 	}
 }
@@ -171,6 +301,21 @@ func TestTypeStatement(t *testing.T) {
 				"age":  token.IntType,
 			},
 		},
+		{
+			input: `
+			type line struct{
+				a point
+				f func(int) int
+				next *line
+			}`,
+			expectedIdentifier: "line",
+			expectedType:       "struct",
+			expectedFields: map[string]token.TokenType{
+				"a":    token.Ident,
+				"f":    token.Lparen,
+				"next": token.Asterisk,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -211,16 +356,65 @@ func TestTypeStatement(t *testing.T) {
 				t.Fatalf("structType.Fields contains %q which was not expected", f.Value)
 			}
 
+			var got token.TokenType
 			switch v := f.Tnode.(type) {
 			case *ast.BasicType:
-				if ft != v.Token.Type {
-					t.Fatalf("structType.Fields.List[%d].Kind is not %s. got=%s", i, ft, v.Token.Type)
-				}
+				got = v.Token.Type
+			case *ast.FuncType:
+				got = v.Token.Type
+			case *ast.PointerType:
+				got = v.Token.Type
+			}
+
+			if ft != got {
+				t.Fatalf("structType.Fields.List[%d].Kind is not %s. got=%s", i, ft, got)
 			}
 		}
 	}
 }
 
+func TestNamedBasicType(t *testing.T) {
+	tests := []struct {
+		input              string
+		expectedIdentifier string
+		expectedType       token.TokenType
+	}{
+		{"type celsius float", "celsius", token.FloatType},
+		{"type id int", "id", token.IntType},
+		{"type name string", "name", token.StringType},
+		{"type flag bool", "flag", token.BoolType},
+		{"type small byte", "small", token.ByteType},
+		{"type temperature celsius", "temperature", token.Ident},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserError(t, p)
+
+		checkProgramLength(t, program)
+
+		typeStmt, ok := program.Statements[0].(*ast.TypeStatement)
+		if !ok {
+			t.Fatalf("stmt not *ast.TypeStatement. got=%T", program.Statements[0])
+		}
+
+		if typeStmt.Name.Value != tt.expectedIdentifier {
+			t.Fatalf("typeStmt.Name not %q. got=%q", tt.expectedIdentifier, typeStmt.Name.Value)
+		}
+
+		basicType, ok := typeStmt.Type.(*ast.BasicType)
+		if !ok {
+			t.Fatalf("typeStmt.Type is not an *ast.BasicType. got=%T", typeStmt.Type)
+		}
+
+		if basicType.Token.Type != tt.expectedType {
+			t.Fatalf("basicType.Token.Type is not %s. got=%s", tt.expectedType, basicType.Token.Type)
+		}
+	}
+}
+
 func TestSelector(t *testing.T) {
 	tests := []struct {
 		input              string
@@ -309,11 +503,18 @@ func testAssignStatement(t *testing.T, stmt ast.Statement, id string, value inte
 		t.Fatalf("stmt not *ast.AssignStatement, got=%T", stmt)
 	}
 
-	switch v := assignStmt.Name.(type) {
+	if len(assignStmt.Names) != 1 || len(assignStmt.Values) != 1 {
+		t.Fatalf(
+			"assignStmt does not assign 1 value to 1 name. got=%d names, %d values",
+			len(assignStmt.Names), len(assignStmt.Values),
+		)
+	}
+
+	switch v := assignStmt.Names[0].(type) {
 	case *ast.Identifier:
 		if v.Value != id {
 			t.Fatalf(
-				"assignStmt.Name.Value not %q, got=%q",
+				"assignStmt.Names[0].Value not %q, got=%q",
 				id, v.Value,
 			)
 		}
@@ -322,9 +523,9 @@ func testAssignStatement(t *testing.T, stmt ast.Statement, id string, value inte
 	}
 
 	if inf, ok := value.(infix); ok {
-		testInfixExpression(t, assignStmt.Value, inf.lhs, inf.operator, inf.rhs)
+		testInfixExpression(t, assignStmt.Values[0], inf.lhs, inf.operator, inf.rhs)
 	} else {
-		testLiteralExpression(t, assignStmt.Value, value)
+		testLiteralExpression(t, assignStmt.Values[0], value)
 	}
 }
 
@@ -464,16 +665,69 @@ func TestIfStatementWithElse(t *testing.T) {
 
 		testIdentifier(t, consequence.Expression, "x")
 
-		alternative, ok := ifStmt.Alternative.Statements[0].(*ast.ExpressionStatement)
+		block, ok := ifStmt.Alternative.(*ast.BlockStatement)
+		if !ok {
+			t.Fatalf("ifStmt.Alternative is not an *ast.BlockStatement. got=%T",
+				ifStmt.Alternative)
+		}
+
+		alternative, ok := block.Statements[0].(*ast.ExpressionStatement)
 		if !ok {
 			t.Fatalf("ifstmt.Consequence.Statements[0] is not an *ast.ExpressionStatement. got=%T",
-				ifStmt.Alternative.Statements[0])
+				block.Statements[0])
 		}
 
 		testIdentifier(t, alternative.Expression, "y")
 	}
 }
 
+func TestIfStatementWithElseIf(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			input:    `if x < y { x } else if x > y { y } else { z }`,
+			expected: "if(x < y) {x}else if(x > y) {y}else {z}",
+		},
+		{
+			input: `if x < y {
+				x
+			} else if x > y {
+				y
+			}`,
+			expected: "if(x < y) {x}else if(x > y) {y}",
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserError(t, p)
+
+		checkProgramLength(t, program)
+
+		ifStmt, ok := program.Statements[0].(*ast.IfStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not an *ast.IfStatement. got=%T",
+				program.Statements[0])
+		}
+
+		if ifStmt.String() != tt.expected {
+			t.Fatalf("expected=%q, got=%q", tt.expected, ifStmt.String())
+		}
+
+		alternative, ok := ifStmt.Alternative.(*ast.IfStatement)
+		if !ok {
+			t.Fatalf("ifStmt.Alternative is not an *ast.IfStatement. got=%T",
+				ifStmt.Alternative)
+		}
+
+		testInfixExpression(t, alternative.Condition, "x", ">", "y")
+	}
+}
+
 func TestForStatement(t *testing.T) {
 	input := `for var i int = 0; i < 2; i = i + 1 { print i; }`
 
@@ -505,15 +759,23 @@ func TestForStatement(t *testing.T) {
 	}
 }
 
-func TestReturnStatement(t *testing.T) {
+func TestForStatementClauses(t *testing.T) {
 	tests := []struct {
-		input         string
-		expectedValue interface{}
+		input             string
+		expected          string
+		expectedInit      bool
+		expectedCondition bool
+		expectedNext      bool
 	}{
-		{
-			input:         "return 5",
-			expectedValue: 5,
-		},
+		{"for { print 1 }", "for {print 1}", false, false, false},
+		{"for i < 2 { print 1 }", "for (i < 2) {print 1}", false, true, false},
+		{"for ; i < 2; { print 1 }", "for (i < 2) {print 1}", false, true, false},
+		{"for i = 0; ; { print 1 }", "for i = 0; ; {print 1}", true, false, false},
+		{"for ; ; i = i + 1 { print 1 }", "for ; ; i = (i + 1){print 1}", false, false, true},
+		{"for var i int = 0; i < 2; { print i }", "for var i = 0; (i < 2); {print i}", true, true, false},
+		{"for i := 0; i < 2; i = i + 1 { print i }", "for i := 0; (i < 2); i = (i + 1){print i}", true, true, true},
+		{"for i := 0; i < 2; i++ { print i }", "for i := 0; (i < 2); i++{print i}", true, true, true},
+		{"for ; ; i += 2 { print i }", "for ; ; i += 2{print i}", false, false, true},
 	}
 
 	for _, tt := range tests {
@@ -523,132 +785,857 @@ func TestReturnStatement(t *testing.T) {
 		checkParserError(t, p)
 		checkProgramLength(t, program)
 
-		stmt := program.Statements[0]
-		returnStmt, ok := stmt.(*ast.ReturnStatement)
+		forStmt, ok := program.Statements[0].(*ast.ForStatement)
 		if !ok {
-			t.Fatalf("stmt not an *ast.ReturnStatement. got=%T", stmt)
+			t.Fatalf("program.Statements[0] is not an *ast.ForStatement. got=%T",
+				program.Statements[0])
+		}
+
+		if forStmt.String() != tt.expected {
+			t.Fatalf("expected=%q, got=%q", tt.expected, forStmt.String())
+		}
+
+		if (forStmt.Init != nil) != tt.expectedInit {
+			t.Fatalf("forStmt.Init is wrong. got=%v", forStmt.Init)
+		}
+
+		if (forStmt.Condition != nil) != tt.expectedCondition {
+			t.Fatalf("forStmt.Condition is wrong. got=%v", forStmt.Condition)
 		}
 
-		testLiteralExpression(t, returnStmt.Value, tt.expectedValue)
+		if (forStmt.Next != nil) != tt.expectedNext {
+			t.Fatalf("forStmt.Next is wrong. got=%v", forStmt.Next)
+		}
 	}
 }
 
-func TestFuncStatement(t *testing.T) {
+func TestBranchStatement(t *testing.T) {
+	input := `
+	outer: for var i int = 0; i < 2; i = i + 1 {
+		for var j int = 0; j < 2; j = j + 1 {
+			continue outer
+			break
+		}
+	}`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserError(t, p)
+	checkProgramLength(t, program)
+
+	forStmt, ok := program.Statements[0].(*ast.ForStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not an *ast.ForStatement. got=%T",
+			program.Statements[0])
+	}
+
+	if forStmt.Label == nil || forStmt.Label.Value != "outer" {
+		t.Fatalf("forStmt.Label is not %q. got=%v", "outer", forStmt.Label)
+	}
+
+	inner, ok := forStmt.Body.Statements[0].(*ast.ForStatement)
+	if !ok {
+		t.Fatalf("forStmt.Body.Statements[0] is not an *ast.ForStatement. got=%T",
+			forStmt.Body.Statements[0])
+	}
+
+	if inner.Label != nil {
+		t.Fatalf("inner.Label is not nil. got=%v", inner.Label)
+	}
+
 	tests := []struct {
-		input              string
-		expectedName       string
-		expectedParamValue string
-		expectedParamType  string
-		expectedResult     interface{}
+		expected      string
+		expectedLabel string
+	}{
+		{"continue outer", "outer"},
+		{"break", ""},
+	}
+
+	for i, tt := range tests {
+		bs, ok := inner.Body.Statements[i].(*ast.BranchStatement)
+		if !ok {
+			t.Fatalf("inner.Body.Statements[%d] is not an *ast.BranchStatement. got=%T",
+				i, inner.Body.Statements[i])
+		}
+
+		if bs.String() != tt.expected {
+			t.Fatalf("expected=%q, got=%q", tt.expected, bs.String())
+		}
+
+		if tt.expectedLabel == "" {
+			if bs.Label != nil {
+				t.Fatalf("bs.Label is not nil. got=%v", bs.Label)
+			}
+			continue
+		}
+
+		if bs.Label == nil || bs.Label.Value != tt.expectedLabel {
+			t.Fatalf("bs.Label is not %q. got=%v", tt.expectedLabel, bs.Label)
+		}
+	}
+}
+
+func TestSwitchStatement(t *testing.T) {
+	tests := []struct {
+		input         string
+		expected      string
+		expectedTag   string
+		expectedCases int
 	}{
 		{
-			input:              `func greet(int)`,
-			expectedName:       "greet",
-			expectedParamValue: "_",
-			expectedParamType:  "int",
-			expectedResult:     nil,
-		},
-		{
-			input:              `func greet() {}`,
-			expectedName:       "greet",
-			expectedParamValue: "",
-			expectedParamType:  "",
-			expectedResult:     nil,
-		},
-		{
-			input:              `func greet(s string) { }`,
-			expectedName:       "greet",
-			expectedParamValue: "s",
-			expectedParamType:  "string",
-			expectedResult:     nil,
-		},
-		{
-			input:              `func greet(s string) int { }`,
-			expectedName:       "greet",
-			expectedParamValue: "s",
-			expectedParamType:  "string",
-			expectedResult:     "int",
-		},
-		{
-			input:              `func greet(s string) float { }`,
-			expectedName:       "greet",
-			expectedParamValue: "s",
-			expectedParamType:  "string",
-			expectedResult:     "float",
-		},
-		{
-			input:              `func greet(s string) string { }`,
-			expectedName:       "greet",
-			expectedParamValue: "s",
-			expectedParamType:  "string",
-			expectedResult:     "string",
+			input: `
+			switch x {
+			case 1, 2:
+				print 1
+			default:
+				print 2
+			}`,
+			expected:      "switch x {case 1, 2: print 1; default: print 2}",
+			expectedTag:   "x",
+			expectedCases: 2,
 		},
 		{
-			input:              `func greet(s string) bool { }`,
-			expectedName:       "greet",
-			expectedParamValue: "s",
-			expectedParamType:  "string",
-			expectedResult:     "bool",
+			input: `
+			switch {
+			case x < 3:
+			case x > 5:
+				print x
+				print x
+			}`,
+			expected:      "switch {case (x < 3):; case (x > 5): print x; print x}",
+			expectedTag:   "",
+			expectedCases: 2,
 		},
 		{
-			input:              `func greet(s string) human { }`,
-			expectedName:       "greet",
-			expectedParamValue: "s",
-			expectedParamType:  "string",
-			expectedResult:     "human",
+			input: `
+			outer: switch x {
+			}`,
+			expected:      "outer: switch x {}",
+			expectedTag:   "x",
+			expectedCases: 0,
 		},
 	}
 
-	for i, tt := range tests {
-		t.Logf("test[%d]", i)
+	for _, tt := range tests {
 		l := lexer.New(tt.input)
 		p := New(l)
 		program := p.ParseProgram()
 		checkParserError(t, p)
 		checkProgramLength(t, program)
 
-		funcStmt, ok := program.Statements[0].(*ast.FuncStatement)
+		stmt, ok := program.Statements[0].(*ast.SwitchStatement)
 		if !ok {
-			t.Fatalf("program.Statements[0] is not an *ast.FuncStatement. got=%T",
+			t.Fatalf("program.Statements[0] is not an *ast.SwitchStatement. got=%T",
 				program.Statements[0])
 		}
 
-		if funcStmt.TokenLiteral() != "func" {
-			t.Fatalf("funcStmt.TokenLiteral not %q. got=%q", "func", funcStmt.TokenLiteral())
-		}
-
-		if funcStmt.Name.Value != tt.expectedName {
-			t.Fatalf("funcStmt.Name is not %q, got=%q", tt.expectedName, funcStmt.Name)
+		if stmt.String() != tt.expected {
+			t.Fatalf("expected=%q, got=%q", tt.expected, stmt.String())
 		}
 
-		if funcStmt.Signature.Parameter != nil {
-			if funcStmt.Signature.Parameter.Value != tt.expectedParamValue {
-				t.Fatalf(
-					"funcStmt.Parameter.Value is not %q, got=%q",
-					tt.expectedParamValue,
-					funcStmt.Signature.Parameter.Value,
-				)
-			}
-
-			if funcStmt.Signature.Parameter.Tnode.TokenLiteral() != tt.expectedParamType {
-				t.Fatalf(
-					"funcStmt.Parameter.Ttoken.Literal is not %q, got=%q",
-					tt.expectedParamType,
-					funcStmt.Signature.Parameter.Tnode.TokenLiteral(),
-				)
+		if tt.expectedTag == "" {
+			if stmt.Tag != nil {
+				t.Fatalf("stmt.Tag is not nil. got=%v", stmt.Tag)
 			}
+		} else if stmt.Tag == nil || stmt.Tag.String() != tt.expectedTag {
+			t.Fatalf("stmt.Tag is not %q. got=%v", tt.expectedTag, stmt.Tag)
 		}
 
-		if funcStmt.Signature.Result == nil {
-			if tt.expectedResult == nil {
-				continue
-			}
-
-			t.Fatalf(
-				"funcStmt.Result is not %q, got=%q",
-				tt.expectedResult,
-				funcStmt.Signature.Result,
-			)
+		if len(stmt.Cases) != tt.expectedCases {
+			t.Fatalf("stmt.Cases does not contain %d cases. got=%d",
+				tt.expectedCases, len(stmt.Cases))
+		}
+	}
+}
+
+func TestReturnStatement(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedValue interface{}
+	}{
+		{
+			input:         "return 5",
+			expectedValue: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserError(t, p)
+		checkProgramLength(t, program)
+
+		stmt := program.Statements[0]
+		returnStmt, ok := stmt.(*ast.ReturnStatement)
+		if !ok {
+			t.Fatalf("stmt not an *ast.ReturnStatement. got=%T", stmt)
+		}
+
+		testLiteralExpression(t, returnStmt.Values[0], tt.expectedValue)
+	}
+}
+
+func TestMultipleValues(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			input:    "var q, r int = divmod(7, 2)",
+			expected: "var q, r = divmod(7, 2)",
+		},
+		{
+			input:    "var x, y int = 1, 2",
+			expected: "var x, y = 1, 2",
+		},
+		{
+			input:    "q, r = divmod(7, 2)",
+			expected: "q, r = divmod(7, 2)",
+		},
+		{
+			input:    "x, y = y + 1, x",
+			expected: "x, y = (y + 1), x",
+		},
+		{
+			input:    "return q, r",
+			expected: "return q, r",
+		},
+		{
+			input:    "func divmod(a int, b int) (int, int)",
+			expected: "func divmod(a, b) (int, int) ",
+		},
+		{
+			input:    "func divmod(a int, b int) (int)",
+			expected: "func divmod(a, b) int ",
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserError(t, p)
+		checkProgramLength(t, program)
+
+		got := program.String()
+		if got != tt.expected {
+			t.Fatalf("expected=%q, got=%q", tt.expected, got)
+		}
+	}
+}
+
+func TestArray(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			input:    "var a [3]int",
+			expected: "var a",
+		},
+		{
+			input:    "var a [2][3]float",
+			expected: "var a",
+		},
+		{
+			input:    "a[1] = 2",
+			expected: "a[1] = 2",
+		},
+		{
+			input:    "a[i + 1][j] = a[i] * 2",
+			expected: "a[(i + 1)][j] = (a[i] * 2)",
+		},
+		{
+			input:    "print a[1].x",
+			expected: "print a[1].x",
+		},
+		{
+			input:    "print f(a[0])[1]",
+			expected: "print f(a[0])[1]",
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserError(t, p)
+		checkProgramLength(t, program)
+
+		got := program.String()
+		if got != tt.expected {
+			t.Fatalf("expected=%q, got=%q", tt.expected, got)
+		}
+	}
+}
+
+func TestSlice(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			input:    "var s []int = make([]int, 2)",
+			expected: "var s = make([]int, 2)",
+		},
+		{
+			input:    "s = make([][2]float, n, 2 * n)",
+			expected: "s = make([][2]float, n, (2 * n))",
+		},
+		{
+			input:    "s = append(s, 1, x + 1)",
+			expected: "s = append(s, 1, (x + 1))",
+		},
+		{
+			input:    "print len(s) + cap(s)",
+			expected: "print (len(s) + cap(s))",
+		},
+		{
+			input:    "t = s[1:len(s)]",
+			expected: "t = s[1:len(s)]",
+		},
+		{
+			input:    "t = s[:2][1:]",
+			expected: "t = s[:2][1:]",
+		},
+		{
+			input:    "t = s[:]",
+			expected: "t = s[:]",
+		},
+		{
+			input:    "func f(s []int) []string",
+			expected: "func f(s) []string ",
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserError(t, p)
+		checkProgramLength(t, program)
+
+		got := program.String()
+		if got != tt.expected {
+			t.Fatalf("expected=%q, got=%q", tt.expected, got)
+		}
+	}
+}
+
+func TestPointer(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			input:    "var p *int = &x",
+			expected: "var p = (&x)",
+		},
+		{
+			input:    "*p = *p + 1",
+			expected: "(*p) = ((*p) + 1)",
+		},
+		{
+			input:    "print *p * 2",
+			expected: "print ((*p) * 2)",
+		},
+		{
+			input:    "p = &s.x",
+			expected: "p = (&s.x)",
+		},
+		{
+			input:    "var q **human = new(*human)",
+			expected: "var q = new(*human)",
+		},
+		{
+			input:    "p = nil",
+			expected: "p = nil",
+		},
+		{
+			input:    "type node struct { val int; next *node }",
+			expected: "type node struct{val;next}",
+		},
+		{
+			input:    "func f(p *int) *float",
+			expected: "func f(p) *float ",
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserError(t, p)
+		checkProgramLength(t, program)
+
+		got := program.String()
+		if got != tt.expected {
+			t.Fatalf("expected=%q, got=%q", tt.expected, got)
+		}
+	}
+}
+
+func TestCompositeLiteral(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			input:    `var h human = human{age: 3, name: "Bob"}`,
+			expected: `var h = human{age: 3, name: "Bob"}`,
+		},
+		{
+			input:    `h = human{3, "Bob"}`,
+			expected: `h = human{3, "Bob"}`,
+		},
+		{
+			input:    "l = line{a: point{1, 2}, b: point{}}",
+			expected: "l = line{a: point{1, 2}, b: point{}}",
+		},
+		{
+			input:    "print dist(point{\n\tx: 1,\n\ty: 2,\n})",
+			expected: "print dist(point{x: 1, y: 2})",
+		},
+		{
+			input:    "if p == (point{}) { print 1 }",
+			expected: "if(p == point{}) {print 1}",
+		},
+		{
+			input:    "if f(point{}) { print 1 }",
+			expected: "iff(point{}) {print 1}",
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserError(t, p)
+		checkProgramLength(t, program)
+
+		got := program.String()
+		if got != tt.expected {
+			t.Fatalf("expected=%q, got=%q", tt.expected, got)
+		}
+	}
+}
+
+func TestMethod(t *testing.T) {
+	tests := []struct {
+		input         string
+		expected      string
+		expectedLabel string
+	}{
+		{
+			input:         "func (h human) greet() string { return h.name }",
+			expected:      "func (h human) greet() string {return h.name}",
+			expectedLabel: "human.greet",
+		},
+		{
+			input:         "func (h *human) grow(years int) { h.age = h.age + years }",
+			expected:      "func (h *human) grow(years) {h.age = (h.age + years)}",
+			expectedLabel: "human.grow",
+		},
+		{
+			input:         "func greet() {}",
+			expected:      "func greet() {}",
+			expectedLabel: "greet",
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserError(t, p)
+		checkProgramLength(t, program)
+
+		got := program.String()
+		if got != tt.expected {
+			t.Fatalf("expected=%q, got=%q", tt.expected, got)
+		}
+
+		stmt, ok := program.Statements[0].(*ast.FuncStatement)
+		if !ok {
+			t.Fatalf("stmt not *ast.FuncStatement. got=%T", program.Statements[0])
+		}
+
+		if stmt.Label() != tt.expectedLabel {
+			t.Fatalf("stmt.Label() not %q. got=%q", tt.expectedLabel, stmt.Label())
+		}
+	}
+}
+
+func TestInterfaceType(t *testing.T) {
+	tests := []struct {
+		input           string
+		expected        string
+		expectedMethods []string
+	}{
+		{
+			input:           "type empty interface{}",
+			expected:        "type empty interface{}",
+			expectedMethods: nil,
+		},
+		{
+			input:           "type shape interface{area() float}",
+			expected:        "type shape interface{area() float}",
+			expectedMethods: []string{"area"},
+		},
+		{
+			input: `
+			type shape interface{
+				area() float
+				scale(x float)
+			}`,
+			expected:        "type shape interface{area() float;scale(x)}",
+			expectedMethods: []string{"area", "scale"},
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserError(t, p)
+		checkProgramLength(t, program)
+
+		got := program.String()
+		if got != tt.expected {
+			t.Fatalf("expected=%q, got=%q", tt.expected, got)
+		}
+
+		stmt, ok := program.Statements[0].(*ast.TypeStatement)
+		if !ok {
+			t.Fatalf("stmt not *ast.TypeStatement. got=%T", program.Statements[0])
+		}
+
+		it, ok := stmt.Type.(*ast.InterfaceType)
+		if !ok {
+			t.Fatalf("stmt.Type is not an *ast.InterfaceType. got=%T", stmt.Type)
+		}
+
+		if len(it.Methods) != len(tt.expectedMethods) {
+			t.Fatalf("it.Methods length is not %d. got=%d", len(tt.expectedMethods), len(it.Methods))
+		}
+
+		for i, m := range it.Methods {
+			if m.Value != tt.expectedMethods[i] {
+				t.Fatalf("it.Methods[%d] is not %q. got=%q", i, tt.expectedMethods[i], m.Value)
+			}
+
+			if _, ok := m.Tnode.(*ast.FuncType); !ok {
+				t.Fatalf("it.Methods[%d].Tnode is not an *ast.FuncType. got=%T", i, m.Tnode)
+			}
+		}
+	}
+}
+
+func TestFuncLiteral(t *testing.T) {
+	tests := []struct {
+		input              string
+		expected           string
+		expectedParameters []string
+	}{
+		{
+			input:              "print func() {}",
+			expected:           "print func() {}",
+			expectedParameters: nil,
+		},
+		{
+			input:              "print func(x int) int {return x + n}",
+			expected:           "print func(x) int {return (x + n)}",
+			expectedParameters: []string{"x"},
+		},
+		{
+			input:              "print func(a int, b float) (int, float) {return a, b}",
+			expected:           "print func(a, b) (int, float) {return a, b}",
+			expectedParameters: []string{"a", "b"},
+		},
+		{
+			input:              "print func() bool {return s{a: 1}.a == 1}",
+			expected:           "print func() bool {return (s{a: 1}.a == 1)}",
+			expectedParameters: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserError(t, p)
+		checkProgramLength(t, program)
+
+		got := program.String()
+		if got != tt.expected {
+			t.Fatalf("expected=%q, got=%q", tt.expected, got)
+		}
+
+		stmt, ok := program.Statements[0].(*ast.PrintStatement)
+		if !ok {
+			t.Fatalf("stmt not *ast.PrintStatement. got=%T", program.Statements[0])
+		}
+
+		fl, ok := stmt.Value.(*ast.FuncLiteral)
+		if !ok {
+			t.Fatalf("stmt.Value is not an *ast.FuncLiteral. got=%T", stmt.Value)
+		}
+
+		if len(fl.Signature.Parameters) != len(tt.expectedParameters) {
+			t.Fatalf("fl.Signature.Parameters length is not %d. got=%d", len(tt.expectedParameters), len(fl.Signature.Parameters))
+		}
+
+		for i, param := range fl.Signature.Parameters {
+			if param.Value != tt.expectedParameters[i] {
+				t.Fatalf("fl.Signature.Parameters[%d] is not %q. got=%q", i, tt.expectedParameters[i], param.Value)
+			}
+		}
+	}
+}
+
+func TestConversionExpression(t *testing.T) {
+	tests := []struct {
+		input        string
+		expected     string
+		expectedType string
+	}{
+		{"float(x)", "float(x)", "float"},
+		{"int(2.5 * x)", "int((2.5 * x))", "int"},
+		{"string(s[0])", "string(s[0])", "string"},
+		{"byte(65)", "byte(65)", "byte"},
+		{"bool(b)", "bool(b)", "bool"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserError(t, p)
+		checkProgramLength(t, program)
+
+		got := program.String()
+		if got != tt.expected {
+			t.Fatalf("expected=%q, got=%q", tt.expected, got)
+		}
+
+		exprStmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T",
+				program.Statements[0],
+			)
+		}
+
+		ce, ok := exprStmt.Expression.(*ast.ConversionExpression)
+		if !ok {
+			t.Fatalf("expr is not ast.ConversionExpression. got=%T", exprStmt.Expression)
+		}
+
+		if ce.Tnode.String() != tt.expectedType {
+			t.Fatalf("ce.Tnode is not %q. got=%q", tt.expectedType, ce.Tnode)
+		}
+	}
+}
+
+func TestConstStatement(t *testing.T) {
+	tests := []struct {
+		input         string
+		expected      string
+		expectedSpecs int
+	}{
+		{"const pi float = 3.14", "const pi float = 3.14", 1},
+		{"const a, b = 1, 2", "const a, b = 1, 2", 1},
+		{"const ( a = iota; b; c )", "const (a = iota; b; c)", 3},
+		{"const (\n\tkb = 1 << (10 * iota)\n\tmb\n)", "const (kb = (1 << (10 * iota)); mb)", 2},
+		{"const ()", "const ()", 0},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserError(t, p)
+		checkProgramLength(t, program)
+
+		got := program.String()
+		if got != tt.expected {
+			t.Fatalf("expected=%q, got=%q", tt.expected, got)
+		}
+
+		stmt, ok := program.Statements[0].(*ast.ConstStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ConstStatement. got=%T",
+				program.Statements[0],
+			)
+		}
+
+		if len(stmt.Specs) != tt.expectedSpecs {
+			t.Fatalf("stmt.Specs does not contain %d specs. got=%d", tt.expectedSpecs, len(stmt.Specs))
+		}
+
+		for i, spec := range stmt.Specs {
+			if spec.Iota != i {
+				t.Fatalf("spec.Iota is not %d. got=%d", i, spec.Iota)
+			}
+		}
+	}
+}
+
+func TestFuncStatement(t *testing.T) {
+	tests := []struct {
+		input               string
+		expectedName        string
+		expectedParamValues []string
+		expectedParamTypes  []string
+		expectedResult      interface{}
+	}{
+		{
+			input:               `func greet(int)`,
+			expectedName:        "greet",
+			expectedParamValues: []string{"_"},
+			expectedParamTypes:  []string{"int"},
+			expectedResult:      nil,
+		},
+		{
+			input:               `func greet() {}`,
+			expectedName:        "greet",
+			expectedParamValues: nil,
+			expectedParamTypes:  nil,
+			expectedResult:      nil,
+		},
+		{
+			input:               `func greet(s string) { }`,
+			expectedName:        "greet",
+			expectedParamValues: []string{"s"},
+			expectedParamTypes:  []string{"string"},
+			expectedResult:      nil,
+		},
+		{
+			input:               `func greet(s string) int { }`,
+			expectedName:        "greet",
+			expectedParamValues: []string{"s"},
+			expectedParamTypes:  []string{"string"},
+			expectedResult:      "int",
+		},
+		{
+			input:               `func greet(s string) float { }`,
+			expectedName:        "greet",
+			expectedParamValues: []string{"s"},
+			expectedParamTypes:  []string{"string"},
+			expectedResult:      "float",
+		},
+		{
+			input:               `func greet(s string) string { }`,
+			expectedName:        "greet",
+			expectedParamValues: []string{"s"},
+			expectedParamTypes:  []string{"string"},
+			expectedResult:      "string",
+		},
+		{
+			input:               `func greet(s string) bool { }`,
+			expectedName:        "greet",
+			expectedParamValues: []string{"s"},
+			expectedParamTypes:  []string{"string"},
+			expectedResult:      "bool",
+		},
+		{
+			input:               `func greet(s string) human { }`,
+			expectedName:        "greet",
+			expectedParamValues: []string{"s"},
+			expectedParamTypes:  []string{"string"},
+			expectedResult:      "human",
+		},
+		{
+			input:               `func add(x int, y float) float { }`,
+			expectedName:        "add",
+			expectedParamValues: []string{"x", "y"},
+			expectedParamTypes:  []string{"int", "float"},
+			expectedResult:      "float",
+		},
+		{
+			input:               `func add(x, y int, z float) { }`,
+			expectedName:        "add",
+			expectedParamValues: []string{"x", "y", "z"},
+			expectedParamTypes:  []string{"int", "int", "float"},
+			expectedResult:      nil,
+		},
+		{
+			input:               `func add(int, float) int`,
+			expectedName:        "add",
+			expectedParamValues: []string{"_", "_"},
+			expectedParamTypes:  []string{"int", "float"},
+			expectedResult:      "int",
+		},
+		{
+			input:               `func apply(f func(int, int) int, x int) int { }`,
+			expectedName:        "apply",
+			expectedParamValues: []string{"f", "x"},
+			expectedParamTypes:  []string{"(", "int"},
+			expectedResult:      "int",
+		},
+	}
+
+	for i, tt := range tests {
+		t.Logf("test[%d]", i)
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserError(t, p)
+		checkProgramLength(t, program)
+
+		funcStmt, ok := program.Statements[0].(*ast.FuncStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not an *ast.FuncStatement. got=%T",
+				program.Statements[0])
+		}
+
+		if funcStmt.TokenLiteral() != "func" {
+			t.Fatalf("funcStmt.TokenLiteral not %q. got=%q", "func", funcStmt.TokenLiteral())
+		}
+
+		if funcStmt.Name.Value != tt.expectedName {
+			t.Fatalf("funcStmt.Name is not %q, got=%q", tt.expectedName, funcStmt.Name)
+		}
+
+		if len(funcStmt.Signature.Parameters) != len(tt.expectedParamValues) {
+			t.Fatalf(
+				"funcStmt.Parameters does not contain %d parameters. got=%d",
+				len(tt.expectedParamValues),
+				len(funcStmt.Signature.Parameters),
+			)
+		}
+
+		for i, param := range funcStmt.Signature.Parameters {
+			if param.Value != tt.expectedParamValues[i] {
+				t.Fatalf(
+					"funcStmt.Parameters[%d].Value is not %q, got=%q",
+					i,
+					tt.expectedParamValues[i],
+					param.Value,
+				)
+			}
+
+			if param.Tnode.TokenLiteral() != tt.expectedParamTypes[i] {
+				t.Fatalf(
+					"funcStmt.Parameters[%d].Tnode.TokenLiteral is not %q, got=%q",
+					i,
+					tt.expectedParamTypes[i],
+					param.Tnode.TokenLiteral(),
+				)
+			}
+		}
+
+		if funcStmt.Signature.Result == nil {
+			if tt.expectedResult == nil {
+				continue
+			}
+
+			t.Fatalf(
+				"funcStmt.Result is not %q, got=%q",
+				tt.expectedResult,
+				funcStmt.Signature.Result,
+			)
 		}
 
 		if funcStmt.Signature.Result.TokenLiteral() != tt.expectedResult {
@@ -662,7 +1649,7 @@ func TestFuncStatement(t *testing.T) {
 }
 
 func TestCallExpression(t *testing.T) {
-	input := "compile(2 + 2)"
+	input := "compile(2 + 2, x, 3 * 4)"
 
 	l := lexer.New(input)
 	p := New(l)
@@ -683,7 +1670,54 @@ func TestCallExpression(t *testing.T) {
 	}
 
 	testIdentifier(t, call.Function, "compile")
-	testInfixExpression(t, call.Argument, 2, "+", 2)
+
+	if len(call.Arguments) != 3 {
+		t.Fatalf("call.Arguments does not contain 3 arguments. got=%d", len(call.Arguments))
+	}
+
+	testInfixExpression(t, call.Arguments[0], 2, "+", 2)
+	testIdentifier(t, call.Arguments[1], "x")
+	testInfixExpression(t, call.Arguments[2], 3, "*", 4)
+}
+
+func TestPrefixExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		operator string
+		value    interface{}
+	}{
+		{"-5", "-", 5},
+		{"+5", "+", 5},
+		{"-x", "-", "x"},
+		{"!true", "!", true},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserError(t, p)
+
+		checkProgramLength(t, program)
+
+		exprStmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ExpressionStatement. got=%T",
+				program.Statements[0],
+			)
+		}
+
+		expr, ok := exprStmt.Expression.(*ast.PrefixExpression)
+		if !ok {
+			t.Fatalf("expr is not ast.PrefixExpression. got=%T", exprStmt.Expression)
+		}
+
+		if expr.Operator != tt.operator {
+			t.Fatalf("expr.Operator is not %q. got=%s", tt.operator, expr.Operator)
+		}
+
+		testLiteralExpression(t, expr.Right, tt.value)
+	}
 }
 
 func TestInfixExpressions(t *testing.T) {
@@ -702,6 +1736,18 @@ func TestInfixExpressions(t *testing.T) {
 		{"2 == 2", 2, "==", 2},
 		{"2 != 2", 2, "!=", 2},
 		{"2 != 2", 2, "!=", 2},
+		{"2 <= 2", 2, "<=", 2},
+		{"2 > 2", 2, ">", 2},
+		{"2 >= 2", 2, ">=", 2},
+		{"5 % 5", 5, "%", 5},
+		{"5 & 5", 5, "&", 5},
+		{"5 | 5", 5, "|", 5},
+		{"5 ^ 5", 5, "^", 5},
+		{"5 &^ 5", 5, "&^", 5},
+		{"5 << 5", 5, "<<", 5},
+		{"5 >> 5", 5, ">>", 5},
+		{"x && y", "x", "&&", "y"},
+		{"x || y", "x", "||", "y"},
 	}
 
 	for _, tt := range tests {
@@ -752,6 +1798,58 @@ func TestOperatorPrecedence(t *testing.T) {
 			input:    "1 < 1 == false",
 			expected: "((1 < 1) == false)",
 		},
+		{
+			input:    "1 >= 2 == 3 <= 4",
+			expected: "((1 >= 2) == (3 <= 4))",
+		},
+		{
+			input:    "1 + 2 > 3 * 4",
+			expected: "((1 + 2) > (3 * 4))",
+		},
+		{
+			input:    "1 + 2 << 3",
+			expected: "(1 + (2 << 3))",
+		},
+		{
+			input:    "a | b & c ^ d",
+			expected: "((a | (b & c)) ^ d)",
+		},
+		{
+			input:    "a % b &^ c >> d",
+			expected: "(((a % b) &^ c) >> d)",
+		},
+		{
+			input:    "a & 1 == 0",
+			expected: "((a & 1) == 0)",
+		},
+		{
+			input:    "-a * b",
+			expected: "((-a) * b)",
+		},
+		{
+			input:    "a - -b",
+			expected: "(a - (-b))",
+		},
+		{
+			input:    "a || b && c",
+			expected: "(a || (b && c))",
+		},
+		{
+			input:    "a && b || c && d",
+			expected: "((a && b) || (c && d))",
+		},
+		{
+			input:    "1 < 2 && x == y || !z",
+			expected: "(((1 < 2) && (x == y)) || (!z))",
+		},
+		{
+			input:    "!a == b",
+			expected: "((!a) == b)",
+		},
+		{
+			input:    "!(a && b)",
+			expected: "(!(a && b))",
+		},
 	}
 
 	for _, tt := range tests {
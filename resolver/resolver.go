@@ -5,6 +5,7 @@ import (
 
 	"github.com/Glorforidor/didactic_compiler/ast"
 	"github.com/Glorforidor/didactic_compiler/symbol"
+	"github.com/Glorforidor/didactic_compiler/token"
 )
 
 // funcPrototypes hold information about a function being a protype.
@@ -37,21 +38,54 @@ func Resolve(node ast.Node, symbolTable *symbol.Table) error {
 			return err
 		}
 	case *ast.VarStatement:
-		if err := Resolve(node.Value, symbolTable); err != nil {
-			return err
+		for _, v := range node.Values {
+			if err := Resolve(v, symbolTable); err != nil {
+				return err
+			}
 		}
-		if _, err := symbolTable.Define(node.Name.Value, node.Name.Tnode); err != nil {
-			return err
+
+		if node.Token.Type == token.Define {
+			if err := defineShortVar(node, symbolTable); err != nil {
+				return err
+			}
+			break
+		}
+
+		for _, n := range node.Names {
+			if _, err := symbolTable.Define(n.Value, n.Tnode); err != nil {
+				return err
+			}
+		}
+	case *ast.ConstStatement:
+		for _, spec := range node.Specs {
+			for _, v := range spec.Values {
+				if err := Resolve(v, symbolTable); err != nil {
+					return err
+				}
+			}
+			for _, n := range spec.Names {
+				if _, err := symbolTable.DefineConst(n.Value); err != nil {
+					return err
+				}
+			}
 		}
 	case *ast.TypeStatement:
 		if _, err := symbolTable.DefineType(node.Name.Value, node.Type); err != nil {
 			return err
 		}
 	case *ast.AssignStatement:
-		if err := Resolve(node.Name, symbolTable); err != nil {
-			return err
+		for _, n := range node.Names {
+			if err := Resolve(n, symbolTable); err != nil {
+				return err
+			}
 		}
-		if err := Resolve(node.Value, symbolTable); err != nil {
+		for _, v := range node.Values {
+			if err := Resolve(v, symbolTable); err != nil {
+				return err
+			}
+		}
+	case *ast.IncDecStatement:
+		if err := Resolve(node.Name, symbolTable); err != nil {
 			return err
 		}
 	case *ast.IfStatement:
@@ -69,51 +103,78 @@ func Resolve(node ast.Node, symbolTable *symbol.Table) error {
 		}
 	case *ast.ForStatement:
 		node.SymbolTable = symbol.NewEnclosedTable(symbolTable)
-		if err := Resolve(node.Init, node.SymbolTable); err != nil {
-			return err
+		if node.Init != nil {
+			if err := Resolve(node.Init, node.SymbolTable); err != nil {
+				return err
+			}
 		}
 
-		if err := Resolve(node.Condition, node.SymbolTable); err != nil {
-			return err
+		if node.Condition != nil {
+			if err := Resolve(node.Condition, node.SymbolTable); err != nil {
+				return err
+			}
 		}
 
-		if err := Resolve(node.Next, node.SymbolTable); err != nil {
-			return err
+		if node.Next != nil {
+			if err := Resolve(node.Next, node.SymbolTable); err != nil {
+				return err
+			}
 		}
 
 		if err := Resolve(node.Body, node.SymbolTable); err != nil {
 			return err
 		}
+	case *ast.SwitchStatement:
+		if node.Tag != nil {
+			if err := Resolve(node.Tag, symbolTable); err != nil {
+				return err
+			}
+		}
+
+		// The body of each case is a block of its own.
+		for _, c := range node.Cases {
+			for _, v := range c.Values {
+				if err := Resolve(v, symbolTable); err != nil {
+					return err
+				}
+			}
+
+			if err := Resolve(c.Body, symbolTable); err != nil {
+				return err
+			}
+		}
 	case *ast.FuncStatement:
-		_, ok := symbolTable.Resolve(node.Name.Value)
+		// A method is defined by its label, so methods of different types
+		// can have the same name.
+		name := node.Label()
+
+		_, ok := symbolTable.Resolve(name)
 		if !ok {
-			if _, err := symbolTable.DefineFunc(node.Name.Value, node.Signature); err != nil {
+			if _, err := symbolTable.DefineFunc(name, node.Signature); err != nil {
 				return err
 			}
-		} else if !funcPrototypes[node.Name.Value] {
-			return fmt.Errorf("resolver: function: %q already defined", node.Name.Value)
-		} else if node.Body == nil && funcPrototypes[node.Name.Value] {
-			return fmt.Errorf("resolver: function: %q already prototyped", node.Name.Value)
+		} else if !funcPrototypes[name] {
+			return fmt.Errorf("resolver: function: %q already defined", name)
+		} else if node.Body == nil && funcPrototypes[name] {
+			return fmt.Errorf("resolver: function: %q already prototyped", name)
 		}
 
-		funcPrototypes[node.Name.Value] = node.Body == nil
+		funcPrototypes[name] = node.Body == nil
 
 		node.SymbolTable = symbol.NewEnclosedTable(symbolTable)
 
-		if node.Signature.Parameter != nil {
-			s, ok := node.SymbolTable.Resolve(node.Signature.Parameter.Value)
-			if ok && s.Scope == symbol.TypeScope {
-				node.SymbolTable.DefineFuncParameter(
-					node.Signature.Parameter.Value,
-					s.Type,
-				)
-			} else {
-				// Allow the parameter to over shadow a global variable of same
-				// name.
-				node.SymbolTable.DefineFuncParameter(
-					node.Signature.Parameter.Value,
-					node.Signature.Parameter.Tnode,
-				)
+		// The receiver is passed as the first parameter.
+		if node.Receiver != nil {
+			if _, err := node.SymbolTable.DefineFuncParameter(node.Receiver.Value, node.Receiver.Tnode); err != nil {
+				return fmt.Errorf("resolver: function: %q: %w", name, err)
+			}
+		}
+
+		// Allow the parameters to over shadow a global variable of same
+		// name.
+		for _, param := range node.Signature.Parameters {
+			if _, err := node.SymbolTable.DefineFuncParameter(param.Value, param.Tnode); err != nil {
+				return fmt.Errorf("resolver: function: %q: %w", name, err)
 			}
 		}
 
@@ -122,23 +183,45 @@ func Resolve(node ast.Node, symbolTable *symbol.Table) error {
 				return err
 			}
 		}
-	case *ast.ReturnStatement:
-		if err := Resolve(node.Value, symbolTable); err != nil {
+	case *ast.FuncLiteral:
+		// The variables of the enclosing functions used by the function
+		// literal are captured by its table.
+		node.SymbolTable = symbol.NewClosureTable(symbolTable)
+
+		for _, param := range node.Signature.Parameters {
+			if _, err := node.SymbolTable.DefineFuncParameter(param.Value, param.Tnode); err != nil {
+				return fmt.Errorf("resolver: function literal: %w", err)
+			}
+		}
+
+		if err := Resolve(node.Body, node.SymbolTable); err != nil {
 			return err
 		}
+	case *ast.ReturnStatement:
+		for _, v := range node.Values {
+			if err := Resolve(v, symbolTable); err != nil {
+				return err
+			}
+		}
 	case *ast.CallExpression:
 		if err := Resolve(node.Function, symbolTable); err != nil {
 			return err
 		}
 
-		if err := Resolve(node.Argument, symbolTable); err != nil {
-			return err
+		for _, a := range node.Arguments {
+			if err := Resolve(a, symbolTable); err != nil {
+				return err
+			}
 		}
 	case *ast.Identifier:
 		_, ok := symbolTable.Resolve(node.Value)
 		if !ok {
 			return fmt.Errorf("resolver: identifier: %q is not defined", node.Value)
 		}
+	case *ast.PrefixExpression:
+		if err := Resolve(node.Right, symbolTable); err != nil {
+			return err
+		}
 	case *ast.InfixExpression:
 		if err := Resolve(node.Left, symbolTable); err != nil {
 			return err
@@ -146,10 +229,81 @@ func Resolve(node ast.Node, symbolTable *symbol.Table) error {
 		if err := Resolve(node.Right, symbolTable); err != nil {
 			return err
 		}
+	case *ast.IndexExpression:
+		if err := Resolve(node.Left, symbolTable); err != nil {
+			return err
+		}
+		if err := Resolve(node.Index, symbolTable); err != nil {
+			return err
+		}
+	case *ast.SliceExpression:
+		if err := Resolve(node.Left, symbolTable); err != nil {
+			return err
+		}
+		if node.Low != nil {
+			if err := Resolve(node.Low, symbolTable); err != nil {
+				return err
+			}
+		}
+		if node.High != nil {
+			if err := Resolve(node.High, symbolTable); err != nil {
+				return err
+			}
+		}
+	case *ast.BuiltinExpression:
+		for _, a := range node.Arguments {
+			if err := Resolve(a, symbolTable); err != nil {
+				return err
+			}
+		}
+	case *ast.ConversionExpression:
+		if err := Resolve(node.Value, symbolTable); err != nil {
+			return err
+		}
 	case *ast.SelectorExpression:
 		if err := Resolve(node.X, symbolTable); err != nil {
 			return err
 		}
+	case *ast.CompositeLiteral:
+		// The keys are the fields of the struct, which the checker resolves.
+		for _, v := range node.Values {
+			if err := Resolve(v, symbolTable); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// defineShortVar defines the names of a short variable declaration. A name
+// which is already declared in the same scope is redeclared, which only
+// assigns it, but at least one of the names must be new.
+func defineShortVar(node *ast.VarStatement, symbolTable *symbol.Table) error {
+	node.Redeclared = make([]bool, len(node.Names))
+	seen := map[string]bool{}
+	isNew := false
+
+	for i, n := range node.Names {
+		if seen[n.Value] {
+			return fmt.Errorf("resolver: identifier: %q repeated on left side of :=", n.Value)
+		}
+		seen[n.Value] = true
+
+		s, ok := symbolTable.Lookup(n.Value)
+		if ok && (s.Scope == symbol.GlobalScope || s.Scope == symbol.LocalScope) {
+			node.Redeclared[i] = true
+			continue
+		}
+
+		if _, err := symbolTable.Define(n.Value, n.Tnode); err != nil {
+			return err
+		}
+		isNew = true
+	}
+
+	if !isNew {
+		return fmt.Errorf("resolver: no new variables on left side of :=")
 	}
 
 	return nil
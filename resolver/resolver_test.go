@@ -193,6 +193,25 @@ x = 2`,
 			}`,
 			expectedToErr: true,
 		},
+		{
+			input: `
+			func test(x int, y float, z int) {
+				print y
+			}`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			func test(x int, x float) {
+				print x
+			}`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			func test(int, int)`,
+			expectedToErr: false,
+		},
 		{
 			input: `
 			func test(x int) int {
@@ -225,6 +244,113 @@ x = 2`,
 			x.name`,
 			expectedToErr: false,
 		},
+		{
+			input: `
+			const ( a = iota; b )
+			print a + b`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			const a = 1
+			const a = 2`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			const a = b
+			const b = 1`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			const a = 1
+			func test() {
+				const a = 2
+			}`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			var x int
+			switch x {
+			case 1:
+				var y int
+			case 2:
+				var y float
+			}`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			var x int
+			switch x {
+			case 1:
+				var y int
+			case 2:
+				y = 2
+			}`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			switch y {
+			}`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			x := 1
+			for i := 0; i < x; i = i + 1 {
+				print i
+			}`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			x := x`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			x := 1
+			x := 2`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			x, y := 1, 2
+			y, z := 3, 4`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			x, y := 1, 2
+			y, x := 3, 4`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			x, x := 1, 2`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			x := 1
+			x += 2
+			x++`,
+			expectedToErr: false,
+		},
+		{
+			input: `
+			y++`,
+			expectedToErr: true,
+		},
+		{
+			input: `
+			y -= 1`,
+			expectedToErr: true,
+		},
 	}
 
 	for i, tt := range tests {
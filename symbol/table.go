@@ -3,6 +3,8 @@ package symbol
 import (
 	"fmt"
 	"sort"
+
+	"github.com/Glorforidor/didactic_compiler/types"
 )
 
 type SymbolScope int
@@ -12,6 +14,13 @@ const (
 	FuncScope
 	LocalScope
 	TypeScope
+	// FreeScope is the scope of a variable of an enclosing function used by
+	// a function literal. The variable is captured by the closure of the
+	// function literal.
+	FreeScope
+	// ConstScope is the scope of a constant. A constant does not reside in
+	// memory, as its value is known by the checker.
+	ConstScope
 )
 
 type Symbol struct {
@@ -21,6 +30,19 @@ type Symbol struct {
 	which       int         // The ordinal position of variable (local or param)
 	stackPoint  int         // Where the symbol resides on the stack.
 	stackOffset int         // stackOffset is used for referencing variables from the previous scope
+
+	// Captured is set if the local variable is used by a function literal,
+	// or if its address is taken. The variable then lives on the heap, where
+	// both the function and the closure of the function literal or the
+	// pointer can reach it, and the stack only holds its address.
+	Captured bool
+	// Variable is the variable of the enclosing function, which a free
+	// variable captures.
+	Variable *Symbol
+
+	// Value is the value of a constant, which is given by the checker. It is
+	// either an int64, a float64, a string or a bool.
+	Value interface{}
 }
 
 func (s *Symbol) Code() interface{} {
@@ -32,17 +54,39 @@ func (s *Symbol) Code() interface{} {
 		return s.Name
 	case LocalScope:
 		return s.stackOffset + s.stackPoint
+	case FreeScope:
+		// A free variable is reached through the closure, whose address is
+		// on the stack.
+		return s.stackOffset + s.stackPoint
 	default:
 		panic("Symbol did not have a scope!")
 	}
 }
 
+// FreeIndex returns the index of the free variable in the closure.
+func (s *Symbol) FreeIndex() int {
+	return s.which
+}
+
 type Table struct {
 	Outer *Table
 	store map[string]*Symbol
 
+	// parameters holds the function parameters in the order they were
+	// defined. Unlike store it also holds every blank '_' parameter.
+	parameters []*Symbol
+
 	numDefinitions int
 	stackSpace     int
+
+	// closure is set for the table of a function literal, which captures
+	// the variables it uses from the enclosing functions.
+	closure bool
+	// free holds the free variables of a function literal in the order they
+	// were captured.
+	free []*Symbol
+	// closurePoint is where the address of the closure resides on the stack.
+	closurePoint int
 }
 
 func (st *Table) String() string {
@@ -61,6 +105,13 @@ func NewEnclosedTable(outer *Table) *Table {
 	return s
 }
 
+// NewClosureTable creates the table of a function literal enclosed by outer.
+func NewClosureTable(outer *Table) *Table {
+	s := NewEnclosedTable(outer)
+	s.closure = true
+	return s
+}
+
 func (st *Table) DefineType(name string, t interface{}) (*Symbol, error) {
 	if s, ok := st.store[name]; ok {
 		// TODO: better error message - what scope? maybe just say the variable
@@ -88,13 +139,75 @@ func (st *Table) DefineFunc(name string, t interface{}) (*Symbol, error) {
 }
 
 // DefineFuncParameter is used for defining the function parameter. Which will always
-// have the local scope.
-func (st *Table) DefineFuncParameter(name string, t interface{}) *Symbol {
+// have the local scope. Blank '_' parameters may be defined more than once.
+func (st *Table) DefineFuncParameter(name string, t interface{}) (*Symbol, error) {
+	if s, ok := st.store[name]; ok && name != "_" {
+		return s, fmt.Errorf("identifier: %q duplicate parameter", name)
+	}
+
 	s := &Symbol{Name: name, Type: t, which: st.numDefinitions, Scope: LocalScope}
 	st.store[name] = s
+	st.parameters = append(st.parameters, s)
 	st.numDefinitions++
 
-	return s
+	return s, nil
+}
+
+// Parameters returns the function parameters in the order they were defined.
+func (st *Table) Parameters() []*Symbol {
+	return st.parameters
+}
+
+// Free returns the free variables of a function literal in the order they
+// were captured.
+func (st *Table) Free() []*Symbol {
+	return st.free
+}
+
+// Closure returns where the address of the closure of a function literal
+// resides on the stack. It returns false if the table is not of a function
+// literal.
+func (st *Table) Closure() (int, bool) {
+	return st.closurePoint, st.closure
+}
+
+// capture defines the local variable s of an enclosing function as a free
+// variable of the function literal.
+func (st *Table) capture(s *Symbol, stackOffset int) *Symbol {
+	s.Captured = true
+
+	f := &Symbol{
+		Name:        s.Name,
+		Type:        s.Type,
+		Scope:       FreeScope,
+		Variable:    s,
+		which:       len(st.free),
+		stackPoint:  st.closurePoint,
+		stackOffset: stackOffset,
+	}
+	st.store[s.Name] = f
+	st.free = append(st.free, f)
+
+	return f
+}
+
+// DefineConst defines the constant name into the symbol table. Like a
+// variable, the constant may not over shadow a symbol with the same name.
+func (st *Table) DefineConst(name string) (*Symbol, error) {
+	if s, ok := st.store[name]; ok {
+		return s, fmt.Errorf("identifier: %q already defined in scope", name)
+	}
+
+	if st.Outer != nil {
+		if s, ok := st.Resolve(name); ok {
+			return s, fmt.Errorf("identifier: %q would over shadow existing identfier", name)
+		}
+	}
+
+	s := &Symbol{Name: name, Scope: ConstScope}
+	st.store[name] = s
+
+	return s, nil
 }
 
 // Define defines the name with type t into the symbol table. It will check
@@ -122,44 +235,97 @@ func (st *Table) Define(name string, t interface{}) (*Symbol, error) {
 	return s, nil
 }
 
+// variablesSize is the byte size of a variable. As we target RV64 then it is 8
+// bytes.
+const variableSize = 8
+
 // ComputeStack computes how much stack space a block will accomendate and save
 // the computation in the symbol table. It returns the computed stack space.
 func (st *Table) ComputeStack() int {
-	keys := make([]string, 0, len(st.store))
-	for k := range st.store {
-		keys = append(keys, k)
+	x := st.layout()
+
+	// The stack space always need to be 16 byte alligned. We subtract 8 from x
+	// since we add that extra 8 as offset.
+	if (x-variableSize)%16 == 0 {
+		st.stackSpace = x - variableSize
+	} else {
+		st.stackSpace = x
 	}
-	sort.Slice(keys, func(i, j int) bool {
-		return st.store[keys[i]].which < st.store[keys[j]].which
-	})
 
-	// variablesSize is the byte size of a variable. As we target RV64 then it is 8
-	// bytes.
-	const variableSize = 8
+	return st.stackSpace
+}
+
+// ComputeFrame computes how much stack space a function frame will
+// accomendate and save the computation in the symbol table. Unlike
+// ComputeStack, the top of the frame is never given to a symbol as that is
+// where the return address is saved. It returns the computed stack space.
+func (st *Table) ComputeFrame() int {
+	x := st.layout()
+
+	// Round up to the nearest multiple of 16, so the return address can be
+	// saved at the top of the frame.
+	st.stackSpace = (x + 15) / 16 * 16
+
+	return st.stackSpace
+}
+
+// layout gives each symbol a position on the stack and returns the position
+// right after the last symbol.
+func (st *Table) layout() int {
+	symbols := make([]*Symbol, 0, len(st.store))
+	for _, v := range st.store {
+		symbols = append(symbols, v)
+	}
+	for _, v := range st.parameters {
+		if st.store[v.Name] != v {
+			// Blank parameters which have been shadowed by another blank
+			// parameter.
+			symbols = append(symbols, v)
+		}
+	}
+	sort.Slice(symbols, func(i, j int) bool {
+		return symbols[i].which < symbols[j].which
+	})
 
 	// The first element on the stack is always at 8(sp). Writing to 0(sp)
 	// should always be safe and should never overwrite other data.
 	x := variableSize
 
-	for _, k := range keys {
-		v := st.store[k]
-		if v.Scope == TypeScope {
+	for _, v := range symbols {
+		if v.Scope == TypeScope || v.Scope == FreeScope || v.Scope == ConstScope {
 			continue
 		}
 
 		v.stackPoint = x
-		x += variableSize
+		v.stackOffset = 0
+
+		// Arrays are stored in place and may take up more than a single
+		// variable.
+		if t, ok := v.Type.(types.Type); ok {
+			x += types.Sizeof(t)
+		} else {
+			x += variableSize
+		}
 	}
 
-	// The stack space always need to be 16 byte alligned. We subtract 8 from x
-	// since we add that extra 8 as offset.
-	if (x-variableSize)%16 == 0 {
-		st.stackSpace = x - variableSize
-	} else {
-		st.stackSpace = x
+	// The address of the closure is saved right after the other symbols.
+	if st.closure {
+		st.closurePoint = x
+		for _, f := range st.free {
+			f.stackPoint = x
+		}
+
+		x += variableSize
 	}
 
-	return st.stackSpace
+	return x
+}
+
+// Lookup returns the symbol of the name defined in the table itself, without
+// looking in the enclosing tables.
+func (st *Table) Lookup(name string) (*Symbol, bool) {
+	s, ok := st.store[name]
+	return s, ok
 }
 
 func (st *Table) Resolve(name string) (*Symbol, bool) {
@@ -170,6 +336,13 @@ func (st *Table) resolve(name string, stackOffset int) (*Symbol, bool) {
 	s, ok := st.store[name]
 	if !ok && st.Outer != nil {
 		s, ok := st.Outer.resolve(name, stackOffset+st.stackSpace)
+
+		// A variable of an enclosing function is not on the stack of a
+		// function literal, so it is captured instead.
+		if ok && st.closure && (s.Scope == LocalScope || s.Scope == FreeScope) {
+			return st.capture(s, stackOffset), true
+		}
+
 		return s, ok
 	}
 
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/Glorforidor/didactic_compiler/token"
+	"github.com/Glorforidor/didactic_compiler/types"
 )
 
 func TestDefine(t *testing.T) {
@@ -151,3 +152,74 @@ func TestCode(t *testing.T) {
 		}
 	}
 }
+
+func TestComputeStackWithArrays(t *testing.T) {
+	global := NewTable()
+	local := NewEnclosedTable(global)
+	local.Define("a", types.Typ[types.Int])
+	local.Define("b", &types.Array{Len: 3, Elem: types.Typ[types.Int]})
+	local.Define("c", types.Typ[types.Float])
+
+	if space := local.ComputeStack(); space != 48 {
+		t.Fatalf("wrong stack space. expected=%d, got=%d", 48, space)
+	}
+
+	expected := map[string]int{"a": 8, "b": 16, "c": 40}
+	for name, code := range expected {
+		sym, _ := local.Resolve(name)
+		if sym.Code() != code {
+			t.Errorf("symbol %s had wrong code. expected=%d, got=%v", name, code, sym.Code())
+		}
+	}
+}
+
+func TestResolveFree(t *testing.T) {
+	global := NewTable()
+	global.Define("a", token.IntType)
+
+	local := NewEnclosedTable(global)
+	local.Define("b", token.IntType)
+	local.Define("c", token.IntType)
+
+	literal := NewClosureTable(local)
+	literal.Define("d", token.IntType)
+
+	inner := NewClosureTable(literal)
+
+	expected := []*Symbol{
+		{Name: "a", Scope: GlobalScope, Type: token.IntType, which: 0},
+		{Name: "c", Scope: FreeScope, Type: token.IntType, which: 0},
+		{Name: "d", Scope: FreeScope, Type: token.IntType, which: 1},
+		{Name: "b", Scope: FreeScope, Type: token.IntType, which: 2},
+	}
+
+	for _, sym := range expected {
+		result, ok := inner.Resolve(sym.Name)
+		if !ok {
+			t.Errorf("name %s not resolvable", sym.Name)
+			continue
+		}
+
+		// A free variable links to the variable it captures.
+		if sym.Scope == FreeScope && (result.Variable == nil || result.Variable.Name != sym.Name) {
+			t.Errorf("free variable %s does not capture a variable. got=%+v", sym.Name, result.Variable)
+		}
+
+		got := *result
+		got.Variable = nil
+		if got != *sym {
+			t.Errorf("expected %s to resolve to %+v, got=%+v", sym.Name, sym, result)
+		}
+	}
+
+	for _, name := range []string{"b", "c"} {
+		s, _ := local.Resolve(name)
+		if !s.Captured {
+			t.Errorf("expected %s to be captured", name)
+		}
+	}
+
+	if len(literal.Free()) != 2 {
+		t.Errorf("wrong number of free variables in literal. expected=2, got=%d", len(literal.Free()))
+	}
+}
@@ -19,45 +19,99 @@ const (
 	String TokenType = "STRING" // "hello world"
 
 	// Operators
-	Plus     TokenType = "+"
-	Minus    TokenType = "-"
-	Asterisk TokenType = "*"
-	Slash    TokenType = "/"
-	Assign   TokenType = "="
+	Plus      TokenType = "+"
+	Minus     TokenType = "-"
+	Asterisk  TokenType = "*"
+	Slash     TokenType = "/"
+	Percent   TokenType = "%"
+	Assign    TokenType = "="
+	Define    TokenType = ":="
+	Ampersand TokenType = "&"
+	Bang      TokenType = "!"
+
+	// Bitwise operators
+	Pipe       TokenType = "|"
+	Caret      TokenType = "^"
+	AndNot     TokenType = "&^"
+	ShiftLeft  TokenType = "<<"
+	ShiftRight TokenType = ">>"
 
 	// Grouping
-	Lparen TokenType = "("
-	Rparen TokenType = ")"
-	Lbrace TokenType = "{"
-	Rbrace TokenType = "}"
+	Lparen   TokenType = "("
+	Rparen   TokenType = ")"
+	Lbrace   TokenType = "{"
+	Rbrace   TokenType = "}"
+	Lbracket TokenType = "["
+	Rbracket TokenType = "]"
 
 	// Selector
 	Period TokenType = "."
 
 	// Delimiters
 	Semicolon TokenType = ";"
+	Comma     TokenType = ","
+	Colon     TokenType = ":"
+
+	// Assignment operators
+	PlusAssign       TokenType = "+="
+	MinusAssign      TokenType = "-="
+	AsteriskAssign   TokenType = "*="
+	SlashAssign      TokenType = "/="
+	PercentAssign    TokenType = "%="
+	AmpersandAssign  TokenType = "&="
+	PipeAssign       TokenType = "|="
+	CaretAssign      TokenType = "^="
+	AndNotAssign     TokenType = "&^="
+	ShiftLeftAssign  TokenType = "<<="
+	ShiftRightAssign TokenType = ">>="
+	Increment        TokenType = "++"
+	Decrement        TokenType = "--"
 
 	// Comparison operators
-	Equal    TokenType = "=="
-	NotEqual TokenType = "!="
-	LessThan TokenType = "<"
+	Equal        TokenType = "=="
+	NotEqual     TokenType = "!="
+	LessThan     TokenType = "<"
+	LessEqual    TokenType = "<="
+	GreaterThan  TokenType = ">"
+	GreaterEqual TokenType = ">="
+
+	// Logical operators
+	And TokenType = "&&"
+	Or  TokenType = "||"
 
 	// Keywords
 	Print      TokenType = "PRINT"
 	Var        TokenType = "VAR"
+	Const      TokenType = "CONST"
 	Type       TokenType = "TYPE"
 	For        TokenType = "FOR"
+	Break      TokenType = "BREAK"
+	Continue   TokenType = "CONTINUE"
+	Switch     TokenType = "SWITCH"
+	Case       TokenType = "CASE"
+	Default    TokenType = "DEFAULT"
 	If         TokenType = "IF"
 	Else       TokenType = "ELSE"
 	Func       TokenType = "FUNC"
 	Return     TokenType = "RETURN"
 	Struct     TokenType = "STRUCT"
+	Interface  TokenType = "INTERFACE"
 	IntType    TokenType = "INT_TYPE"
 	FloatType  TokenType = "FLOAT_TYPE"
 	StringType TokenType = "STRING_TYPE"
 	BoolType   TokenType = "BOOL_TYPE"
+	ByteType   TokenType = "BYTE_TYPE"
 	True       TokenType = "TRUE"
 	False      TokenType = "FALSE"
+	Nil        TokenType = "NIL"
+	Iota       TokenType = "IOTA"
+
+	// Builtin functions
+	Make   TokenType = "MAKE"
+	Append TokenType = "APPEND"
+	Len    TokenType = "LEN"
+	Cap    TokenType = "CAP"
+	New    TokenType = "NEW"
 )
 
 // NOTE: could add pos and end to token so error messages later could add
@@ -76,21 +130,36 @@ type Position struct {
 }
 
 var keywords = map[string]TokenType{
-	"print":  Print,
-	"var":    Var,
-	"type":   Type,
-	"for":    For,
-	"if":     If,
-	"else":   Else,
-	"func":   Func,
-	"return": Return,
-	"struct": Struct,
-	"int":    IntType,
-	"float":  FloatType,
-	"string": StringType,
-	"bool":   BoolType,
-	"true":   True,
-	"false":  False,
+	"print":     Print,
+	"var":       Var,
+	"const":     Const,
+	"type":      Type,
+	"for":       For,
+	"break":     Break,
+	"continue":  Continue,
+	"switch":    Switch,
+	"case":      Case,
+	"default":   Default,
+	"if":        If,
+	"else":      Else,
+	"func":      Func,
+	"return":    Return,
+	"struct":    Struct,
+	"interface": Interface,
+	"int":       IntType,
+	"float":     FloatType,
+	"string":    StringType,
+	"bool":      BoolType,
+	"byte":      ByteType,
+	"true":      True,
+	"false":     False,
+	"nil":       Nil,
+	"iota":      Iota,
+	"make":      Make,
+	"append":    Append,
+	"len":       Len,
+	"cap":       Cap,
+	"new":       New,
 }
 
 // LookupIdentifier checks if the identifier is a keyword, and if so returns
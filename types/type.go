@@ -1,6 +1,7 @@
 package types
 
 import (
+	"strconv"
 	"strings"
 )
 
@@ -13,8 +14,17 @@ const (
 	Float
 	String
 	Bool
+	Byte
 	StructKind
 	Func
+	TupleKind
+	ArrayKind
+	SliceKind
+	PointerKind
+	InterfaceKind
+	UntypedNil
+	UntypedInt
+	UntypedFloat
 )
 
 type Type interface {
@@ -25,11 +35,30 @@ type Type interface {
 type Basic struct {
 	kind kind
 	name string
+	// Methods is the method set of a basic type named by a type statement.
+	Methods []*Method
 }
 
 func (b *Basic) Kind() kind     { return b.kind }
 func (b *Basic) String() string { return b.name }
 
+// Method returns the method with the name in the method set of the basic type.
+func (b *Basic) Method(name string) (*Method, bool) {
+	for _, m := range b.Methods {
+		if m.Name == name {
+			return m, true
+		}
+	}
+
+	return nil, false
+}
+
+// Named returns a new type with the name given by a type statement, which has
+// the same kind as the basic type.
+func (b *Basic) Named(name string) *Basic {
+	return &Basic{kind: b.kind, name: name}
+}
+
 var Typ = []*Basic{
 	Unknown: {kind: Unknown, name: "unknown"},
 	Nil:     {kind: Nil, name: "nil"},
@@ -37,30 +66,143 @@ var Typ = []*Basic{
 	Float:   {kind: Float, name: "float"},
 	String:  {kind: String, name: "string"},
 	Bool:    {kind: Bool, name: "bool"},
+	Byte:    {kind: Byte, name: "byte"},
+
+	// UntypedNil is the type of the nil literal.
+	UntypedNil: {kind: UntypedNil, name: "untyped nil"},
+
+	// UntypedInt and UntypedFloat are the types of the constants declared
+	// without a type.
+	UntypedInt:   {kind: UntypedInt, name: "untyped int"},
+	UntypedFloat: {kind: UntypedFloat, name: "untyped float"},
 }
 
 type Signature struct {
-	Parameter Type
-	Result    Type
+	Parameters []Type
+	Result     Type
 }
 
 func (s *Signature) Kind() kind { return Func }
 func (s *Signature) String() string {
 	var sb strings.Builder
 
-	sb.WriteString("(")
-	if s.Parameter != nil {
-		sb.WriteString(s.Parameter.String())
+	sb.WriteString("func(")
+	for i, p := range s.Parameters {
+		sb.WriteString(p.String())
+
+		if i == len(s.Parameters)-1 {
+			break
+		}
+
+		sb.WriteString(", ")
 	}
-	sb.WriteString(",")
-	if s.Result != nil {
+	sb.WriteString(")")
+	if s.Result != nil && s.Result.Kind() != Nil {
+		sb.WriteString(" ")
 		sb.WriteString(s.Result.String())
 	}
+
+	return sb.String()
+}
+
+// Tuple is the type of the results of a function with multiple results.
+type Tuple struct {
+	Types []Type
+}
+
+func (t *Tuple) Kind() kind { return TupleKind }
+func (t *Tuple) String() string {
+	var sb strings.Builder
+
+	sb.WriteString("(")
+	for i, typ := range t.Types {
+		sb.WriteString(typ.String())
+
+		if i == len(t.Types)-1 {
+			break
+		}
+
+		sb.WriteString(", ")
+	}
 	sb.WriteString(")")
 
 	return sb.String()
 }
 
+// Values returns the types of the values a type consists of. A tuple consists
+// of its types, nil of no types, and every other type of just itself.
+func Values(t Type) []Type {
+	switch t := t.(type) {
+	case *Tuple:
+		return t.Types
+	default:
+		if t.Kind() == Nil {
+			return nil
+		}
+
+		return []Type{t}
+	}
+}
+
+type Array struct {
+	Len  int
+	Elem Type
+}
+
+func (a *Array) Kind() kind { return ArrayKind }
+func (a *Array) String() string {
+	var sb strings.Builder
+
+	sb.WriteString("[")
+	sb.WriteString(strconv.Itoa(a.Len))
+	sb.WriteString("]")
+	sb.WriteString(a.Elem.String())
+
+	return sb.String()
+}
+
+// Size returns the byte size of the array, which holds its elements right
+// after each other.
+func (a *Array) Size() int {
+	return a.Len * Sizeof(a.Elem)
+}
+
+type Slice struct {
+	Elem Type
+}
+
+func (s *Slice) Kind() kind { return SliceKind }
+func (s *Slice) String() string {
+	return "[]" + s.Elem.String()
+}
+
+type Pointer struct {
+	Elem Type
+}
+
+func (p *Pointer) Kind() kind { return PointerKind }
+func (p *Pointer) String() string {
+	// A pointer to a struct is written with the name of the struct, as the
+	// struct may point to itself.
+	if s, ok := p.Elem.(*Struct); ok && s.Name != "" {
+		return "*" + s.Name
+	}
+
+	return "*" + p.Elem.String()
+}
+
+// Sizeof returns the byte size of a variable of type t. Arrays are stored in
+// place while every other type fits in a word of 8 bytes, e.g. a struct
+// variable only holds the address of the struct.
+func Sizeof(t Type) int {
+	switch t := t.(type) {
+	case *Array:
+		return t.Size()
+	default:
+		return 8
+	}
+}
+
 type Field struct {
 	Name string
 	Type Type
@@ -76,8 +218,20 @@ func (f *Field) String() string {
 	return sb.String()
 }
 
+// Method is a function declared with a receiver of a named type.
+type Method struct {
+	Name      string
+	Signature *Signature // The signature without the receiver.
+	// PointerReceiver is set if the receiver is a pointer to the type.
+	PointerReceiver bool
+}
+
 type Struct struct {
+	// Name is the name given to the struct by the type statement.
+	Name   string
 	Fields []*Field
+	// Methods is the method set of the named struct.
+	Methods []*Method
 }
 
 func (s *Struct) Kind() kind { return StructKind }
@@ -99,11 +253,67 @@ func (s *Struct) String() string {
 	return sb.String()
 }
 
+// Method returns the method with the name in the method set of the struct.
+func (s *Struct) Method(name string) (*Method, bool) {
+	for _, m := range s.Methods {
+		if m.Name == name {
+			return m, true
+		}
+	}
+
+	return nil, false
+}
+
+// Size returns the byte size of the struct, which holds its fields right after
+// each other.
 func (s *Struct) Size() int {
 	var space int
-	for range s.Fields {
-		space += 8
+	for _, f := range s.Fields {
+		space += fieldSize(f.Type)
 	}
 
 	return space
 }
+
+// Offset returns the byte offset of the i'th field in the struct.
+func (s *Struct) Offset(i int) int {
+	var offset int
+	for _, f := range s.Fields[:i] {
+		offset += fieldSize(f.Type)
+	}
+
+	return offset
+}
+
+// fieldSize returns the byte size of a struct field of type t. Unlike a
+// variable, a field of struct type holds the whole struct in place.
+func fieldSize(t Type) int {
+	switch t := t.(type) {
+	case *Struct:
+		return t.Size()
+	default:
+		return Sizeof(t)
+	}
+}
+
+// Interface is a set of methods. Any type with all the methods of the
+// interface implements it.
+type Interface struct {
+	// Name is the name given to the interface by the type statement.
+	Name    string
+	Methods []*Method
+}
+
+func (i *Interface) Kind() kind     { return InterfaceKind }
+func (i *Interface) String() string { return i.Name }
+
+// Method returns the method with the name in the method set of the interface.
+func (i *Interface) Method(name string) (*Method, bool) {
+	for _, m := range i.Methods {
+		if m.Name == name {
+			return m, true
+		}
+	}
+
+	return nil, false
+}